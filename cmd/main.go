@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"crypto/rsa"
+	"net"
+	"os"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -9,9 +12,13 @@ import (
 
 	"github.com/Rolan335/project/config"
 	"github.com/Rolan335/project/internal/app"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/broadcast"
 	"github.com/Rolan335/project/internal/cache"
+	"github.com/Rolan335/project/internal/grpcserver"
 	"github.com/Rolan335/project/internal/handler"
 	"github.com/Rolan335/project/internal/metric"
+	"github.com/Rolan335/project/internal/ratelimit"
 	"github.com/Rolan335/project/internal/repository"
 	"github.com/Rolan335/project/internal/storage/pgconn"
 	"github.com/Rolan335/project/internal/tracer"
@@ -56,19 +63,57 @@ func main() {
 	realocInterval := time.Minute
 	cache.GoPollDeletion(ctx, deleteInterval, realocInterval)
 
-	blog := usecase.NewBlogProvider(cache)
+	blog := usecase.NewBlogProvider(cache, broadcast.New(cfg.Events.ReplaySize), cache)
+	comments := usecase.NewCommentProvider(cache.Comments(repository.NewCommentRepo(conn)))
+	users := usecase.NewUserProvider(repository.NewUserRepo(conn))
+	keys := usecase.NewAPIKeyProvider(repository.NewAPIKeyRepo(conn))
+	idempotency := usecase.NewIdempotencyProvider(repository.NewIdempotencyRepo(conn), cfg.Idempotency.TTL)
+	idempotency.GoPurgeExpired(ctx, cfg.Idempotency.PurgeInterval)
+	blog.GoPurgeTrash(ctx, cfg.Trash.PurgeInterval, cfg.Trash.PurgeAfter)
+	blog.GoPublishScheduled(ctx, cfg.Publisher.Interval, cfg.Publisher.BatchSize)
+	webhooks := usecase.NewWebhookProvider(repository.NewWebhookRepo(conn), cfg.Webhooks.Timeout, usecase.WebhookRetry{
+		MaxAttempts: cfg.Webhooks.MaxAttempts,
+		Backoff:     cfg.Webhooks.Backoff,
+		MaxBackoff:  cfg.Webhooks.MaxBackoff,
+	})
+	webhooks.GoDeliver(ctx, cfg.Webhooks.Interval, cfg.Webhooks.BatchSize)
+	relay := usecase.NewOutboxRelay(repository.NewOutboxRepo(conn), usecase.LogSink{}, webhooks)
+	relay.GoRelay(ctx, cfg.Outbox.Interval, cfg.Outbox.BatchSize, cfg.Outbox.Retention)
 
 	metric.MustRegisterMetrics()
 	pollInterval := 10 * time.Second
 	metric.GoCountCacheLen(ctx, pollInterval, cache)
 
 	validate := validator.New()
-	handle := handler.New(blog, validate)
+	handle := handler.New(blog, comments, users, keys, webhooks, validate)
 
-	apiEndpoint := app.GetRouter(handle)
+	verifier, err := newVerifier(cfg.Auth)
+	if err != nil {
+		log.Panic().Err(err).Msg("")
+	}
+	ipLimiter := ratelimit.New(cfg.RateLimit.IP.Rate, cfg.RateLimit.IP.Burst)
+	ipLimiter.GoPollDeletion(ctx, cfg.RateLimit.CleanupInterval)
+	readLimiter := ratelimit.New(cfg.RateLimit.Read.Rate, cfg.RateLimit.Read.Burst)
+	readLimiter.GoPollDeletion(ctx, cfg.RateLimit.CleanupInterval)
+	writeLimiter := ratelimit.New(cfg.RateLimit.Write.Rate, cfg.RateLimit.Write.Burst)
+	writeLimiter.GoPollDeletion(ctx, cfg.RateLimit.CleanupInterval)
+
+	apiEndpoint := app.GetRouter(handle, verifier, keys, ipLimiter, readLimiter, writeLimiter, idempotency, cfg.Feed.CacheTTL, cfg.Events.Heartbeat, cfg.Live.MaxSubscriptions, cfg.Export.Timeout, cfg.Export.MaxConcurrent)
 
 	metricEndpoint := app.GetMetricsRouter()
 
+	grpcEndpoint := grpcserver.NewGRPCServer(grpcserver.New(blog, validate), verifier, keys, ipLimiter, readLimiter, writeLimiter)
+	defer grpcEndpoint.GracefulStop()
+	grpcListener, err := net.Listen("tcp", cfg.GRPC.Port)
+	if err != nil {
+		log.Panic().Err(err).Msg("")
+	}
+	go func() {
+		if err := grpcEndpoint.Serve(grpcListener); err != nil {
+			log.Panic().Err(err).Msg("")
+		}
+	}()
+
 	go func() {
 		if err := metricEndpoint.Listen(":8081"); err != nil {
 			log.Panic().Err(err).Msg("")
@@ -79,3 +124,21 @@ func main() {
 		log.Panic().Err(err).Msg("")
 	}
 }
+
+func newVerifier(cfg config.Auth) (*auth.Verifier, error) {
+	var publicKey *rsa.PublicKey
+	if cfg.PublicKeyPath != "" {
+		data, err := os.ReadFile(cfg.PublicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		publicKey, err = auth.ParseRSAPublicKey(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Secret == "" && publicKey == nil {
+		log.Warn().Msg("auth keys are not configured, all changes will be rejected")
+	}
+	return auth.NewVerifier([]byte(cfg.Secret), publicKey), nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	_ "embed"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/pkg/errors"
@@ -11,8 +12,20 @@ import (
 )
 
 type Config struct {
-	App      App
-	Postgres Postgres
+	App         App
+	Postgres    Postgres
+	Trash       Trash
+	Auth        Auth
+	RateLimit   RateLimit
+	Idempotency Idempotency
+	Feed        Feed
+	Publisher   Publisher
+	Outbox      Outbox
+	Webhooks    Webhooks
+	Events      Events
+	Live        Live
+	Export      Export
+	GRPC        GRPC
 }
 
 type App struct {
@@ -24,6 +37,51 @@ type Postgres struct {
 	ConnStr string `mapstructure:"connstr"`
 }
 
+type Trash struct {
+	// blogs and posts older than purgeafter in trash are deleted permanently
+	PurgeAfter    time.Duration `mapstructure:"purgeafter"`
+	PurgeInterval time.Duration `mapstructure:"purgeinterval"`
+}
+
+type Auth struct {
+	// secret of HS256 tokens, HS256 is disabled when empty
+	Secret string `mapstructure:"secret"`
+	// path to PEM public key of RS256 tokens, RS256 is disabled when empty
+	PublicKeyPath string `mapstructure:"publickeypath"`
+}
+
+type RateLimit struct {
+	// every request of a client IP, counted before authentication
+	IP    Limit `mapstructure:"ip"`
+	Read  Limit `mapstructure:"read"`
+	Write Limit `mapstructure:"write"`
+	// idle clients are forgotten every cleanupinterval
+	CleanupInterval time.Duration `mapstructure:"cleanupinterval"`
+}
+
+type Limit struct {
+	// requests per second
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+}
+
+type Idempotency struct {
+	// responses are replayed for ttl after the first request
+	TTL           time.Duration `mapstructure:"ttl"`
+	PurgeInterval time.Duration `mapstructure:"purgeinterval"`
+}
+
+type Feed struct {
+	// rendered feeds are served from memory for cachettl
+	CacheTTL time.Duration `mapstructure:"cachettl"`
+}
+
+type Publisher struct {
+	// scheduled posts are checked every interval and published batchsize posts per query
+	Interval  time.Duration `mapstructure:"interval"`
+	BatchSize int           `mapstructure:"batchsize"`
+}
+
 //go:embed config.yaml
 var config []byte
 
@@ -51,3 +109,43 @@ func New(configPath string) (*Config, error) {
 
 	return &config, nil
 }
+
+type Outbox struct {
+	// pending events are relayed every interval by batchsize, sent events are kept for retention
+	Interval  time.Duration `mapstructure:"interval"`
+	BatchSize int           `mapstructure:"batchsize"`
+	Retention time.Duration `mapstructure:"retention"`
+}
+
+type Webhooks struct {
+	// due deliveries are checked every interval and sent batchsize at once, each request waits at most timeout
+	Interval  time.Duration `mapstructure:"interval"`
+	BatchSize int           `mapstructure:"batchsize"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+	// failed delivery is retried after backoff doubled on every attempt up to maxbackoff, maxattempts failures make it dead
+	MaxAttempts int           `mapstructure:"maxattempts"`
+	Backoff     time.Duration `mapstructure:"backoff"`
+	MaxBackoff  time.Duration `mapstructure:"maxbackoff"`
+}
+
+type Events struct {
+	// last replaysize post events are kept to resume streams, heartbeat keeps idle streams open
+	ReplaySize int           `mapstructure:"replaysize"`
+	Heartbeat  time.Duration `mapstructure:"heartbeat"`
+}
+
+type Export struct {
+	// an export holds a database connection until the client reads it, it is cut after timeout
+	Timeout       time.Duration `mapstructure:"timeout"`
+	MaxConcurrent int           `mapstructure:"maxconcurrent"`
+}
+
+type Live struct {
+	// a websocket connection is subscribed to at most maxsubscriptions blogs
+	MaxSubscriptions int `mapstructure:"maxsubscriptions"`
+}
+
+type GRPC struct {
+	// grpc api listens on port next to the rest api
+	Port string `mapstructure:"port"`
+}
@@ -1,29 +1,98 @@
 package app
 
 import (
+	"time"
+
+	"github.com/Rolan335/project/internal/auth"
 	"github.com/Rolan335/project/internal/handler"
 	"github.com/Rolan335/project/internal/middleware"
+	"github.com/Rolan335/project/internal/ratelimit"
+	"github.com/Rolan335/project/internal/usecase"
 	"github.com/gofiber/adaptor/v2"
 	"github.com/gofiber/contrib/otelfiber"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cache"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-func GetRouter(handle *handler.Handler) *fiber.App {
-	app := fiber.New()
+func GetRouter(handle *handler.Handler, verifier *auth.Verifier, keys usecase.APIKeyUsecase, ipLimiter, readLimiter, writeLimiter *ratelimit.Limiter, idempotency usecase.IdempotencyUsecase, feedCacheTTL time.Duration, eventsHeartbeat time.Duration, liveMaxSubscriptions int, exportTimeout time.Duration, exportMaxConcurrent int) *fiber.App {
+	// the import reads NDJSON of any size from the stream, other routes get bodies up to BodyLimit
+	app := fiber.New(fiber.Config{
+		BodyLimit:         fiber.DefaultBodyLimit,
+		StreamRequestBody: true,
+	})
 	api := app.Group("/api")
+	api.Use(middleware.BodyLimit(fiber.DefaultBodyLimit, handler.StreamsBody))
 	api.Use(middleware.Metric)
 	api.Use(otelfiber.Middleware())
+	// every client is limited by IP before its credentials are checked, then by user per route
+	api.Use(middleware.IPRateLimit(ipLimiter))
+	api.Use(middleware.Auth(verifier, keys))
+	read := middleware.RateLimit(readLimiter)
+	write := middleware.RateLimit(writeLimiter)
+	idempotent := middleware.Idempotency(idempotency)
+	// feed readers poll often, feeds are served from memory for feedCacheTTL
+	feedCache := cache.New(cache.Config{
+		Expiration:           feedCacheTTL,
+		StoreResponseHeaders: true,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return utils.CopyString(c.OriginalURL())
+		},
+		Next: func(c *fiber.Ctx) bool {
+			return c.Response().StatusCode() != fiber.StatusOK
+		},
+	})
 
-	api.Get("/blog/:blog_id", handle.GetBlog)
-	api.Post("/blog", handle.CreateBlog)
-	api.Put("/blog/:blog_id", handle.UpdateBlog)
-	api.Delete("/blog/:blog_id", handle.DeleteBlog)
-	api.Get("/blog/:blog_id/posts", handle.GetPosts)
-	api.Get("/blog/:blog_id/posts/:post_id", handle.GetPost)
-	api.Put("/blog/:blog_id/posts/:post_id", handle.UpdatePost)
-	api.Post("/blog/:blog_id/posts", handle.CreatePost)
-	api.Delete("/blog/:blog_id/posts/:post_id", handle.DeletePost)
+	api.Get("/blog/:blog_id", read, handle.GetBlog)
+	api.Post("/blog", write, idempotent, handle.CreateBlog)
+	api.Put("/blog/:blog_id", write, handle.UpdateBlog)
+	api.Delete("/blog/:blog_id", write, handle.DeleteBlog)
+	api.Post("/blog/:blog_id/restore", write, handle.RestoreBlog)
+	api.Get("/trash/blogs", read, handle.GetBlogsTrash)
+	api.Get("/blog/:blog_id/tags", read, handle.GetBlogTags)
+	api.Get("/blog/:blog_id/export", read, handle.ExportBlog(exportTimeout, exportMaxConcurrent))
+	api.Get("/blog/:blog_id/feed.rss", read, middleware.NotModified, feedCache, handle.GetFeedRSS)
+	api.Get("/blog/:blog_id/feed.atom", read, middleware.NotModified, feedCache, handle.GetFeedAtom)
+	api.Get("/blog/:blog_id/events", read, handle.StreamPostEvents(eventsHeartbeat))
+	api.Get("/blog/:blog_id/posts", read, handle.GetPosts)
+	api.Get("/blog/:blog_id/posts/:post_id", read, handle.GetPost)
+	api.Put("/blog/:blog_id/posts/:post_id", write, handle.UpdatePost)
+	api.Post("/blog/:blog_id/posts", write, idempotent, handle.CreatePost)
+	api.Post("/blog/:blog_id/posts\\:import", write, handle.ImportPosts)
+	api.Delete("/blog/:blog_id/posts/:post_id", write, handle.DeletePost)
+	api.Post("/blog/:blog_id/posts/:post_id/restore", write, handle.RestorePost)
+	api.Get("/blog/:blog_id/trash", read, handle.GetPostsTrash)
+	// diff goes before :revision, otherwise it is matched as a revision number
+	api.Get("/blog/:blog_id/posts/:post_id/revisions", read, handle.GetPostRevisions)
+	api.Get("/blog/:blog_id/posts/:post_id/revisions/diff", read, handle.DiffPostRevisions)
+	api.Get("/blog/:blog_id/posts/:post_id/revisions/:revision", read, handle.GetPostRevision)
+	api.Post("/blog/:blog_id/posts/:post_id/revisions/:revision/restore", write, handle.RestorePostRevision)
+	api.Get("/blog/:blog_id/posts/:post_id/comments", read, handle.GetComments)
+	api.Post("/blog/:blog_id/posts/:post_id/comments", write, handle.CreateComment)
+	api.Put("/blog/:blog_id/posts/:post_id/comments/:comment_id", write, handle.UpdateComment)
+	api.Delete("/blog/:blog_id/posts/:post_id/comments/:comment_id", write, handle.DeleteComment)
+	api.Post("/blog/:blog_id/webhooks", write, handle.CreateWebhook)
+	api.Get("/blog/:blog_id/webhooks", read, handle.GetWebhooks)
+	api.Delete("/blog/:blog_id/webhooks/:webhook_id", write, handle.DeleteWebhook)
+	api.Get("/blog/:blog_id/webhooks/:webhook_id/deliveries", read, handle.GetWebhookDeliveries)
+	api.Post("/blog/:blog_id/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", write, handle.RedeliverWebhook)
+	api.Get("/ws", read, handle.LiveUpgrade, handle.Live(liveMaxSubscriptions, eventsHeartbeat))
+	api.Get("/search", read, handle.SearchPosts)
+	// mutations are not idempotent: GraphQL answers 200 to failed operations, so responses are not stored for replay
+	api.Post("/graphql", middleware.GraphQLRateLimit(readLimiter, writeLimiter), handle.GraphQL())
+	api.Get("/b/:blog_slug", read, handle.GetBlogBySlug)
+	api.Get("/b/:blog_slug/:post_slug", read, handle.GetPostBySlug)
+	api.Post("/users", write, handle.CreateUser)
+	api.Get("/users/:user_id", read, handle.GetUser)
+	api.Put("/users/:user_id", write, handle.UpdateUser)
+	api.Get("/users/:user_id/blogs", read, handle.GetUserBlogs)
+	api.Post("/keys", write, handle.CreateAPIKey)
+	api.Get("/keys", read, handle.GetAPIKeys)
+	api.Delete("/keys/:key_id", write, handle.RevokeAPIKey)
+	// every route needs its entry in internal/openapi, router_test checks it
+	api.Get("/openapi.json", read, handle.OpenAPI())
+	api.Use("/docs", read, handle.Docs())
 
 	return app
 }
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/handler"
+	"github.com/Rolan335/project/internal/openapi"
+	"github.com/Rolan335/project/internal/ratelimit"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter() *fiber.App {
+	handle := handler.New(nil, nil, nil, nil, nil, validator.New())
+	limiter := ratelimit.New(100, 100)
+	return GetRouter(handle, auth.NewVerifier([]byte("secret"), nil), nil, limiter, limiter, limiter, nil, time.Minute, time.Minute, 1, time.Minute, 1)
+}
+
+func TestGetRouter_documented(t *testing.T) {
+	app := newTestRouter()
+	doc := openapi.Build()
+
+	registered := make(map[string]bool)
+	for _, route := range app.GetRoutes(true) {
+		// fiber registers HEAD next to every GET
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+		path := openapi.Path(route.Path)
+		method := strings.ToLower(route.Method)
+		registered[method+" "+path] = true
+		assert.NotNil(t, doc.Paths[path][method], "%s %s has no entry in internal/openapi", route.Method, route.Path)
+	}
+	for path, item := range doc.Paths {
+		for method := range item {
+			assert.True(t, registered[method+" "+path], "%s %s is documented but not registered", method, path)
+		}
+	}
+}
+
+func TestGetRouter_docs(t *testing.T) {
+	app := newTestRouter()
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var doc struct {
+		OpenAPI string
+		Paths   map[string]any
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+	assert.Contains(t, doc.Paths, "/api/blog/{blog_id}/posts:import")
+
+	tests := []struct {
+		path     string
+		contains string
+	}{
+		{"/api/docs", "/api/docs/swagger-initializer.js"},
+		{"/api/docs/swagger-initializer.js", "/api/openapi.json"},
+		{"/api/docs/swagger-ui-bundle.js", "SwaggerUIBundle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil), -1)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Contains(t, string(body), tt.contains)
+		})
+	}
+}
+
+func TestGetRouter_bodyLimit(t *testing.T) {
+	app := newTestRouter()
+
+	body := strings.NewReader(`{"name":"` + strings.Repeat("a", fiber.DefaultBodyLimit) + `"}`)
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/api/blog", body), -1)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
@@ -2,4 +2,21 @@ package apperror
 
 import "errors"
 
-var ErrNotFound = errors.New("entity not found")
+var (
+	ErrNotFound      = errors.New("entity not found")
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrVersionMismatch is returned when entity was changed since the version client has seen
+	ErrVersionMismatch = errors.New("entity version mismatch")
+	// ErrConflict is returned when unique field of entity is already taken
+	ErrConflict = errors.New("entity already exists")
+	// ErrUnauthorized is returned when anonymous caller tries to change data
+	ErrUnauthorized = errors.New("authentication required")
+	// ErrForbidden is returned when caller changes blog of another user
+	ErrForbidden = errors.New("caller is not the owner")
+	// ErrNotRegistered is returned when authenticated caller creates something before registering as user
+	ErrNotRegistered = errors.New("caller is not registered as user")
+	// ErrIdempotencyKeyReused is returned when idempotency key is sent again with another request
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused with different request")
+	// ErrRequestInProgress is returned when request with the same idempotency key is not finished yet
+	ErrRequestInProgress = errors.New("request with idempotency key in progress")
+)
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Scopes of API keys. Admin includes all other scopes.
+const (
+	ScopeRead       = "read"
+	ScopeWritePosts = "write:posts"
+	ScopeAdmin      = "admin"
+)
+
+type userIDKey struct{}
+
+type scopesKey struct{}
+
+// WithUserID returns ctx carrying id of the authenticated user
+func WithUserID(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserID returns id of the authenticated user, false for anonymous requests
+func UserID(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(uuid.UUID)
+	return userID, ok
+}
+
+// WithScopes limits what the authenticated user can do, used for API keys
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey{}, scopes)
+}
+
+// HasScope reports whether the caller is allowed to act within scope.
+// Callers authenticated by JWT are not limited by scopes.
+func HasScope(ctx context.Context, scope string) bool {
+	scopes, ok := ctx.Value(scopesKey{}).([]string)
+	if !ok {
+		return true
+	}
+	return slices.Contains(scopes, scope) || slices.Contains(scopes, ScopeAdmin)
+}
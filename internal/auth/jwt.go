@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type header struct {
+	Alg string `json:"alg"`
+}
+
+type claims struct {
+	Subject   string `json:"sub"`
+	ExpiresAt *int64 `json:"exp"`
+	NotBefore *int64 `json:"nbf"`
+}
+
+// Verifier checks signature and time claims of JWT. Only algorithms with configured key are accepted,
+// so token signed with HS256 over public key can not pass as RS256.
+type Verifier struct {
+	secret    []byte
+	publicKey *rsa.PublicKey
+	now       func() time.Time
+}
+
+// NewVerifier creates verifier for HS256 when secret is set and for RS256 when publicKey is set
+func NewVerifier(secret []byte, publicKey *rsa.PublicKey) *Verifier {
+	return &Verifier{
+		secret:    secret,
+		publicKey: publicKey,
+		now:       time.Now,
+	}
+}
+
+// ParseRSAPublicKey parses PEM encoded PKIX or PKCS1 public key
+func ParseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("auth.ParseRSAPublicKey: no PEM block")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "auth.ParseRSAPublicKey")
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("auth.ParseRSAPublicKey: not RSA key")
+	}
+	return rsaKey, nil
+}
+
+// Verify returns subject of the token
+func (v *Verifier) Verify(token string) (uuid.UUID, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return uuid.Nil, ErrInvalidToken
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	if err := v.verifySignature(h.Alg, signed, signature); err != nil {
+		return uuid.Nil, err
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+	now := v.now().Unix()
+	if c.ExpiresAt != nil && now >= *c.ExpiresAt {
+		return uuid.Nil, ErrInvalidToken
+	}
+	if c.NotBefore != nil && now < *c.NotBefore {
+		return uuid.Nil, ErrInvalidToken
+	}
+	subject, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+	return subject, nil
+}
+
+func (v *Verifier) verifySignature(alg string, signed, signature []byte) error {
+	switch {
+	case alg == HS256 && len(v.secret) > 0:
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write(signed)
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return ErrInvalidToken
+		}
+		return nil
+	case alg == RS256 && v.publicKey != nil:
+		digest := sha256.Sum256(signed)
+		if err := rsa.VerifyPKCS1v15(v.publicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return ErrInvalidToken
+		}
+		return nil
+	}
+	return ErrInvalidToken
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
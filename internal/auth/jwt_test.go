@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func sign(t *testing.T, alg string, claims map[string]any, key any) string {
+	t.Helper()
+	h, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	c, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	secret := []byte("secret")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userID := uuid.New()
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name     string
+		verifier *Verifier
+		token    string
+		wantErr  bool
+	}{
+		{"hs256", NewVerifier(secret, nil), sign(t, HS256, map[string]any{"sub": userID.String(), "exp": now + 60}, secret), false},
+		{"rs256", NewVerifier(nil, &rsaKey.PublicKey), sign(t, RS256, map[string]any{"sub": userID.String()}, rsaKey), false},
+		{"wrong secret", NewVerifier(secret, nil), sign(t, HS256, map[string]any{"sub": userID.String()}, []byte("other")), true},
+		{"hs256 not configured", NewVerifier(nil, &rsaKey.PublicKey), sign(t, HS256, map[string]any{"sub": userID.String()}, secret), true},
+		{"alg none", NewVerifier(secret, nil), sign(t, "none", map[string]any{"sub": userID.String()}, nil), true},
+		{"expired", NewVerifier(secret, nil), sign(t, HS256, map[string]any{"sub": userID.String(), "exp": now - 1}, secret), true},
+		{"not yet valid", NewVerifier(secret, nil), sign(t, HS256, map[string]any{"sub": userID.String(), "nbf": now + 60}, secret), true},
+		{"subject not uuid", NewVerifier(secret, nil), sign(t, HS256, map[string]any{"sub": "admin"}, secret), true},
+		{"malformed", NewVerifier(secret, nil), "abc.def", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.verifier.Verify(tt.token)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidToken)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, userID, got)
+		})
+	}
+}
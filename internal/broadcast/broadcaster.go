@@ -0,0 +1,168 @@
+package broadcast
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// subscriptionBuffer is the number of events a subscriber may fall behind, a slower one is dropped
+// and is expected to come back with the id of the last event it has got
+const subscriptionBuffer = 64
+
+// Event is a message published to a topic. ID is "<epoch>-<seq>", epoch tells ids of another process apart.
+type Event struct {
+	ID    string
+	Topic uuid.UUID
+	Type  string
+	Data  []byte
+	// Private events are delivered to private subscriptions only
+	Private bool
+	seq     uint64
+}
+
+// Broadcaster fans out events to subscribers of their topic in process
+// and keeps the last events of all topics to replay them on resume.
+type Broadcaster struct {
+	mu     *sync.Mutex
+	epoch  string
+	seq    uint64
+	size   int
+	buffer []Event
+	subs   map[*Subscription]struct{}
+}
+
+// New keeps the last size events for replay
+func New(size int) *Broadcaster {
+	return &Broadcaster{
+		mu:     &sync.Mutex{},
+		epoch:  strconv.FormatInt(time.Now().UnixNano(), 36),
+		size:   size,
+		buffer: make([]Event, 0, size),
+		subs:   make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription gets events of one topic in publish order. Events is closed when the subscription
+// is closed or dropped for falling behind.
+type Subscription struct {
+	Events <-chan Event
+	// Replay are buffered events published after the last event id given to Subscribe
+	Replay []Event
+	// Missed is set when some events after the last event id are not in the buffer anymore,
+	// the subscriber should reload the state instead of relying on Replay
+	Missed bool
+
+	b       *Broadcaster
+	topic   uuid.UUID
+	private bool
+	ch      chan Event
+}
+
+func (b *Broadcaster) Publish(topic uuid.UUID, eventType string, data []byte, private bool) {
+	b.publish(topic, eventType, data, private, nil)
+}
+
+// publish delivers the event to subscribers of topic except the one who sent it
+func (b *Broadcaster) publish(topic uuid.UUID, eventType string, data []byte, private bool, except *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	e := Event{
+		ID:      b.epoch + "-" + strconv.FormatUint(b.seq, 10),
+		Topic:   topic,
+		Type:    eventType,
+		Data:    data,
+		Private: private,
+		seq:     b.seq,
+	}
+	if b.size > 0 {
+		if len(b.buffer) == b.size {
+			copy(b.buffer, b.buffer[1:])
+			b.buffer = b.buffer[:len(b.buffer)-1]
+		}
+		b.buffer = append(b.buffer, e)
+	}
+	for s := range b.subs {
+		if s == except || !s.accepts(e) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			b.drop(s)
+		}
+	}
+}
+
+// Subscribe starts a subscription of topic. With lastEventID the events published after it are replayed,
+// private subscriptions get private events too.
+func (b *Broadcaster) Subscribe(topic uuid.UUID, lastEventID string, private bool) *Subscription {
+	ch := make(chan Event, subscriptionBuffer)
+	s := &Subscription{
+		Events:  ch,
+		b:       b,
+		topic:   topic,
+		private: private,
+		ch:      ch,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if lastEventID != "" {
+		s.Replay, s.Missed = b.replay(s, lastEventID)
+	}
+	b.subs[s] = struct{}{}
+	return s
+}
+
+// replay returns events of the subscription after lastEventID, it must be called with the lock held
+func (b *Broadcaster) replay(s *Subscription, lastEventID string) ([]Event, bool) {
+	epoch, seqStr, ok := strings.Cut(lastEventID, "-")
+	if !ok || epoch != b.epoch {
+		return nil, true
+	}
+	last, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil || last > b.seq {
+		return nil, true
+	}
+	// the next event must still be in the buffer, unless nothing has happened since
+	missed := last < b.seq && (len(b.buffer) == 0 || b.buffer[0].seq > last+1)
+	var events []Event
+	for _, e := range b.buffer {
+		if e.seq > last && s.accepts(e) {
+			events = append(events, e)
+		}
+	}
+	return events, missed
+}
+
+// drop removes the subscription, it must be called with the lock held
+func (b *Broadcaster) drop(s *Subscription) {
+	if _, ok := b.subs[s]; !ok {
+		return
+	}
+	delete(b.subs, s)
+	close(s.ch)
+}
+
+func (s *Subscription) accepts(e Event) bool {
+	return e.Topic == s.topic && (!e.Private || s.private)
+}
+
+// Publish sends the event to the other subscribers of the topic
+func (s *Subscription) Publish(eventType string, data []byte, private bool) {
+	s.b.publish(s.topic, eventType, data, private, s)
+}
+
+// Close stops the subscription, it is safe to call it more than once
+func (s *Subscription) Close() {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	s.b.drop(s)
+}
@@ -0,0 +1,107 @@
+package broadcast
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func receive(s *Subscription) []string {
+	var types []string
+	for {
+		select {
+		case e, ok := <-s.Events:
+			if !ok {
+				return types
+			}
+			types = append(types, e.Type)
+		default:
+			return types
+		}
+	}
+}
+
+func TestBroadcaster_Publish(t *testing.T) {
+	a := assert.New(t)
+	b := New(10)
+	topic := uuid.New()
+
+	public := b.Subscribe(topic, "", false)
+	private := b.Subscribe(topic, "", true)
+	other := b.Subscribe(uuid.New(), "", true)
+
+	b.Publish(topic, "created", nil, false)
+	b.Publish(topic, "draft", nil, true)
+
+	a.Equal([]string{"created"}, receive(public))
+	a.Equal([]string{"created", "draft"}, receive(private))
+	a.Empty(receive(other))
+
+	// subscriber does not get what it publishes itself
+	private.Publish("presence", nil, false)
+	a.Equal([]string{"presence"}, receive(public))
+	a.Empty(receive(private))
+
+	public.Close()
+	public.Close()
+	_, ok := <-public.Events
+	a.False(ok)
+}
+
+func TestBroadcaster_Subscribe(t *testing.T) {
+	topic := uuid.New()
+	b := New(3)
+	var ids []string
+	for _, typ := range []string{"1", "2", "3", "4"} {
+		b.Publish(topic, typ, nil, false)
+		ids = append(ids, b.buffer[len(b.buffer)-1].ID)
+	}
+
+	testCases := []struct {
+		name        string
+		lastEventID string
+		wantReplay  []string
+		wantMissed  bool
+	}{
+		{name: "new", lastEventID: ""},
+		{name: "resume", lastEventID: ids[1], wantReplay: []string{"3", "4"}},
+		{name: "up to date", lastEventID: ids[3]},
+		{name: "next is in buffer", lastEventID: ids[0], wantReplay: []string{"2", "3", "4"}},
+		{name: "evicted", lastEventID: b.epoch + "-0", wantReplay: []string{"2", "3", "4"}, wantMissed: true},
+		{name: "another process", lastEventID: "abc-1", wantMissed: true},
+		{name: "malformed", lastEventID: "1", wantMissed: true},
+		{name: "from the future", lastEventID: b.epoch + "-10", wantMissed: true},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			s := b.Subscribe(topic, tt.lastEventID, false)
+			defer s.Close()
+			var replay []string
+			for _, e := range s.Replay {
+				replay = append(replay, e.Type)
+			}
+			assert.Equal(t, tt.wantReplay, replay)
+			assert.Equal(t, tt.wantMissed, s.Missed)
+		})
+	}
+
+	// the buffer is shared by topics, an event of another topic evicts too
+	b.Publish(uuid.New(), "other", nil, false)
+	s := b.Subscribe(topic, ids[0], false)
+	defer s.Close()
+	assert.True(t, s.Missed)
+}
+
+func TestBroadcaster_slowSubscriber(t *testing.T) {
+	b := New(0)
+	topic := uuid.New()
+	s := b.Subscribe(topic, "", false)
+	for i := 0; i <= subscriptionBuffer; i++ {
+		b.Publish(topic, "updated", nil, false)
+	}
+	// buffered events are still delivered, then the channel is closed
+	assert.Len(t, receive(s), subscriptionBuffer)
+	_, ok := <-s.Events
+	assert.False(t, ok)
+}
@@ -82,35 +82,54 @@ func (c *CacheDecorator) GetBlog(ctx context.Context, blogID uuid.UUID) (model.D
 	c.blogCache.Set(ctx, blog)
 	return blog, nil
 }
-func (c *CacheDecorator) AddBlog(ctx context.Context, blog model.DbBlog) (uuid.UUID, error) {
-	id, err := c.repository.AddBlog(ctx, blog)
+func (c *CacheDecorator) AddBlog(ctx context.Context, blog model.DbBlog) (model.DbBlog, error) {
+	newBlog, err := c.repository.AddBlog(ctx, blog)
 	if err != nil {
-		return uuid.Nil, errors.Wrap(err, "cacheDecorator.AddBlog")
+		return model.DbBlog{}, errors.Wrap(err, "cacheDecorator.AddBlog")
 	}
-	// set to cache only if success insert into repo
-	c.blogCache.Set(ctx, blog)
-	return id, nil
+	// set to cache only if success insert into repo, stored blog has the slug
+	c.blogCache.Set(ctx, newBlog)
+	return newBlog, nil
+}
+
+// GetBlogBySlug is not cached, slugs change on rename
+func (c *CacheDecorator) GetBlogBySlug(ctx context.Context, slug string) (model.DbBlog, error) {
+	return c.repository.GetBlogBySlug(ctx, slug)
 }
 func (c *CacheDecorator) UpdateBlog(ctx context.Context, blog model.DbBlog) (model.DbBlog, error) {
 	newBlog, err := c.repository.UpdateBlog(ctx, blog)
 	if err != nil {
 		return model.DbBlog{}, errors.Wrap(err, "cacheDecorator.UpdateBlog")
 	}
-	// update cache only if success into repo
-	c.blogCache.Set(ctx, blog)
+	// update cache only if success into repo, stored blog has new version
+	c.blogCache.Set(ctx, newBlog)
 	return newBlog, nil
 }
 
-// TODO: При удалении блога остаются посты блога в кэше cachedata.PostCache
 func (c *CacheDecorator) DeleteBlog(ctx context.Context, blogID uuid.UUID) error {
 	err := c.repository.DeleteBlog(ctx, blogID)
 	if err != nil {
 		return errors.Wrap(err, "cacheDecorator.DeleteBlog")
 	}
 	c.blogCache.Delete(ctx, blogID)
+	// посты блога тоже уходят в корзину
+	c.postCache.DeleteByBlog(ctx, blogID)
 	return nil
 }
 
+func (c *CacheDecorator) GetDeletedBlogs(ctx context.Context, userID uuid.UUID) ([]model.DbBlog, error) {
+	return c.repository.GetDeletedBlogs(ctx, userID)
+}
+
+func (c *CacheDecorator) RestoreBlog(ctx context.Context, blogID uuid.UUID) (model.DbBlog, error) {
+	blog, err := c.repository.RestoreBlog(ctx, blogID)
+	if err != nil {
+		return model.DbBlog{}, errors.Wrap(err, "cacheDecorator.RestoreBlog")
+	}
+	c.blogCache.Set(ctx, blog)
+	return blog, nil
+}
+
 func (c *CacheDecorator) GetPost(ctx context.Context, postID uuid.UUID) (model.DbPost, error) {
 	if model, ok := c.postCache.Get(ctx, postID); ok {
 		log.Debug().Str("uuid:", postID.String()).Msg("cache hit")
@@ -126,28 +145,70 @@ func (c *CacheDecorator) GetPost(ctx context.Context, postID uuid.UUID) (model.D
 	return post, nil
 }
 
-func (c *CacheDecorator) GetPosts(ctx context.Context, blogID uuid.UUID) ([]model.DbPost, error) {
+// GetHTML returns rendered text kept next to the cached post of the same version
+func (c *CacheDecorator) GetHTML(ctx context.Context, post model.DbPost) (string, bool) {
+	return c.postCache.GetHTML(ctx, post)
+}
+
+// SetHTML keeps rendered text next to the cached post of the same version, so it is rendered once per version
+func (c *CacheDecorator) SetHTML(ctx context.Context, post model.DbPost, html string) {
+	c.postCache.SetHTML(ctx, post, html)
+}
+
+func (c *CacheDecorator) GetPosts(ctx context.Context, filter model.DbPostsFilter) ([]model.DbPost, error) {
 	// не идём в кэш, так как там могут быть не все посты и в любом случае обращение в бд.
-	return c.repository.GetPosts(ctx, blogID)
+	return c.repository.GetPosts(ctx, filter)
+}
+func (c *CacheDecorator) GetBlogsPosts(ctx context.Context, filter model.DbBlogsPostsFilter) ([]model.DbPost, error) {
+	return c.repository.GetBlogsPosts(ctx, filter)
 }
-func (c *CacheDecorator) AddPost(ctx context.Context, post model.DbPost) (uuid.UUID, error) {
-	id, err := c.repository.AddPost(ctx, post)
+func (c *CacheDecorator) SearchPosts(ctx context.Context, search model.DbPostsSearch) ([]model.DbPostSearchResult, error) {
+	return c.repository.SearchPosts(ctx, search)
+}
+func (c *CacheDecorator) AddPost(ctx context.Context, post model.DbPost) (model.DbPost, error) {
+	newPost, err := c.repository.AddPost(ctx, post)
 	if err != nil {
-		return uuid.Nil, errors.Wrap(err, "cacheDecorator.AddPost")
+		return model.DbPost{}, errors.Wrap(err, "cacheDecorator.AddPost")
 	}
-	// set to cache only if success insert into repo
-	c.postCache.Set(ctx, post)
-	return id, nil
+	// post without tags is read from db with an empty list, cache hit must not differ
+	if newPost.Tags == nil {
+		newPost.Tags = []string{}
+	}
+	// set to cache only if success insert into repo, stored post has the slug
+	c.postCache.Set(ctx, newPost)
+	return newPost, nil
+}
+
+// GetPostBySlug is not cached, slugs change on rename
+func (c *CacheDecorator) GetPostBySlug(ctx context.Context, blogID uuid.UUID, slug string) (model.DbPost, error) {
+	return c.repository.GetPostBySlug(ctx, blogID, slug)
+}
+func (c *CacheDecorator) StreamPosts(ctx context.Context, blogID uuid.UUID, fn func(model.DbPost) error) error {
+	return c.repository.StreamPosts(ctx, blogID, fn)
+}
+func (c *CacheDecorator) ImportPosts(ctx context.Context, blogID uuid.UUID, next func() ([]model.DbPost, error)) error {
+	return c.repository.ImportPosts(ctx, blogID, next)
 }
 func (c *CacheDecorator) UpdatePost(ctx context.Context, post model.DbPost) (model.DbPost, error) {
 	newPost, err := c.repository.UpdatePost(ctx, post)
 	if err != nil {
 		return model.DbPost{}, errors.Wrap(err, "cacheDecorator.UpdatePost")
 	}
-	// update cache only if success into repo
-	c.postCache.Set(ctx, post)
+	// update cache only if success into repo, stored post has new version
+	c.postCache.Set(ctx, newPost)
 	return newPost, nil
 }
+func (c *CacheDecorator) PublishScheduled(ctx context.Context, limit int) ([]model.DbPost, error) {
+	posts, err := c.repository.PublishScheduled(ctx, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "cacheDecorator.PublishScheduled")
+	}
+	// cached scheduled posts are stale now
+	for i := 0; i < len(posts); i++ {
+		c.postCache.Delete(ctx, posts[i].ID)
+	}
+	return posts, nil
+}
 func (c *CacheDecorator) DeletePost(ctx context.Context, postID uuid.UUID, blogID uuid.UUID) error {
 	err := c.repository.DeletePost(ctx, postID, blogID)
 	if err != nil {
@@ -156,3 +217,65 @@ func (c *CacheDecorator) DeletePost(ctx context.Context, postID uuid.UUID, blogI
 	c.postCache.Delete(ctx, postID)
 	return nil
 }
+
+func (c *CacheDecorator) GetDeletedPosts(ctx context.Context, blogID uuid.UUID) ([]model.DbPost, error) {
+	return c.repository.GetDeletedPosts(ctx, blogID)
+}
+
+func (c *CacheDecorator) RestorePost(ctx context.Context, postID uuid.UUID, blogID uuid.UUID) (model.DbPost, error) {
+	post, err := c.repository.RestorePost(ctx, postID, blogID)
+	if err != nil {
+		return model.DbPost{}, errors.Wrap(err, "cacheDecorator.RestorePost")
+	}
+	c.postCache.Set(ctx, post)
+	return post, nil
+}
+
+// PurgeDeleted does not touch cache, entities in trash are never cached
+func (c *CacheDecorator) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return c.repository.PurgeDeleted(ctx, olderThan)
+}
+
+func (c *CacheDecorator) GetPostRevisions(ctx context.Context, postID uuid.UUID, blogID uuid.UUID) ([]model.DbPostRevision, error) {
+	return c.repository.GetPostRevisions(ctx, postID, blogID)
+}
+
+func (c *CacheDecorator) GetPostRevision(ctx context.Context, postID uuid.UUID, blogID uuid.UUID, revision int) (model.DbPostRevision, error) {
+	return c.repository.GetPostRevision(ctx, postID, blogID, revision)
+}
+
+func (c *CacheDecorator) GetBlogOwner(ctx context.Context, blogID uuid.UUID) (uuid.UUID, error) {
+	return c.repository.GetBlogOwner(ctx, blogID)
+}
+
+func (c *CacheDecorator) GetBlogTags(ctx context.Context, blogID uuid.UUID) ([]model.DbTagCount, error) {
+	return c.repository.GetBlogTags(ctx, blogID)
+}
+
+// CommentDecorator drops the post of changed comments from cache, cached post keeps its comments count
+type CommentDecorator struct {
+	repository.CommentRepository
+	postCache *cachedata.PostCache
+}
+
+// Comments wraps comment repository, so that new and deleted comments reach the post cache
+func (c *CacheDecorator) Comments(comments repository.CommentRepository) *CommentDecorator {
+	return &CommentDecorator{CommentRepository: comments, postCache: c.postCache}
+}
+
+func (c *CommentDecorator) AddComment(ctx context.Context, blogID uuid.UUID, comment model.DbComment) (uuid.UUID, error) {
+	id, err := c.CommentRepository.AddComment(ctx, blogID, comment)
+	if err != nil {
+		return uuid.Nil, errors.Wrap(err, "cacheDecorator.AddComment")
+	}
+	c.postCache.Delete(ctx, comment.PostID)
+	return id, nil
+}
+
+func (c *CommentDecorator) DeleteComment(ctx context.Context, commentID uuid.UUID, postID uuid.UUID, blogID uuid.UUID) error {
+	if err := c.CommentRepository.DeleteComment(ctx, commentID, postID, blogID); err != nil {
+		return errors.Wrap(err, "cacheDecorator.DeleteComment")
+	}
+	c.postCache.Delete(ctx, postID)
+	return nil
+}
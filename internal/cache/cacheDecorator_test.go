@@ -34,7 +34,7 @@ func TestCache_GetBlog(t *testing.T) {
 	repository := mocks.NewMockBlogRepository(ctrl)
 	cache := NewCacheDecorator(defaultTtl, defaultSize, repository)
 
-	repository.EXPECT().AddBlog(gomock.Any(), existModel).Return(existBlogID, nil).Times(1)
+	repository.EXPECT().AddBlog(gomock.Any(), existModel).Return(existModel, nil).Times(1)
 	cache.AddBlog(context.Background(), existModel)
 
 	testCases := []struct {
@@ -86,18 +86,20 @@ func TestCache_AddBlog(t *testing.T) {
 	defer ctrl.Finish()
 	repository := mocks.NewMockBlogRepository(ctrl)
 	cache := NewCacheDecorator(defaultTtl, defaultSize, repository)
-	ids := map[string]uuid.UUID{"valid add": uuid.New()}
+	in := model.DbBlog{ID: uuid.New(), UserID: uuid.New(), Name: "Valid Add", CreatedAt: time.Now()}
+	stored := in
+	stored.Slug = "valid-add"
 	testCases := []struct {
 		name            string
 		in              model.DbBlog
-		want            uuid.UUID
+		want            model.DbBlog
 		wantErr         error
 		repositoryCalls int
 	}{
 		{
 			name:            "valid add",
-			in:              model.DbBlog{ID: ids["valid add"], UserID: uuid.New(), Name: gofakeit.Name(), CreatedAt: time.Now()},
-			want:            ids["valid add"],
+			in:              in,
+			want:            stored,
 			repositoryCalls: 1,
 		},
 	}
@@ -111,6 +113,11 @@ func TestCache_AddBlog(t *testing.T) {
 			if got != tt.want {
 				t.Errorf("Cache.AddBlog() = %v, want %v", got, tt.want)
 			}
+			// cache keeps the stored blog, slug included
+			cached, err := cache.GetBlog(context.Background(), tt.in.ID)
+			if err != nil || cached != tt.want {
+				t.Errorf("Cache.GetBlog() = %v, %v, want %v", cached, err, tt.want)
+			}
 		})
 	}
 }
@@ -129,7 +136,7 @@ func TestCache_UpdateBlog(t *testing.T) {
 	repository := mocks.NewMockBlogRepository(ctrl)
 	cache := NewCacheDecorator(defaultTtl, defaultSize, repository)
 
-	repository.EXPECT().AddBlog(gomock.Any(), existModel).Return(existBlogID, nil).Times(1)
+	repository.EXPECT().AddBlog(gomock.Any(), existModel).Return(existModel, nil).Times(1)
 	cache.AddBlog(context.Background(), existModel)
 
 	testCases := []struct {
@@ -189,7 +196,7 @@ func TestCache_DeleteBlog(t *testing.T) {
 	repository := mocks.NewMockBlogRepository(ctrl)
 	cache := NewCacheDecorator(defaultTtl, defaultSize, repository)
 
-	repository.EXPECT().AddBlog(gomock.Any(), existModel).Return(existBlogID, nil).Times(1)
+	repository.EXPECT().AddBlog(gomock.Any(), existModel).Return(existModel, nil).Times(1)
 	cache.AddBlog(context.Background(), existModel)
 
 	testCases := []struct {
@@ -222,6 +229,116 @@ func TestCache_DeleteBlog(t *testing.T) {
 	}
 }
 
+func TestCache_DeleteBlogPosts(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockBlogRepository(ctrl)
+	cache := NewCacheDecorator(defaultTtl, defaultSize, repository)
+
+	post := model.DbPost{ID: uuid.New(), BlogID: uuid.New(), Title: gofakeit.Name(), Text: gofakeit.Name(), CreatedAt: time.Now()}
+	repository.EXPECT().AddPost(gomock.Any(), post).Return(post, nil).Times(1)
+	cache.AddPost(context.Background(), post)
+
+	repository.EXPECT().DeleteBlog(gomock.Any(), post.BlogID).Return(nil).Times(1)
+	a.NoError(cache.DeleteBlog(context.Background(), post.BlogID))
+
+	// post of deleted blog is not served from cache
+	repository.EXPECT().GetPost(gomock.Any(), post.ID).Return(model.DbPost{}, apperror.ErrNotFound).Times(1)
+	_, err := cache.GetPost(context.Background(), post.ID)
+	a.ErrorIs(err, apperror.ErrNotFound)
+}
+
+func TestCache_AddPostTags(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockBlogRepository(ctrl)
+	cache := NewCacheDecorator(defaultTtl, defaultSize, repository)
+
+	post := model.DbPost{ID: uuid.New(), BlogID: uuid.New(), Title: gofakeit.Name(), Version: 1}
+	repository.EXPECT().AddPost(gomock.Any(), post).Return(post, nil)
+	_, err := cache.AddPost(context.Background(), post)
+	a.NoError(err)
+	got, err := cache.GetPost(context.Background(), post.ID)
+	a.NoError(err)
+	a.NotNil(got.Tags)
+	a.Empty(got.Tags)
+}
+
+func TestCache_HTML(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockBlogRepository(ctrl)
+	cache := NewCacheDecorator(defaultTtl, defaultSize, repository)
+
+	post := model.DbPost{ID: uuid.New(), BlogID: uuid.New(), Title: gofakeit.Name(), Text: "*hi*", Version: 1}
+	// html of a post which is not cached is not kept
+	cache.SetHTML(context.Background(), post, "<p><em>hi</em></p>\n")
+	_, ok := cache.GetHTML(context.Background(), post)
+	a.False(ok)
+
+	repository.EXPECT().AddPost(gomock.Any(), post).Return(post, nil)
+	_, err := cache.AddPost(context.Background(), post)
+	a.NoError(err)
+	cache.SetHTML(context.Background(), post, "<p><em>hi</em></p>\n")
+	html, ok := cache.GetHTML(context.Background(), post)
+	a.True(ok)
+	a.Equal("<p><em>hi</em></p>\n", html)
+
+	// new version is rendered again
+	updated := post
+	updated.Version, updated.Text = 2, "**hi**"
+	repository.EXPECT().UpdatePost(gomock.Any(), updated).Return(updated, nil)
+	_, err = cache.UpdatePost(context.Background(), updated)
+	a.NoError(err)
+	_, ok = cache.GetHTML(context.Background(), updated)
+	a.False(ok)
+
+	// html of the old version read by a list does not bring the old version back
+	cache.SetHTML(context.Background(), post, "<p><em>hi</em></p>\n")
+	repository.EXPECT().GetPost(gomock.Any(), post.ID).Times(0)
+	got, err := cache.GetPost(context.Background(), post.ID)
+	a.NoError(err)
+	a.Equal(2, got.Version)
+	_, ok = cache.GetHTML(context.Background(), updated)
+	a.False(ok)
+}
+
+func TestCache_Comments(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockBlogRepository(ctrl)
+	commentRepository := mocks.NewMockCommentRepository(ctrl)
+	cache := NewCacheDecorator(defaultTtl, defaultSize, repository)
+	comments := cache.Comments(commentRepository)
+
+	post := model.DbPost{ID: uuid.New(), BlogID: uuid.New(), Title: gofakeit.Name(), Version: 1}
+	repository.EXPECT().AddPost(gomock.Any(), post).Return(post, nil)
+	_, err := cache.AddPost(context.Background(), post)
+	a.NoError(err)
+
+	// new comment drops the post with old count, it is read from db again
+	commentRepository.EXPECT().AddComment(gomock.Any(), post.BlogID, gomock.Any()).Return(uuid.New(), nil)
+	_, err = comments.AddComment(context.Background(), post.BlogID, model.DbComment{PostID: post.ID})
+	a.NoError(err)
+	commented := post
+	commented.CommentsCount = 1
+	repository.EXPECT().GetPost(gomock.Any(), post.ID).Return(commented, nil).Times(1)
+	got, err := cache.GetPost(context.Background(), post.ID)
+	a.NoError(err)
+	a.Equal(1, got.CommentsCount)
+
+	commentRepository.EXPECT().DeleteComment(gomock.Any(), gomock.Any(), post.ID, post.BlogID).Return(nil)
+	a.NoError(comments.DeleteComment(context.Background(), uuid.New(), post.ID, post.BlogID))
+	repository.EXPECT().GetPost(gomock.Any(), post.ID).Return(post, nil).Times(1)
+	got, err = cache.GetPost(context.Background(), post.ID)
+	a.NoError(err)
+	a.Equal(0, got.CommentsCount)
+}
+
 func TestCache_GoPollDeletion(t *testing.T) {
 	a := assert.New(t)
 	ctrl := gomock.NewController(t)
@@ -238,7 +355,7 @@ func TestCache_GoPollDeletion(t *testing.T) {
 			Name:      gofakeit.Name(),
 			CreatedAt: time.Now(),
 		}
-		repository.EXPECT().AddBlog(gomock.Any(), blog).Return(blog.ID, nil).Times(1)
+		repository.EXPECT().AddBlog(gomock.Any(), blog).Return(blog, nil).Times(1)
 		cache.AddBlog(context.Background(), blog)
 	}
 	_, len := cache.GetBlogLen()
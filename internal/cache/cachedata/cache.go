@@ -33,7 +33,8 @@ func (b *BlogCache) GetLen() int {
 func (b *BlogCache) Get(_ context.Context, uuid uuid.UUID) (model.DbBlog, bool) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	if v, ok := b.data[uuid]; ok {
+	// blog in trash is a miss
+	if v, ok := b.data[uuid]; ok && v.Db.DeletedAt == nil {
 		return v.Db, true
 	}
 	return model.DbBlog{}, false
@@ -100,7 +101,8 @@ func (b *PostCache) GetLen() int {
 func (b *PostCache) Get(_ context.Context, uuid uuid.UUID) (model.DbPost, bool) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	if v, ok := b.data[uuid]; ok {
+	// post in trash is a miss
+	if v, ok := b.data[uuid]; ok && v.Db.DeletedAt == nil {
 		return v.Db, true
 	}
 	return model.DbPost{}, false
@@ -116,6 +118,27 @@ func (b *PostCache) Set(_ context.Context, model model.DbPost) uuid.UUID {
 	return model.ID
 }
 
+// GetHTML returns rendered text of the post if the cached post has the same version
+func (b *PostCache) GetHTML(_ context.Context, post model.DbPost) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if v, ok := b.data[post.ID]; ok && v.Db.Version == post.Version && v.HTML != "" {
+		return v.HTML, true
+	}
+	return "", false
+}
+
+// SetHTML keeps rendered text next to the cached post of the same version. The post itself is never
+// cached here: it may be read before a concurrent update and would replace the newer cached version.
+func (b *PostCache) SetHTML(_ context.Context, post model.DbPost, html string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if v, ok := b.data[post.ID]; ok && v.Db.Version == post.Version {
+		v.HTML = html
+		b.data[post.ID] = v
+	}
+}
+
 func (b *PostCache) Delete(_ context.Context, uuid uuid.UUID) bool {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -126,6 +149,20 @@ func (b *PostCache) Delete(_ context.Context, uuid uuid.UUID) bool {
 	return false
 }
 
+// DeleteByBlog deletes all posts of the blog, returns number of deleted posts
+func (b *PostCache) DeleteByBlog(_ context.Context, blogID uuid.UUID) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	deleted := 0
+	for k, v := range b.data {
+		if v.Db.BlogID == blogID {
+			delete(b.data, k)
+			deleted++
+		}
+	}
+	return deleted
+}
+
 func (b *PostCache) DeleteExpired() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -14,4 +14,6 @@ type CacheBlog struct {
 type CachePost struct {
 	Deadline time.Time
 	Db       model.DbPost
+	// HTML is rendered Db.Text, empty until the post is requested as HTML
+	HTML string
 }
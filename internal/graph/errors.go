@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/rs/zerolog/log"
+)
+
+// Error is a resolver error with a code in extensions, clients check the code instead of the message
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]any {
+	return map[string]any{"code": e.Code}
+}
+
+func badInput(message string) error {
+	return &Error{Code: "BAD_USER_INPUT", Message: message}
+}
+
+// resolverError maps usecase errors to codes, unknown errors are logged and hidden from the client
+func resolverError(err error) error {
+	if errors.Is(err, apperror.ErrNotFound) {
+		return &Error{Code: "NOT_FOUND", Message: apperror.ErrNotFound.Error()}
+	}
+	if errors.Is(err, apperror.ErrInvalidCursor) {
+		return badInput(apperror.ErrInvalidCursor.Error())
+	}
+	if errors.Is(err, apperror.ErrVersionMismatch) {
+		return &Error{Code: "VERSION_MISMATCH", Message: apperror.ErrVersionMismatch.Error()}
+	}
+	if errors.Is(err, apperror.ErrConflict) {
+		return &Error{Code: "CONFLICT", Message: apperror.ErrConflict.Error()}
+	}
+	if errors.Is(err, apperror.ErrUnauthorized) {
+		return &Error{Code: "UNAUTHENTICATED", Message: apperror.ErrUnauthorized.Error()}
+	}
+	if errors.Is(err, apperror.ErrNotRegistered) {
+		return &Error{Code: "FORBIDDEN", Message: apperror.ErrNotRegistered.Error()}
+	}
+	if errors.Is(err, apperror.ErrForbidden) {
+		return &Error{Code: "FORBIDDEN", Message: apperror.ErrForbidden.Error()}
+	}
+	log.Err(err).Msg("")
+	return &Error{Code: "INTERNAL", Message: "internal error"}
+}
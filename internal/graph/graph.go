@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/go-playground/validator/v10"
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaString string
+
+// maxDepth limits nesting of queries such as blog.posts.blog.posts...
+const maxDepth = 8
+
+// Schema serves GraphQL queries and mutations of blogs, posts and users with the usecases of the REST API
+type Schema struct {
+	schema *graphql.Schema
+	blogs  usecase.BlogUsecase
+}
+
+func New(blogs usecase.BlogUsecase, users usecase.UserUsecase, validate *validator.Validate) *Schema {
+	resolver := &Resolver{
+		validate: validate,
+		blogs:    blogs,
+		users:    users,
+	}
+	return &Schema{
+		schema: graphql.MustParseSchema(schemaString, resolver,
+			graphql.MaxDepth(maxDepth),
+			// resolvers of a whole batch of blogs must run at once for the loader to collect them
+			graphql.MaxParallelism(postsBatchSize),
+		),
+		blogs: blogs,
+	}
+}
+
+// Exec runs the operation, every call gets its own posts loader
+func (s *Schema) Exec(ctx context.Context, query, operationName string, variables map[string]any) *graphql.Response {
+	return s.schema.Exec(withLoader(ctx, s.blogs), query, operationName, variables)
+}
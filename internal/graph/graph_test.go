@@ -0,0 +1,208 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blogUsecase has blogs with one post each and counts calls of post pages
+type blogUsecase struct {
+	usecase.BlogUsecase
+	mu         *sync.Mutex
+	blogs      map[uuid.UUID]model.BlogGetResp
+	batches    [][]uuid.UUID
+	pagesCalls int
+	updated    model.PostPutReq
+}
+
+func (u *blogUsecase) GetBlog(_ context.Context, req model.BlogGetReq) (model.BlogGetResp, error) {
+	blog, ok := u.blogs[req.BlogID]
+	if !ok {
+		return model.BlogGetResp{}, apperror.ErrNotFound
+	}
+	return blog, nil
+}
+
+func (u *blogUsecase) post(blogID uuid.UUID) model.PostGetResp {
+	return model.PostGetResp{PostID: uuid.New(), BlogID: blogID, Title: u.blogs[blogID].Name, Status: model.PostStatusPublished}
+}
+
+func (u *blogUsecase) GetBlogsPosts(_ context.Context, req model.BlogsPostsGetReq) (model.BlogsPostsGetResp, error) {
+	u.mu.Lock()
+	u.batches = append(u.batches, req.BlogIDs)
+	u.mu.Unlock()
+	resp := model.BlogsPostsGetResp{Posts: make(map[uuid.UUID]model.PostsGetResp)}
+	for _, blogID := range req.BlogIDs {
+		resp.Posts[blogID] = model.PostsGetResp{Posts: []model.PostGetResp{u.post(blogID)}, NextCursor: "next"}
+	}
+	return resp, nil
+}
+
+func (u *blogUsecase) GetPosts(_ context.Context, req model.PostsGetReq) (model.PostsGetResp, error) {
+	u.mu.Lock()
+	u.pagesCalls++
+	u.mu.Unlock()
+	if req.Cursor != "next" {
+		return model.PostsGetResp{}, apperror.ErrInvalidCursor
+	}
+	return model.PostsGetResp{Posts: []model.PostGetResp{u.post(req.BlogID)}}, nil
+}
+
+func (u *blogUsecase) UpdatePost(ctx context.Context, req model.PostPutReq) (model.PostPutResp, error) {
+	if _, ok := auth.UserID(ctx); !ok {
+		return model.PostPutResp{}, apperror.ErrUnauthorized
+	}
+	u.updated = req
+	return model.PostPutResp{PostID: req.PostID, BlogID: req.BlogID, Title: req.Title, Text: req.Text, Version: req.Version + 1}, nil
+}
+
+type userUsecase struct {
+	usecase.UserUsecase
+	user  model.UserGetResp
+	blogs []model.BlogGetResp
+}
+
+func (u *userUsecase) GetUser(_ context.Context, req model.UserGetReq) (model.UserGetResp, error) {
+	if req.UserID != u.user.UserID {
+		return model.UserGetResp{}, apperror.ErrNotFound
+	}
+	return u.user, nil
+}
+
+func (u *userUsecase) GetUserBlogs(_ context.Context, _ model.UserBlogsGetReq) (model.UserBlogsGetResp, error) {
+	return model.UserBlogsGetResp{Blogs: u.blogs}, nil
+}
+
+func newTestSchema(blogCount int) (*Schema, *blogUsecase, *userUsecase) {
+	users := &userUsecase{user: model.UserGetResp{UserID: uuid.New(), DisplayName: "user", CreatedAt: time.Now()}}
+	blogs := &blogUsecase{mu: &sync.Mutex{}, blogs: make(map[uuid.UUID]model.BlogGetResp)}
+	for i := 0; i < blogCount; i++ {
+		blog := model.BlogGetResp{BlogID: uuid.New(), UserID: users.user.UserID, Name: uuid.NewString()}
+		blogs.blogs[blog.BlogID] = blog
+		users.blogs = append(users.blogs, blog)
+	}
+	return New(blogs, users, validator.New()), blogs, users
+}
+
+func TestSchema_postsLoader(t *testing.T) {
+	schema, blogs, users := newTestSchema(postsBatchSize + 1)
+
+	resp := schema.Exec(context.Background(), `query ($id: ID!) {
+		user(id: $id) { blogs { name posts(first: 5) { nodes { title } nextCursor } } }
+	}`, "", map[string]any{"id": users.user.UserID.String()})
+	require.Empty(t, resp.Errors)
+
+	var data struct {
+		User struct {
+			Blogs []struct {
+				Name  string
+				Posts struct {
+					Nodes      []struct{ Title string }
+					NextCursor string
+				}
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+	require.Len(t, data.User.Blogs, postsBatchSize+1)
+	for _, blog := range data.User.Blogs {
+		require.Len(t, blog.Posts.Nodes, 1)
+		assert.Equal(t, blog.Name, blog.Posts.Nodes[0].Title)
+		assert.Equal(t, "next", blog.Posts.NextCursor)
+	}
+
+	// the first pages are loaded in full batches, not blog by blog
+	assert.Zero(t, blogs.pagesCalls)
+	var loaded int
+	for _, batch := range blogs.batches {
+		assert.LessOrEqual(t, len(batch), postsBatchSize)
+		loaded += len(batch)
+	}
+	assert.Equal(t, postsBatchSize+1, loaded)
+	assert.Less(t, len(blogs.batches), 4)
+}
+
+func TestSchema_Exec(t *testing.T) {
+	schema, blogs, _ := newTestSchema(1)
+	var blogID uuid.UUID
+	for id := range blogs.blogs {
+		blogID = id
+	}
+	postID := uuid.New()
+	userCtx := auth.WithUserID(context.Background(), uuid.New())
+
+	t.Run("next page", func(t *testing.T) {
+		resp := schema.Exec(context.Background(), `query ($id: ID!) { blog(id: $id) { posts(after: "next") { nodes { title } } } }`,
+			"", map[string]any{"id": blogID.String()})
+		require.Empty(t, resp.Errors)
+		assert.Equal(t, 1, blogs.pagesCalls)
+	})
+
+	t.Run("update post", func(t *testing.T) {
+		resp := schema.Exec(userCtx, `mutation ($blogId: ID!, $id: ID!) {
+			updatePost(blogId: $blogId, id: $id, input: {title: "title", text: "text", tags: []}, version: 2) { title version }
+		}`, "", map[string]any{"blogId": blogID.String(), "id": postID.String()})
+		require.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"updatePost": {"title": "title", "version": 3}}`, string(resp.Data))
+		assert.Equal(t, model.PostPutReq{PostID: postID, BlogID: blogID, Title: "title", Text: "text", Tags: []string{}, Version: 2}, blogs.updated)
+	})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		query     string
+		variables map[string]any
+		code      string
+	}{
+		{"not found", context.Background(), `query ($id: ID!) { blog(id: $id) { name } }`, map[string]any{"id": uuid.NewString()}, "NOT_FOUND"},
+		{"invalid id", context.Background(), `{ blog(id: "blog") { name } }`, nil, "BAD_USER_INPUT"},
+		{"invalid cursor", context.Background(), `query ($id: ID!) { blog(id: $id) { posts(after: "cursor") { nextCursor } } }`, map[string]any{"id": blogID.String()}, "BAD_USER_INPUT"},
+		{"invalid page", context.Background(), `query ($id: ID!) { blog(id: $id) { posts(first: 1000) { nextCursor } } }`, map[string]any{"id": blogID.String()}, "BAD_USER_INPUT"},
+		{"no read scope", auth.WithScopes(userCtx, []string{auth.ScopeWritePosts}), `query ($id: ID!) { blog(id: $id) { name } }`, map[string]any{"id": blogID.String()}, "FORBIDDEN"},
+		{"anonymous change", context.Background(), `mutation ($blogId: ID!, $id: ID!) {
+			updatePost(blogId: $blogId, id: $id, input: {title: "title", text: "text"}) { title }
+		}`, map[string]any{"blogId": blogID.String(), "id": postID.String()}, "UNAUTHENTICATED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := schema.Exec(tt.ctx, tt.query, "", tt.variables)
+			require.Len(t, resp.Errors, 1)
+			assert.Equal(t, tt.code, resp.Errors[0].Extensions["code"])
+		})
+	}
+}
+
+func TestHasMutation(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{name: "shorthand query", query: `{ blog(id: "1") { title } }`},
+		{name: "named query", query: `query Blog { blog(id: "1") { title } }`},
+		{name: "mutation", query: `mutation { createBlog(input: {title: "t"}) { id } }`, want: true},
+		{name: "mutation after query", query: `query A { me { id } } mutation B { deletePost(id: "1") }`, want: true},
+		{name: "field named mutation", query: `{ blog(id: "1") { mutation: title } }`},
+		{name: "string", query: `{ search(q: "mutation {") { id } }`},
+		{name: "block string", query: `{ search(q: """ \""" mutation """) { id } }`},
+		{name: "comment", query: "# mutation\n{ me { id } }"},
+		{name: "variable", query: `query ($mutation: ID!) { blog(id: $mutation) { title } }`},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HasMutation(tt.query))
+		})
+	}
+}
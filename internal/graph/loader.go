@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/google/uuid"
+)
+
+const (
+	// postsBatchWait is how long a batch collects blogs before posts are loaded
+	postsBatchWait = 2 * time.Millisecond
+	// postsBatchSize is the max number of blogs of one GetBlogsPosts call
+	postsBatchSize = 100
+)
+
+type loaderKey struct{}
+
+// postsLoader is a dataloader of the first pages of posts. Resolvers of Blog.posts run in parallel,
+// blogs asked for the same page within postsBatchWait are loaded with one GetBlogsPosts call.
+// A loader lives for one request and shares its context.
+type postsLoader struct {
+	ctx     context.Context
+	usecase usecase.BlogUsecase
+	mu      *sync.Mutex
+	batches map[postsPage]*postsBatch
+}
+
+// postsPage tells apart batches of different page sizes and tags
+type postsPage struct {
+	limit int
+	tag   string
+}
+
+type postsBatch struct {
+	blogIDs []uuid.UUID
+	done    chan struct{}
+	resp    model.BlogsPostsGetResp
+	err     error
+}
+
+func withLoader(ctx context.Context, blogs usecase.BlogUsecase) context.Context {
+	return context.WithValue(ctx, loaderKey{}, &postsLoader{
+		ctx:     ctx,
+		usecase: blogs,
+		mu:      &sync.Mutex{},
+		batches: make(map[postsPage]*postsBatch),
+	})
+}
+
+func loaderFrom(ctx context.Context) *postsLoader {
+	return ctx.Value(loaderKey{}).(*postsLoader)
+}
+
+// Load returns the first page of posts of the blog
+func (l *postsLoader) Load(ctx context.Context, blogID uuid.UUID, page postsPage) (model.PostsGetResp, error) {
+	l.mu.Lock()
+	batch, ok := l.batches[page]
+	if !ok {
+		batch = &postsBatch{done: make(chan struct{})}
+		l.batches[page] = batch
+		time.AfterFunc(postsBatchWait, func() { l.run(page, batch) })
+	}
+	if !slices.Contains(batch.blogIDs, blogID) {
+		batch.blogIDs = append(batch.blogIDs, blogID)
+	}
+	// a full batch takes no more blogs, the next blog starts a new one
+	if len(batch.blogIDs) == postsBatchSize {
+		delete(l.batches, page)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return model.PostsGetResp{}, ctx.Err()
+	}
+	if batch.err != nil {
+		return model.PostsGetResp{}, batch.err
+	}
+	return batch.resp.Posts[blogID], nil
+}
+
+func (l *postsLoader) run(page postsPage, batch *postsBatch) {
+	l.mu.Lock()
+	if l.batches[page] == batch {
+		delete(l.batches, page)
+	}
+	blogIDs := batch.blogIDs
+	l.mu.Unlock()
+
+	batch.resp, batch.err = l.usecase.GetBlogsPosts(l.ctx, model.BlogsPostsGetReq{
+		BlogIDs: blogIDs,
+		Limit:   page.limit,
+		Tag:     page.tag,
+	})
+	close(batch.done)
+}
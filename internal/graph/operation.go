@@ -0,0 +1,67 @@
+package graph
+
+import "strings"
+
+// HasMutation reports whether the document has a mutation operation. Only names outside of
+// selection sets are looked at, so a name "mutation" of a query or fragment counts too:
+// the answer may be wrong only towards a mutation.
+func HasMutation(query string) bool {
+	depth := 0
+	for i := 0; i < len(query); {
+		switch c := query[i]; {
+		case c == '#':
+			for i < len(query) && query[i] != '\n' && query[i] != '\r' {
+				i++
+			}
+		case strings.HasPrefix(query[i:], `"""`):
+			i += 3
+			for i < len(query) && !strings.HasPrefix(query[i:], `"""`) {
+				if strings.HasPrefix(query[i:], `\"""`) {
+					i++
+				}
+				i++
+			}
+			i += 3
+		case c == '"':
+			i++
+			for i < len(query) && query[i] != '"' && query[i] != '\n' {
+				if query[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+		case c == '{':
+			depth++
+			i++
+		case c == '}':
+			depth--
+			i++
+		case c == '$':
+			// variable, its name is not a keyword
+			i++
+			for i < len(query) && isNameChar(query[i]) {
+				i++
+			}
+		case isNameStart(c):
+			start := i
+			for i < len(query) && isNameChar(query[i]) {
+				i++
+			}
+			if depth == 0 && query[start:i] == "mutation" {
+				return true
+			}
+		default:
+			i++
+		}
+	}
+	return false
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || '0' <= c && c <= '9'
+}
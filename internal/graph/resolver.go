@@ -0,0 +1,406 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// Resolver is the root of queries and mutations
+type Resolver struct {
+	validate *validator.Validate
+	blogs    usecase.BlogUsecase
+	users    usecase.UserUsecase
+}
+
+// canRead checks read scope of API keys, the REST API checks it for GET requests in middleware.Auth
+func canRead(ctx context.Context) error {
+	if !auth.HasScope(ctx, auth.ScopeRead) {
+		return resolverError(apperror.ErrForbidden)
+	}
+	return nil
+}
+
+func parseID(id graphql.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, badInput("invalid id " + string(id))
+	}
+	return parsed, nil
+}
+
+func (r *Resolver) validateReq(req any) error {
+	if err := r.validate.Struct(req); err != nil {
+		return badInput(err.Error())
+	}
+	return nil
+}
+
+func (r *Resolver) Blog(ctx context.Context, args struct{ ID graphql.ID }) (*blogResolver, error) {
+	if err := canRead(ctx); err != nil {
+		return nil, err
+	}
+	blogID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return r.blog(ctx, blogID)
+}
+
+func (r *Resolver) blog(ctx context.Context, blogID uuid.UUID) (*blogResolver, error) {
+	blog, err := r.blogs.GetBlog(ctx, model.BlogGetReq{BlogID: blogID})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &blogResolver{r: r, blog: blog}, nil
+}
+
+func (r *Resolver) BlogBySlug(ctx context.Context, args struct{ Slug string }) (*blogResolver, error) {
+	if err := canRead(ctx); err != nil {
+		return nil, err
+	}
+	req := model.BlogSlugGetReq{Slug: args.Slug}
+	if err := r.validateReq(req); err != nil {
+		return nil, err
+	}
+	blog, err := r.blogs.GetBlogBySlug(ctx, req)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &blogResolver{r: r, blog: blog}, nil
+}
+
+func (r *Resolver) Post(ctx context.Context, args struct{ BlogID, ID graphql.ID }) (*postResolver, error) {
+	if err := canRead(ctx); err != nil {
+		return nil, err
+	}
+	var req model.PostGetReq
+	var err error
+	req.BlogID, err = parseID(args.BlogID)
+	if err != nil {
+		return nil, err
+	}
+	req.PostID, err = parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := r.blogs.GetPost(ctx, req)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &postResolver{r: r, post: post}, nil
+}
+
+func (r *Resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	if err := canRead(ctx); err != nil {
+		return nil, err
+	}
+	userID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return r.user(ctx, userID)
+}
+
+func (r *Resolver) user(ctx context.Context, userID uuid.UUID) (*userResolver, error) {
+	user, err := r.users.GetUser(ctx, model.UserGetReq{UserID: userID})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &userResolver{r: r, user: user}, nil
+}
+
+func (r *Resolver) Search(ctx context.Context, args struct {
+	Query  string
+	BlogID *graphql.ID
+	First  *int32
+}) ([]*searchResultResolver, error) {
+	if err := canRead(ctx); err != nil {
+		return nil, err
+	}
+	req := model.PostsSearchReq{Query: args.Query}
+	if args.BlogID != nil {
+		blogID, err := parseID(*args.BlogID)
+		if err != nil {
+			return nil, err
+		}
+		req.BlogID = &blogID
+	}
+	if args.First != nil {
+		req.Limit = int(*args.First)
+	}
+	if err := r.validateReq(req); err != nil {
+		return nil, err
+	}
+	resp, err := r.blogs.SearchPosts(ctx, req)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	res := make([]*searchResultResolver, 0, len(resp.Posts))
+	for _, post := range resp.Posts {
+		res = append(res, &searchResultResolver{post: post})
+	}
+	return res, nil
+}
+
+type blogInput struct {
+	Name string
+}
+
+func (r *Resolver) AddBlog(ctx context.Context, args struct{ Input blogInput }) (*blogResolver, error) {
+	req := model.BlogPostReq{Name: args.Input.Name}
+	if err := r.validateReq(req); err != nil {
+		return nil, err
+	}
+	resp, err := r.blogs.AddBlog(ctx, req)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return r.blog(ctx, resp.BlogID)
+}
+
+func (r *Resolver) UpdateBlog(ctx context.Context, args struct {
+	ID      graphql.ID
+	Input   blogInput
+	Version *int32
+}) (*blogResolver, error) {
+	req := model.BlogPutReq{Name: args.Input.Name}
+	var err error
+	req.BlogID, err = parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if args.Version != nil {
+		req.Version = int(*args.Version)
+	}
+	if err := r.validateReq(req); err != nil {
+		return nil, err
+	}
+	resp, err := r.blogs.UpdateBlog(ctx, req)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &blogResolver{r: r, blog: model.BlogGetResp{
+		BlogID:    resp.BlogID,
+		UserID:    resp.UserID,
+		Name:      resp.Name,
+		Slug:      resp.Slug,
+		CreatedAt: resp.CreatedAt,
+		Version:   resp.Version,
+	}}, nil
+}
+
+func (r *Resolver) DeleteBlog(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	blogID, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	if err := r.blogs.DeleteBlog(ctx, model.BlogDeleteReq{BlogID: blogID}); err != nil {
+		return false, resolverError(err)
+	}
+	return true, nil
+}
+
+func (r *Resolver) RestoreBlog(ctx context.Context, args struct{ ID graphql.ID }) (*blogResolver, error) {
+	blogID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	blog, err := r.blogs.RestoreBlog(ctx, model.BlogRestoreReq{BlogID: blogID})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &blogResolver{r: r, blog: blog}, nil
+}
+
+type addPostInput struct {
+	Title     string
+	Text      string
+	Tags      *[]string
+	Status    *string
+	PublishAt *graphql.Time
+}
+
+func (r *Resolver) AddPost(ctx context.Context, args struct {
+	BlogID graphql.ID
+	Input  addPostInput
+}) (*postResolver, error) {
+	req := model.PostPostReq{
+		Title:     args.Input.Title,
+		Text:      args.Input.Text,
+		PublishAt: timeArg(args.Input.PublishAt),
+	}
+	if args.Input.Tags != nil {
+		req.Tags = *args.Input.Tags
+	}
+	if args.Input.Status != nil {
+		req.Status = *args.Input.Status
+	}
+	var err error
+	req.BlogID, err = parseID(args.BlogID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.validateReq(req); err != nil {
+		return nil, err
+	}
+	resp, err := r.blogs.AddPost(ctx, req)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	post, err := r.blogs.GetPost(ctx, model.PostGetReq{BlogID: req.BlogID, PostID: resp.PostID})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &postResolver{r: r, post: post}, nil
+}
+
+type updatePostInput struct {
+	Title     string
+	Text      string
+	Tags      *[]string
+	Status    *string
+	PublishAt *graphql.Time
+}
+
+func (r *Resolver) UpdatePost(ctx context.Context, args struct {
+	BlogID  graphql.ID
+	ID      graphql.ID
+	Input   updatePostInput
+	Version *int32
+}) (*postResolver, error) {
+	req := model.PostPutReq{
+		Title:     args.Input.Title,
+		Text:      args.Input.Text,
+		PublishAt: timeArg(args.Input.PublishAt),
+	}
+	// omitted tags are left as is, an empty list removes them
+	if args.Input.Tags != nil {
+		req.Tags = append([]string{}, *args.Input.Tags...)
+	}
+	if args.Input.Status != nil {
+		req.Status = *args.Input.Status
+	}
+	if args.Version != nil {
+		req.Version = int(*args.Version)
+	}
+	var err error
+	req.BlogID, err = parseID(args.BlogID)
+	if err != nil {
+		return nil, err
+	}
+	req.PostID, err = parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.validateReq(req); err != nil {
+		return nil, err
+	}
+	resp, err := r.blogs.UpdatePost(ctx, req)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &postResolver{r: r, post: model.PostGetResp{
+		PostID:    resp.PostID,
+		BlogID:    resp.BlogID,
+		Title:     resp.Title,
+		Slug:      resp.Slug,
+		Text:      resp.Text,
+		Status:    resp.Status,
+		PublishAt: resp.PublishAt,
+		CreatedAt: resp.CreatedAt,
+		Version:   resp.Version,
+		Tags:      resp.Tags,
+	}}, nil
+}
+
+func (r *Resolver) DeletePost(ctx context.Context, args struct{ BlogID, ID graphql.ID }) (bool, error) {
+	var req model.PostDeleteReq
+	var err error
+	req.BlogID, err = parseID(args.BlogID)
+	if err != nil {
+		return false, err
+	}
+	req.PostID, err = parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	if err := r.blogs.DeletePost(ctx, req); err != nil {
+		return false, resolverError(err)
+	}
+	return true, nil
+}
+
+func (r *Resolver) RestorePost(ctx context.Context, args struct{ BlogID, ID graphql.ID }) (*postResolver, error) {
+	var req model.PostRestoreReq
+	var err error
+	req.BlogID, err = parseID(args.BlogID)
+	if err != nil {
+		return nil, err
+	}
+	req.PostID, err = parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := r.blogs.RestorePost(ctx, req)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &postResolver{r: r, post: post}, nil
+}
+
+type userInput struct {
+	DisplayName string
+	Email       string
+	Bio         *string
+}
+
+func (r *Resolver) AddUser(ctx context.Context, args struct{ Input userInput }) (*userResolver, error) {
+	req := model.UserPostReq{DisplayName: args.Input.DisplayName, Email: args.Input.Email}
+	if args.Input.Bio != nil {
+		req.Bio = *args.Input.Bio
+	}
+	if err := r.validateReq(req); err != nil {
+		return nil, err
+	}
+	resp, err := r.users.AddUser(ctx, req)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return r.user(ctx, resp.UserID)
+}
+
+func (r *Resolver) UpdateUser(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input userInput
+}) (*userResolver, error) {
+	req := model.UserPutReq{DisplayName: args.Input.DisplayName, Email: args.Input.Email}
+	if args.Input.Bio != nil {
+		req.Bio = *args.Input.Bio
+	}
+	var err error
+	req.UserID, err = parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.validateReq(req); err != nil {
+		return nil, err
+	}
+	resp, err := r.users.UpdateUser(ctx, req)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &userResolver{r: r, user: model.UserGetResp{
+		UserID:      resp.UserID,
+		DisplayName: resp.DisplayName,
+		Email:       resp.Email,
+		Bio:         resp.Bio,
+		CreatedAt:   resp.CreatedAt,
+	}}, nil
+}
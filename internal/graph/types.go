@@ -0,0 +1,248 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type blogResolver struct {
+	r    *Resolver
+	blog model.BlogGetResp
+}
+
+func (b *blogResolver) ID() graphql.ID {
+	return graphql.ID(b.blog.BlogID.String())
+}
+
+func (b *blogResolver) Name() string {
+	return b.blog.Name
+}
+
+func (b *blogResolver) Slug() string {
+	return b.blog.Slug
+}
+
+func (b *blogResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: b.blog.CreatedAt}
+}
+
+func (b *blogResolver) Version() int32 {
+	return int32(b.blog.Version)
+}
+
+func (b *blogResolver) Owner(ctx context.Context) (*userResolver, error) {
+	return b.r.user(ctx, b.blog.UserID)
+}
+
+// Posts of the first page go through the loader, next pages are asked for one blog at a time
+func (b *blogResolver) Posts(ctx context.Context, args struct {
+	First *int32
+	After *string
+	Tag   *string
+}) (*postConnectionResolver, error) {
+	req := model.PostsGetReq{BlogID: b.blog.BlogID}
+	if args.First != nil {
+		req.Limit = int(*args.First)
+	}
+	if args.After != nil {
+		req.Cursor = *args.After
+	}
+	if args.Tag != nil {
+		req.Tag = *args.Tag
+	}
+	if err := b.r.validateReq(req); err != nil {
+		return nil, err
+	}
+	var resp model.PostsGetResp
+	var err error
+	if req.Cursor == "" {
+		resp, err = loaderFrom(ctx).Load(ctx, req.BlogID, postsPage{limit: req.Limit, tag: req.Tag})
+	} else {
+		resp, err = b.r.blogs.GetPosts(ctx, req)
+	}
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &postConnectionResolver{r: b.r, posts: resp}, nil
+}
+
+func (b *blogResolver) Tags(ctx context.Context) ([]*tagResolver, error) {
+	resp, err := b.r.blogs.GetBlogTags(ctx, model.TagsGetReq{BlogID: b.blog.BlogID})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	tags := make([]*tagResolver, 0, len(resp.Tags))
+	for _, tag := range resp.Tags {
+		tags = append(tags, &tagResolver{tag: tag})
+	}
+	return tags, nil
+}
+
+type postConnectionResolver struct {
+	r     *Resolver
+	posts model.PostsGetResp
+}
+
+func (c *postConnectionResolver) Nodes() []*postResolver {
+	posts := make([]*postResolver, 0, len(c.posts.Posts))
+	for _, post := range c.posts.Posts {
+		posts = append(posts, &postResolver{r: c.r, post: post})
+	}
+	return posts
+}
+
+func (c *postConnectionResolver) NextCursor() *string {
+	if c.posts.NextCursor == "" {
+		return nil
+	}
+	return &c.posts.NextCursor
+}
+
+type postResolver struct {
+	r    *Resolver
+	post model.PostGetResp
+}
+
+func (p *postResolver) ID() graphql.ID {
+	return graphql.ID(p.post.PostID.String())
+}
+
+func (p *postResolver) Blog(ctx context.Context) (*blogResolver, error) {
+	return p.r.blog(ctx, p.post.BlogID)
+}
+
+func (p *postResolver) Title() string {
+	return p.post.Title
+}
+
+func (p *postResolver) Slug() string {
+	return p.post.Slug
+}
+
+func (p *postResolver) Text() string {
+	return p.post.Text
+}
+
+func (p *postResolver) Status() string {
+	return p.post.Status
+}
+
+func (p *postResolver) PublishAt() *graphql.Time {
+	if p.post.PublishAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *p.post.PublishAt}
+}
+
+func (p *postResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: p.post.CreatedAt}
+}
+
+func (p *postResolver) Version() int32 {
+	return int32(p.post.Version)
+}
+
+func (p *postResolver) Tags() []string {
+	if p.post.Tags == nil {
+		return []string{}
+	}
+	return p.post.Tags
+}
+
+func (p *postResolver) CommentsCount() int32 {
+	return int32(p.post.CommentsCount)
+}
+
+type tagResolver struct {
+	tag model.TagResp
+}
+
+func (t *tagResolver) Name() string {
+	return t.tag.Name
+}
+
+func (t *tagResolver) Count() int32 {
+	return int32(t.tag.Count)
+}
+
+type searchResultResolver struct {
+	post model.PostSearchResp
+}
+
+func (s *searchResultResolver) PostID() graphql.ID {
+	return graphql.ID(s.post.PostID.String())
+}
+
+func (s *searchResultResolver) BlogID() graphql.ID {
+	return graphql.ID(s.post.BlogID.String())
+}
+
+func (s *searchResultResolver) Title() string {
+	return s.post.Title
+}
+
+func (s *searchResultResolver) Slug() string {
+	return s.post.Slug
+}
+
+func (s *searchResultResolver) Snippet() string {
+	return s.post.Snippet
+}
+
+func (s *searchResultResolver) Rank() float64 {
+	return float64(s.post.Rank)
+}
+
+func (s *searchResultResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: s.post.CreatedAt}
+}
+
+type userResolver struct {
+	r    *Resolver
+	user model.UserGetResp
+}
+
+func (u *userResolver) ID() graphql.ID {
+	return graphql.ID(u.user.UserID.String())
+}
+
+func (u *userResolver) DisplayName() string {
+	return u.user.DisplayName
+}
+
+func (u *userResolver) Email() *string {
+	if u.user.Email == "" {
+		return nil
+	}
+	return &u.user.Email
+}
+
+func (u *userResolver) Bio() string {
+	return u.user.Bio
+}
+
+func (u *userResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: u.user.CreatedAt}
+}
+
+func (u *userResolver) Blogs(ctx context.Context) ([]*blogResolver, error) {
+	resp, err := u.r.users.GetUserBlogs(ctx, model.UserBlogsGetReq{UserID: u.user.UserID})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	blogs := make([]*blogResolver, 0, len(resp.Blogs))
+	for _, blog := range resp.Blogs {
+		blogs = append(blogs, &blogResolver{r: u.r, blog: blog})
+	}
+	return blogs, nil
+}
+
+func timeArg(t *graphql.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,67 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	blogv1 "github.com/Rolan335/project/api/blog/v1"
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationMetadata = "authorization"
+	bearerPrefix          = "Bearer "
+	apiKeyPrefix          = "ApiKey "
+)
+
+// readMethods need read scope of API keys, changes are checked by usecases
+var readMethods = map[string]bool{
+	blogv1.BlogService_GetBlog_FullMethodName:     true,
+	blogv1.BlogService_GetPost_FullMethodName:     true,
+	blogv1.BlogService_GetPosts_FullMethodName:    true,
+	blogv1.BlogService_SearchPosts_FullMethodName: true,
+}
+
+// Auth puts the caller of bearer token or API key from authorization metadata into context,
+// it is the grpc counterpart of middleware.Auth
+func Auth(verifier *auth.Verifier, keys usecase.APIKeyUsecase) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var header string
+		if values := metadata.ValueFromIncomingContext(ctx, authorizationMetadata); len(values) > 0 {
+			header = values[0]
+		}
+		switch {
+		case header == "":
+			return handler(ctx, req)
+		case strings.HasPrefix(header, bearerPrefix):
+			userID, err := verifier.Verify(strings.TrimPrefix(header, bearerPrefix))
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, "invalid token")
+			}
+			return handler(auth.WithUserID(ctx, userID), req)
+		case strings.HasPrefix(header, apiKeyPrefix):
+			key, err := keys.AuthenticateAPIKey(ctx, strings.TrimPrefix(header, apiKeyPrefix))
+			if err != nil {
+				if errors.Is(err, apperror.ErrUnauthorized) {
+					return nil, status.Error(codes.Unauthenticated, "invalid api key")
+				}
+				log.Err(err).Msg("")
+				return nil, status.Error(codes.Internal, "internal error")
+			}
+			ctx = auth.WithScopes(auth.WithUserID(ctx, key.UserID), key.Scopes)
+			if readMethods[info.FullMethod] && !auth.HasScope(ctx, auth.ScopeRead) {
+				return nil, status.Error(codes.PermissionDenied, "read scope required")
+			}
+			return handler(ctx, req)
+		}
+		return nil, status.Error(codes.Unauthenticated, "unsupported authorization")
+	}
+}
@@ -0,0 +1,64 @@
+package grpcserver
+
+import (
+	"time"
+
+	blogv1 "github.com/Rolan335/project/api/blog/v1"
+	"github.com/Rolan335/project/internal/model"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func blogToProto(blog model.BlogGetResp) *blogv1.Blog {
+	return &blogv1.Blog{
+		Id:        blog.BlogID.String(),
+		UserId:    blog.UserID.String(),
+		Name:      blog.Name,
+		Slug:      blog.Slug,
+		CreatedAt: timestamppb.New(blog.CreatedAt),
+		Version:   int32(blog.Version),
+	}
+}
+
+func postToProto(post model.PostGetResp) *blogv1.Post {
+	return &blogv1.Post{
+		PostId:        post.PostID.String(),
+		BlogId:        post.BlogID.String(),
+		Title:         post.Title,
+		Slug:          post.Slug,
+		Text:          post.Text,
+		Html:          post.HTML,
+		Status:        post.Status,
+		PublishAt:     timeToProto(post.PublishAt),
+		CreatedAt:     timestamppb.New(post.CreatedAt),
+		Version:       int32(post.Version),
+		Tags:          post.Tags,
+		CommentsCount: int32(post.CommentsCount),
+	}
+}
+
+func searchResultToProto(post model.PostSearchResp) *blogv1.PostSearchResult {
+	return &blogv1.PostSearchResult{
+		PostId:    post.PostID.String(),
+		BlogId:    post.BlogID.String(),
+		Title:     post.Title,
+		Slug:      post.Slug,
+		Snippet:   post.Snippet,
+		Rank:      post.Rank,
+		CreatedAt: timestamppb.New(post.CreatedAt),
+	}
+}
+
+func timeToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
@@ -0,0 +1,37 @@
+package grpcserver
+
+import (
+	"errors"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError maps usecase errors to grpc status, unknown errors are logged and hidden from the client
+func statusError(err error) error {
+	if errors.Is(err, apperror.ErrNotFound) {
+		return status.Error(codes.NotFound, apperror.ErrNotFound.Error())
+	}
+	if errors.Is(err, apperror.ErrInvalidCursor) {
+		return status.Error(codes.InvalidArgument, apperror.ErrInvalidCursor.Error())
+	}
+	if errors.Is(err, apperror.ErrVersionMismatch) {
+		return status.Error(codes.FailedPrecondition, apperror.ErrVersionMismatch.Error())
+	}
+	if errors.Is(err, apperror.ErrConflict) {
+		return status.Error(codes.AlreadyExists, apperror.ErrConflict.Error())
+	}
+	if errors.Is(err, apperror.ErrUnauthorized) {
+		return status.Error(codes.Unauthenticated, apperror.ErrUnauthorized.Error())
+	}
+	if errors.Is(err, apperror.ErrNotRegistered) {
+		return status.Error(codes.PermissionDenied, apperror.ErrNotRegistered.Error())
+	}
+	if errors.Is(err, apperror.ErrForbidden) {
+		return status.Error(codes.PermissionDenied, apperror.ErrForbidden.Error())
+	}
+	log.Err(err).Msg("")
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,75 @@
+package grpcserver
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/metric"
+	"github.com/Rolan335/project/internal/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const retryAfterMetadata = "retry-after"
+
+// IPRateLimit limits calls of the peer address. It goes before Auth, so that checking tokens and API keys
+// is limited too, it is the grpc counterpart of middleware.IPRateLimit.
+func IPRateLimit(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := allow(ctx, limiter, "ip:"+peerIP(ctx), info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RateLimit limits calls of authenticated user or peer address after Auth. Reads are counted against
+// readLimiter and changes against writeLimiter, the same limiters as of the REST API.
+func RateLimit(readLimiter, writeLimiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		key := "ip:" + peerIP(ctx)
+		if userID, ok := auth.UserID(ctx); ok {
+			key = "user:" + userID.String()
+		}
+		limiter := writeLimiter
+		if readMethods[info.FullMethod] {
+			limiter = readLimiter
+		}
+		if err := allow(ctx, limiter, key, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func allow(ctx context.Context, limiter *ratelimit.Limiter, key, method string) error {
+	res := limiter.Allow(key)
+	if res.Allowed {
+		return nil
+	}
+	metric.RateLimitRejections.WithLabelValues("GRPC", method).Inc()
+	retryAfter := strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds())))
+	if err := grpc.SetHeader(ctx, metadata.Pairs(retryAfterMetadata, retryAfter)); err != nil {
+		return status.Error(codes.Internal, "internal error")
+	}
+	return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+}
+
+// peerIP returns host of the peer address, the whole address when it has no port
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
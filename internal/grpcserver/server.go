@@ -0,0 +1,270 @@
+package grpcserver
+
+import (
+	"context"
+
+	blogv1 "github.com/Rolan335/project/api/blog/v1"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/ratelimit"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Server serves blogs and posts over gRPC with the usecase of the REST API
+type Server struct {
+	blogv1.UnimplementedBlogServiceServer
+	validate *validator.Validate
+	usecase  usecase.BlogUsecase
+}
+
+func New(usecase usecase.BlogUsecase, validate *validator.Validate) *Server {
+	return &Server{
+		validate: validate,
+		usecase:  usecase,
+	}
+}
+
+// NewGRPCServer returns grpc server with BlogService registered, requests are traced,
+// authenticated and rate limited like requests of the REST API
+func NewGRPCServer(srv *Server, verifier *auth.Verifier, keys usecase.APIKeyUsecase, ipLimiter, readLimiter, writeLimiter *ratelimit.Limiter) *grpc.Server {
+	s := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			IPRateLimit(ipLimiter),
+			Auth(verifier, keys),
+			RateLimit(readLimiter, writeLimiter),
+		),
+	)
+	blogv1.RegisterBlogServiceServer(s, srv)
+	return s
+}
+
+func (s *Server) GetBlog(ctx context.Context, in *blogv1.GetBlogRequest) (*blogv1.Blog, error) {
+	var req model.BlogGetReq
+	var err error
+	req.BlogID, err = parseID(in.GetBlogId())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.usecase.GetBlog(ctx, req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return blogToProto(resp), nil
+}
+
+func (s *Server) AddBlog(ctx context.Context, in *blogv1.AddBlogRequest) (*blogv1.AddBlogResponse, error) {
+	req := model.BlogPostReq{Name: in.GetName()}
+	if err := s.validate.Struct(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	resp, err := s.usecase.AddBlog(ctx, req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &blogv1.AddBlogResponse{Id: resp.BlogID.String(), Slug: resp.Slug}, nil
+}
+
+func (s *Server) UpdateBlog(ctx context.Context, in *blogv1.UpdateBlogRequest) (*blogv1.Blog, error) {
+	req := model.BlogPutReq{Name: in.GetName(), Version: int(in.GetVersion())}
+	var err error
+	req.BlogID, err = parseID(in.GetBlogId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	resp, err := s.usecase.UpdateBlog(ctx, req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return blogToProto(model.BlogGetResp{
+		BlogID:    resp.BlogID,
+		UserID:    resp.UserID,
+		Name:      resp.Name,
+		Slug:      resp.Slug,
+		CreatedAt: resp.CreatedAt,
+		Version:   resp.Version,
+	}), nil
+}
+
+func (s *Server) DeleteBlog(ctx context.Context, in *blogv1.DeleteBlogRequest) (*emptypb.Empty, error) {
+	var req model.BlogDeleteReq
+	var err error
+	req.BlogID, err = parseID(in.GetBlogId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.usecase.DeleteBlog(ctx, req); err != nil {
+		return nil, statusError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) GetPost(ctx context.Context, in *blogv1.GetPostRequest) (*blogv1.Post, error) {
+	req := model.PostGetReq{RenderHTML: in.GetRenderHtml()}
+	var err error
+	req.BlogID, err = parseID(in.GetBlogId())
+	if err != nil {
+		return nil, err
+	}
+	req.PostID, err = parseID(in.GetPostId())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.usecase.GetPost(ctx, req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return postToProto(resp), nil
+}
+
+func (s *Server) GetPosts(ctx context.Context, in *blogv1.GetPostsRequest) (*blogv1.GetPostsResponse, error) {
+	req := model.PostsGetReq{
+		Limit:      int(in.GetLimit()),
+		Cursor:     in.GetCursor(),
+		Tag:        in.GetTag(),
+		RenderHTML: in.GetRenderHtml(),
+	}
+	var err error
+	req.BlogID, err = parseID(in.GetBlogId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	resp, err := s.usecase.GetPosts(ctx, req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := &blogv1.GetPostsResponse{
+		Posts:      make([]*blogv1.Post, 0, len(resp.Posts)),
+		NextCursor: resp.NextCursor,
+	}
+	for _, post := range resp.Posts {
+		out.Posts = append(out.Posts, postToProto(post))
+	}
+	return out, nil
+}
+
+func (s *Server) SearchPosts(ctx context.Context, in *blogv1.SearchPostsRequest) (*blogv1.SearchPostsResponse, error) {
+	req := model.PostsSearchReq{Query: in.GetQuery(), Limit: int(in.GetLimit())}
+	if in.GetBlogId() != "" {
+		blogID, err := parseID(in.GetBlogId())
+		if err != nil {
+			return nil, err
+		}
+		req.BlogID = &blogID
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	resp, err := s.usecase.SearchPosts(ctx, req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := &blogv1.SearchPostsResponse{Posts: make([]*blogv1.PostSearchResult, 0, len(resp.Posts))}
+	for _, post := range resp.Posts {
+		out.Posts = append(out.Posts, searchResultToProto(post))
+	}
+	return out, nil
+}
+
+func (s *Server) AddPost(ctx context.Context, in *blogv1.AddPostRequest) (*blogv1.AddPostResponse, error) {
+	req := model.PostPostReq{
+		Title:     in.GetTitle(),
+		Text:      in.GetText(),
+		Tags:      in.GetTags(),
+		Status:    in.GetStatus(),
+		PublishAt: timeFromProto(in.GetPublishAt()),
+	}
+	var err error
+	req.BlogID, err = parseID(in.GetBlogId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	resp, err := s.usecase.AddPost(ctx, req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &blogv1.AddPostResponse{PostId: resp.PostID.String(), Slug: resp.Slug, Status: resp.Status}, nil
+}
+
+func (s *Server) UpdatePost(ctx context.Context, in *blogv1.UpdatePostRequest) (*blogv1.Post, error) {
+	req := model.PostPutReq{
+		Title:     in.GetTitle(),
+		Text:      in.GetText(),
+		Status:    in.GetStatus(),
+		PublishAt: timeFromProto(in.GetPublishAt()),
+		Version:   int(in.GetVersion()),
+	}
+	// tags are left as is unless the message is set, an empty message removes them
+	if in.GetTags() != nil {
+		req.Tags = append([]string{}, in.GetTags().GetTags()...)
+	}
+	var err error
+	req.BlogID, err = parseID(in.GetBlogId())
+	if err != nil {
+		return nil, err
+	}
+	req.PostID, err = parseID(in.GetPostId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	resp, err := s.usecase.UpdatePost(ctx, req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return postToProto(model.PostGetResp{
+		PostID:    resp.PostID,
+		BlogID:    resp.BlogID,
+		Title:     resp.Title,
+		Slug:      resp.Slug,
+		Text:      resp.Text,
+		Status:    resp.Status,
+		PublishAt: resp.PublishAt,
+		CreatedAt: resp.CreatedAt,
+		Version:   resp.Version,
+		Tags:      resp.Tags,
+	}), nil
+}
+
+func (s *Server) DeletePost(ctx context.Context, in *blogv1.DeletePostRequest) (*emptypb.Empty, error) {
+	var req model.PostDeleteReq
+	var err error
+	req.BlogID, err = parseID(in.GetBlogId())
+	if err != nil {
+		return nil, err
+	}
+	req.PostID, err = parseID(in.GetPostId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.usecase.DeletePost(ctx, req); err != nil {
+		return nil, statusError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func parseID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid id %q", s)
+	}
+	return id, nil
+}
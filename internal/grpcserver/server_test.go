@@ -0,0 +1,238 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	blogv1 "github.com/Rolan335/project/api/blog/v1"
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/ratelimit"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// blogUsecase knows one blog, changes need an authenticated caller
+type blogUsecase struct {
+	usecase.BlogUsecase
+	blog  model.BlogGetResp
+	added []model.PostPostReq
+}
+
+func (u *blogUsecase) GetBlog(_ context.Context, req model.BlogGetReq) (model.BlogGetResp, error) {
+	if req.BlogID != u.blog.BlogID {
+		return model.BlogGetResp{}, errors.Wrap(apperror.ErrNotFound, "usercase.BlogProvider.GetBlog")
+	}
+	return u.blog, nil
+}
+
+func (u *blogUsecase) AddPost(ctx context.Context, req model.PostPostReq) (model.PostPostResp, error) {
+	if _, ok := auth.UserID(ctx); !ok {
+		return model.PostPostResp{}, apperror.ErrUnauthorized
+	}
+	if !auth.HasScope(ctx, auth.ScopeWritePosts) {
+		return model.PostPostResp{}, apperror.ErrForbidden
+	}
+	u.added = append(u.added, req)
+	return model.PostPostResp{PostID: uuid.New(), Slug: "post", Status: model.PostStatusPublished}, nil
+}
+
+// AddBlog is called by callers which have not registered as users
+func (u *blogUsecase) AddBlog(_ context.Context, _ model.BlogPostReq) (model.BlogPostResp, error) {
+	return model.BlogPostResp{}, errors.Wrap(apperror.ErrNotRegistered, "usercase.BlogProvider.AddBlog")
+}
+
+func (u *blogUsecase) UpdateBlog(_ context.Context, req model.BlogPutReq) (model.BlogPutResp, error) {
+	if req.Version != u.blog.Version {
+		return model.BlogPutResp{}, apperror.ErrVersionMismatch
+	}
+	return model.BlogPutResp{}, errors.New("connection refused")
+}
+
+// apiKeys grants every key the scope named by the key, "unknown" key is rejected
+type apiKeys struct {
+	usecase.APIKeyUsecase
+	userID uuid.UUID
+}
+
+func (k *apiKeys) AuthenticateAPIKey(_ context.Context, key string) (model.APIKeyAuthResp, error) {
+	if key == "unknown" {
+		return model.APIKeyAuthResp{}, apperror.ErrUnauthorized
+	}
+	return model.APIKeyAuthResp{UserID: k.userID, Scopes: []string{key}}, nil
+}
+
+func startServer(t *testing.T, uc usecase.BlogUsecase, keys usecase.APIKeyUsecase) blogv1.BlogServiceClient {
+	limiter := ratelimit.New(100, 100)
+	return serve(t, NewGRPCServer(New(uc, validator.New()), auth.NewVerifier([]byte("secret"), nil), keys, limiter, limiter, limiter))
+}
+
+func serve(t *testing.T, srv *grpc.Server) blogv1.BlogServiceClient {
+	ln := bufconn.Listen(1 << 20)
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return blogv1.NewBlogServiceClient(conn)
+}
+
+func withAuthorization(value string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), authorizationMetadata, value)
+}
+
+func TestServer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	blogID := uuid.New()
+	uc := &blogUsecase{blog: model.BlogGetResp{BlogID: blogID, UserID: uuid.New(), Name: "blog", Slug: "blog", Version: 3}}
+	client := startServer(t, uc, &apiKeys{userID: uuid.New()})
+
+	t.Run("get blog", func(t *testing.T) {
+		blog, err := client.GetBlog(context.Background(), &blogv1.GetBlogRequest{BlogId: blogID.String()})
+		require.NoError(t, err)
+		assert.Equal(t, blogID.String(), blog.GetId())
+		assert.Equal(t, "blog", blog.GetSlug())
+		assert.Equal(t, int32(3), blog.GetVersion())
+	})
+
+	t.Run("add post", func(t *testing.T) {
+		resp, err := client.AddPost(withAuthorization("ApiKey "+auth.ScopeWritePosts), &blogv1.AddPostRequest{
+			BlogId: blogID.String(),
+			Title:  "title",
+			Text:   "text",
+			Tags:   []string{"go"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "post", resp.GetSlug())
+		require.Len(t, uc.added, 1)
+		assert.Equal(t, model.PostPostReq{BlogID: blogID, Title: "title", Text: "text", Tags: []string{"go"}}, uc.added[0])
+	})
+
+	t.Run("traced", func(t *testing.T) {
+		var names []string
+		for _, span := range recorder.Ended() {
+			names = append(names, span.Name())
+		}
+		assert.Contains(t, names, "blog.v1.BlogService/GetBlog")
+	})
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"unknown blog", func() error {
+			_, err := client.GetBlog(context.Background(), &blogv1.GetBlogRequest{BlogId: uuid.NewString()})
+			return err
+		}, codes.NotFound},
+		{"invalid id", func() error {
+			_, err := client.GetBlog(context.Background(), &blogv1.GetBlogRequest{BlogId: "blog"})
+			return err
+		}, codes.InvalidArgument},
+		{"invalid post", func() error {
+			_, err := client.AddPost(withAuthorization("ApiKey "+auth.ScopeWritePosts), &blogv1.AddPostRequest{BlogId: blogID.String()})
+			return err
+		}, codes.InvalidArgument},
+		{"anonymous", func() error {
+			_, err := client.AddPost(context.Background(), &blogv1.AddPostRequest{BlogId: blogID.String(), Title: "title", Text: "text"})
+			return err
+		}, codes.Unauthenticated},
+		{"invalid token", func() error {
+			_, err := client.GetBlog(withAuthorization("Bearer token"), &blogv1.GetBlogRequest{BlogId: blogID.String()})
+			return err
+		}, codes.Unauthenticated},
+		{"unknown api key", func() error {
+			_, err := client.GetBlog(withAuthorization("ApiKey unknown"), &blogv1.GetBlogRequest{BlogId: blogID.String()})
+			return err
+		}, codes.Unauthenticated},
+		{"no read scope", func() error {
+			_, err := client.GetBlog(withAuthorization("ApiKey "+auth.ScopeWritePosts), &blogv1.GetBlogRequest{BlogId: blogID.String()})
+			return err
+		}, codes.PermissionDenied},
+		{"no write scope", func() error {
+			_, err := client.AddPost(withAuthorization("ApiKey "+auth.ScopeRead), &blogv1.AddPostRequest{BlogId: blogID.String(), Title: "title", Text: "text"})
+			return err
+		}, codes.PermissionDenied},
+		{"not registered", func() error {
+			_, err := client.AddBlog(context.Background(), &blogv1.AddBlogRequest{Name: "blog"})
+			return err
+		}, codes.PermissionDenied},
+		{"version mismatch", func() error {
+			_, err := client.UpdateBlog(context.Background(), &blogv1.UpdateBlogRequest{BlogId: blogID.String(), Name: "blog", Version: 2})
+			return err
+		}, codes.FailedPrecondition},
+		{"internal error", func() error {
+			_, err := client.UpdateBlog(context.Background(), &blogv1.UpdateBlogRequest{BlogId: blogID.String(), Name: "blog", Version: 3})
+			return err
+		}, codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			assert.Equal(t, tt.code, status.Code(err), err)
+		})
+	}
+}
+
+func TestServer_rateLimit(t *testing.T) {
+	blogID := uuid.New()
+	uc := &blogUsecase{blog: model.BlogGetResp{BlogID: blogID, UserID: uuid.New(), Name: "blog", Slug: "blog"}}
+	newClient := func(ipLimiter, readLimiter, writeLimiter *ratelimit.Limiter) blogv1.BlogServiceClient {
+		srv := NewGRPCServer(New(uc, validator.New()), auth.NewVerifier([]byte("secret"), nil), &apiKeys{userID: uuid.New()},
+			ipLimiter, readLimiter, writeLimiter)
+		return serve(t, srv)
+	}
+	getBlog := func(client blogv1.BlogServiceClient, ctx context.Context) error {
+		_, err := client.GetBlog(ctx, &blogv1.GetBlogRequest{BlogId: blogID.String()})
+		return err
+	}
+	addPost := func(client blogv1.BlogServiceClient, ctx context.Context) error {
+		_, err := client.AddPost(ctx, &blogv1.AddPostRequest{BlogId: blogID.String(), Title: "title", Text: "text"})
+		return err
+	}
+
+	t.Run("peer before authentication", func(t *testing.T) {
+		client := newClient(ratelimit.New(1, 1), ratelimit.New(100, 100), ratelimit.New(100, 100))
+		assert.Equal(t, codes.Unauthenticated, status.Code(getBlog(client, withAuthorization("ApiKey unknown"))))
+		var header metadata.MD
+		_, err := client.GetBlog(withAuthorization("ApiKey unknown"), &blogv1.GetBlogRequest{BlogId: blogID.String()}, grpc.Header(&header))
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Equal(t, []string{"1"}, header.Get(retryAfterMetadata))
+	})
+
+	t.Run("reads and writes", func(t *testing.T) {
+		client := newClient(ratelimit.New(100, 100), ratelimit.New(1, 1), ratelimit.New(1, 1))
+		ctx := withAuthorization("ApiKey " + auth.ScopeWritePosts)
+		assert.NoError(t, addPost(client, ctx))
+		assert.Equal(t, codes.ResourceExhausted, status.Code(addPost(client, ctx)))
+		// reads of the same user have their own bucket
+		ctx = withAuthorization("ApiKey " + auth.ScopeRead)
+		assert.NoError(t, getBlog(client, ctx))
+		assert.Equal(t, codes.ResourceExhausted, status.Code(getBlog(client, ctx)))
+	})
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *Handler) CreateAPIKey(c *fiber.Ctx) error {
+	var req model.APIKeyPostReq
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.ErrBadRequest
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.keys.AddAPIKey(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotRegistered) {
+			return fiber.NewError(fiber.StatusForbidden, apperror.ErrNotRegistered.Error())
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) GetAPIKeys(c *fiber.Ctx) error {
+	resp, err := h.keys.GetAPIKeys(c.UserContext())
+	if err != nil {
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) RevokeAPIKey(c *fiber.Ctx) error {
+	keyID, err := uuid.Parse(c.Params(KeyIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	if err := h.keys.RevokeAPIKey(c.UserContext(), model.APIKeyDeleteReq{KeyID: keyID}); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *Handler) CreateComment(c *fiber.Ctx) error {
+	var req model.CommentPostReq
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.ErrBadRequest
+	}
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.PostID, err = uuid.Parse(c.Params(PostIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.comments.AddComment(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrNotRegistered) {
+			return fiber.NewError(fiber.StatusForbidden, apperror.ErrNotRegistered.Error())
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) GetComments(c *fiber.Ctx) error {
+	var req model.CommentsGetReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.PostID, err = uuid.Parse(c.Params(PostIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	if parentID := c.Query(ParentIDQuery); parentID != "" {
+		id, err := uuid.Parse(parentID)
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		req.ParentID = &id
+	}
+	if limit := c.Query(LimitQuery); limit != "" {
+		req.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+	}
+	req.Cursor = c.Query(CursorQuery)
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.comments.GetComments(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrInvalidCursor) {
+			return fiber.ErrBadRequest
+		}
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) UpdateComment(c *fiber.Ctx) error {
+	var req model.CommentPutReq
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.ErrBadRequest
+	}
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.PostID, err = uuid.Parse(c.Params(PostIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.CommentID, err = uuid.Parse(c.Params(CommentIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.comments.UpdateComment(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) DeleteComment(c *fiber.Ctx) error {
+	var req model.CommentDeleteReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.PostID, err = uuid.Parse(c.Params(PostIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.CommentID, err = uuid.Parse(c.Params(CommentIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	if err := h.comments.DeleteComment(c.UserContext(), req); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Rolan335/project/internal/openapi"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+	"github.com/rs/zerolog/log"
+)
+
+// OpenAPI serves the OpenAPI document of the API, it is encoded once
+func (h *Handler) OpenAPI() fiber.Handler {
+	doc, err := json.Marshal(openapi.Build())
+	return func(c *fiber.Ctx) error {
+		if err != nil {
+			log.Err(err).Msg("")
+			return fiber.ErrInternalServerError
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+		return c.Send(doc)
+	}
+}
+
+// Docs serves Swagger UI, it has to be mounted with Use at /api/docs
+func (h *Handler) Docs() fiber.Handler {
+	return filesystem.New(filesystem.Config{Root: http.FS(openapi.UI)})
+}
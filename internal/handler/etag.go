@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// etag is a strong entity tag made of entity version, it is set on responses whose body is defined by the version
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// postETag is a strong entity tag of post representation. Comments count and rendered html change
+// the body without changing the version, so they follow the version in the tag: "3-5" or "3-5-html".
+func postETag(post model.PostGetResp, html bool) string {
+	tag := strconv.Itoa(post.Version) + "-" + strconv.Itoa(post.CommentsCount)
+	if html {
+		tag += "-html"
+	}
+	return `"` + tag + `"`
+}
+
+// ifMatchVersion returns version expected by If-Match header, zero means any version.
+// Only the version part of tag is compared, so a tag of any representation of the version matches.
+// If-Match uses strong comparison, so weak tags and tags that are not ours can't match any version
+// and are reported as failed precondition.
+func ifMatchVersion(c *fiber.Ctx) (int, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+	tag := header
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, fiber.ErrPreconditionFailed
+	}
+	tagVersion, _, _ := strings.Cut(tag[1:len(tag)-1], "-")
+	version, err := strconv.Atoi(tagVersion)
+	if err != nil || version < 1 {
+		return 0, fiber.ErrPreconditionFailed
+	}
+	return version, nil
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	app := fiber.New()
+	app.Put("/", func(c *fiber.Ctx) error {
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(strconv.Itoa(version))
+	})
+
+	testCases := []struct {
+		name       string
+		ifMatch    string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "no header", wantStatus: fiber.StatusOK, wantBody: "0"},
+		{name: "any", ifMatch: "*", wantStatus: fiber.StatusOK, wantBody: "0"},
+		{name: "strong", ifMatch: etag(3), wantStatus: fiber.StatusOK, wantBody: "3"},
+		{name: "post", ifMatch: postETag(model.PostGetResp{Version: 3, CommentsCount: 5}, true), wantStatus: fiber.StatusOK, wantBody: "3"},
+		{name: "weak", ifMatch: `W/"3"`, wantStatus: fiber.StatusPreconditionFailed},
+		{name: "unquoted", ifMatch: "3", wantStatus: fiber.StatusPreconditionFailed},
+		{name: "foreign tag", ifMatch: `"abc"`, wantStatus: fiber.StatusPreconditionFailed},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPut, "/", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set(fiber.HeaderIfMatch, tt.ifMatch)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
+
+// postETagUsecase has one post at version 1, which is updated only when If-Match version is current
+type postETagUsecase struct {
+	usecase.BlogUsecase
+}
+
+func (u *postETagUsecase) GetPost(_ context.Context, req model.PostGetReq) (model.PostGetResp, error) {
+	post := model.PostGetResp{PostID: req.PostID, BlogID: req.BlogID, Version: 1, CommentsCount: 2}
+	if req.RenderHTML {
+		post.HTML = "<p>text</p>"
+	}
+	return post, nil
+}
+
+func (u *postETagUsecase) UpdatePost(_ context.Context, req model.PostPutReq) (model.PostPutResp, error) {
+	if req.Version != 0 && req.Version != 1 {
+		return model.PostPutResp{}, apperror.ErrVersionMismatch
+	}
+	return model.PostPutResp{PostID: req.PostID, BlogID: req.BlogID, Version: 2}, nil
+}
+
+func TestPostETag(t *testing.T) {
+	h := New(&postETagUsecase{}, nil, nil, nil, nil, validator.New())
+	app := fiber.New()
+	app.Get("/blog/:blog_id/posts/:post_id", h.GetPost)
+	app.Put("/blog/:blog_id/posts/:post_id", h.UpdatePost)
+	path := "/blog/" + uuid.NewString() + "/posts/" + uuid.NewString()
+
+	tags := make(map[string]bool)
+	for _, query := range []string{"", "?render=html"} {
+		t.Run("get"+query, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path+query, nil))
+			require.NoError(t, err)
+			require.Equal(t, fiber.StatusOK, resp.StatusCode)
+			tag := resp.Header.Get(fiber.HeaderETag)
+			assert.False(t, strings.HasPrefix(tag, "W/"))
+			assert.False(t, tags[tag], "representations have the same tag")
+			tags[tag] = true
+
+			// the tag of GET is sent back as is
+			req := httptest.NewRequest(fiber.MethodPut, path, strings.NewReader(`{"title":"title","text":"text"}`))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			req.Header.Set(fiber.HeaderIfMatch, tag)
+			resp, err = app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			assert.Equal(t, etag(2), resp.Header.Get(fiber.HeaderETag))
+		})
+	}
+}
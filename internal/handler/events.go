@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	LastEventIDHeader = "Last-Event-ID"
+
+	// eventsRetry is the reconnection delay suggested to EventSource in milliseconds
+	eventsRetry = "3000"
+)
+
+// StreamPostEvents streams changes of posts of the blog as Server-Sent Events. A comment is sent every heartbeat,
+// so proxies keep the connection open and a gone client is noticed by the failed write.
+func (h *Handler) StreamPostEvents(heartbeat time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req model.PostEventsReq
+		var err error
+		req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		req.LastEventID = c.Get(LastEventIDHeader)
+		if err := h.validate.Struct(req); err != nil {
+			log.Err(err).Msg("")
+			return fiber.ErrBadRequest
+		}
+		sub, err := h.usecase.SubscribePostEvents(c.UserContext(), req)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return fiber.ErrNotFound
+			}
+			log.Err(err).Msg("")
+			return fiber.ErrInternalServerError
+		}
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+		// nginx buffers responses by default, events must go out as they come
+		c.Set("X-Accel-Buffering", "no")
+		// fiber.Ctx is released when handler returns, the stream writer must not touch it
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer sub.Close()
+			w.WriteString("retry: " + eventsRetry + "\n\n")
+			if sub.Missed {
+				writeEvent(w, "", usecase.EventPostsReset, []byte("{}"))
+			}
+			for _, e := range sub.Replay {
+				writeEvent(w, e.ID, e.Type, e.Data)
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+			ticker := time.NewTicker(heartbeat)
+			defer ticker.Stop()
+			for {
+				select {
+				case e, ok := <-sub.Events:
+					if !ok {
+						// dropped for falling behind, the client resumes with Last-Event-ID
+						return
+					}
+					writeEvent(w, e.ID, e.Type, e.Data)
+				case <-ticker.C:
+					w.WriteString(": heartbeat\n\n")
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		})
+		return nil
+	}
+}
+
+// writeEvent writes one event in text/event-stream format, every line of data gets its own data field
+func writeEvent(w *bufio.Writer, id string, eventType string, data []byte) {
+	if id != "" {
+		w.WriteString("id: " + id + "\n")
+	}
+	w.WriteString("event: " + eventType + "\n")
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		w.WriteString("data: ")
+		w.Write(line)
+		w.WriteString("\n")
+	}
+	w.WriteString("\n")
+}
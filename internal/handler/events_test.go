@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/broadcast"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventsUsecase subscribes to events and closes the subscription after one live event,
+// as the broadcaster does with a subscriber falling behind
+type eventsUsecase struct {
+	usecase.BlogUsecase
+	events *broadcast.Broadcaster
+	blogID uuid.UUID
+}
+
+func (u *eventsUsecase) SubscribePostEvents(_ context.Context, req model.PostEventsReq) (*broadcast.Subscription, error) {
+	if req.BlogID != u.blogID {
+		return nil, apperror.ErrNotFound
+	}
+	sub := u.events.Subscribe(req.BlogID, req.LastEventID, false)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		u.events.Publish(u.blogID, model.EventPostDeleted, []byte(`{"post_id":"live"}`), false)
+		sub.Close()
+	}()
+	return sub, nil
+}
+
+func TestStreamPostEvents(t *testing.T) {
+	uc := &eventsUsecase{events: broadcast.New(8), blogID: uuid.New()}
+	probe := uc.events.Subscribe(uc.blogID, "", false)
+	uc.events.Publish(uc.blogID, model.EventPostCreated, []byte(`{"post_id":"first"}`), false)
+	uc.events.Publish(uc.blogID, model.EventPostUpdated, []byte("{\n\"post_id\":\"second\"}"), false)
+	first := <-probe.Events
+	probe.Close()
+
+	h := New(uc, nil, nil, nil, nil, validator.New())
+	app := fiber.New()
+	app.Get("/api/blog/:blog_id/events", h.StreamPostEvents(10*time.Millisecond))
+
+	stream := func(t *testing.T, lastEventID string) string {
+		req := httptest.NewRequest(fiber.MethodGet, "/api/blog/"+uc.blogID.String()+"/events", nil)
+		if lastEventID != "" {
+			req.Header.Set(LastEventIDHeader, lastEventID)
+		}
+		resp, err := app.Test(req, 2000)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get(fiber.HeaderContentType))
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	t.Run("live", func(t *testing.T) {
+		body := stream(t, "")
+		assert.True(t, strings.HasPrefix(body, "retry: "+eventsRetry+"\n\n"))
+		assert.Contains(t, body, ": heartbeat\n\n")
+		assert.Contains(t, body, "event: post.deleted\ndata: {\"post_id\":\"live\"}\n\n")
+		assert.NotContains(t, body, "second")
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		body := stream(t, first.ID)
+		assert.NotContains(t, body, "first")
+		assert.Contains(t, body, "event: post.updated\ndata: {\ndata: \"post_id\":\"second\"}\n\n")
+		assert.Less(t, strings.Index(body, "second"), strings.Index(body, "live"))
+		assert.NotContains(t, body, usecase.EventPostsReset)
+	})
+
+	t.Run("missed", func(t *testing.T) {
+		body := stream(t, "gone-1")
+		assert.Contains(t, body, "event: "+usecase.EventPostsReset+"\ndata: {}\n\n")
+	})
+
+	t.Run("unknown blog", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/blog/"+uuid.NewString()+"/events", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+}
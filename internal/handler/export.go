@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// ExportBlog streams tar.gz archive of the blog. Status is sent before the archive is written,
+// so errors in the middle of the stream are only logged and the client gets truncated archive.
+// An export holds a database connection while the client reads it, so at most maxConcurrent exports
+// run at once and every one is cut after timeout.
+func (h *Handler) ExportBlog(timeout time.Duration, maxConcurrent int) fiber.Handler {
+	slots := make(chan struct{}, maxConcurrent)
+	return func(c *fiber.Ctx) error {
+		var req model.BlogExportReq
+		var err error
+		req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		req.Format = c.Query(FormatQuery, usecase.ExportFormatJSON)
+		if err := h.validate.Struct(req); err != nil {
+			log.Err(err).Msg("")
+			return fiber.ErrBadRequest
+		}
+		select {
+		case slots <- struct{}{}:
+		default:
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(timeout.Seconds())))
+			return fiber.ErrServiceUnavailable
+		}
+		if _, err := h.usecase.GetBlog(c.UserContext(), model.BlogGetReq{BlogID: req.BlogID}); err != nil {
+			<-slots
+			if errors.Is(err, apperror.ErrNotFound) {
+				return fiber.ErrNotFound
+			}
+			log.Err(err).Msg("")
+			return fiber.ErrInternalServerError
+		}
+
+		// fiber.Ctx is released when handler returns, the stream writer must not touch it
+		ctx := c.UserContext()
+		conn := c.Context().Conn()
+		c.Set(fiber.HeaderContentType, "application/gzip")
+		c.Attachment("blog-" + req.BlogID.String() + ".tar.gz")
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer func() { <-slots }()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			// the context stops reading posts, the deadline stops a write to a client which does not read
+			deadline, _ := ctx.Deadline()
+			if err := conn.SetWriteDeadline(deadline); err != nil {
+				log.Err(err).Msg("")
+			}
+			defer conn.SetWriteDeadline(time.Time{})
+			if err := h.usecase.ExportBlog(ctx, req, w); err != nil {
+				log.Err(err).Msg("")
+			}
+			if err := w.Flush(); err != nil {
+				log.Err(err).Msg("")
+			}
+		})
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportUsecase writes the archive until release is closed or the context is done
+type exportUsecase struct {
+	usecase.BlogUsecase
+	started chan struct{}
+	release chan struct{}
+	err     chan error
+}
+
+func (u *exportUsecase) GetBlog(_ context.Context, req model.BlogGetReq) (model.BlogGetResp, error) {
+	return model.BlogGetResp{BlogID: req.BlogID}, nil
+}
+
+func (u *exportUsecase) ExportBlog(ctx context.Context, _ model.BlogExportReq, w io.Writer) error {
+	u.started <- struct{}{}
+	select {
+	case <-u.release:
+		_, err := w.Write([]byte("archive"))
+		return err
+	case <-ctx.Done():
+		u.err <- ctx.Err()
+		return ctx.Err()
+	}
+}
+
+func TestExportBlog(t *testing.T) {
+	newApp := func() (*fiber.App, *exportUsecase) {
+		uc := &exportUsecase{started: make(chan struct{}, 1), release: make(chan struct{}), err: make(chan error, 1)}
+		app := fiber.New()
+		app.Get("/blog/:blog_id/export", New(uc, nil, nil, nil, nil, validator.New()).ExportBlog(100*time.Millisecond, 1))
+		return app, uc
+	}
+	url := "/blog/" + uuid.NewString() + "/export"
+
+	t.Run("cut after timeout", func(t *testing.T) {
+		app, uc := newApp()
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, url, nil), 1000)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.ErrorIs(t, <-uc.err, context.DeadlineExceeded)
+	})
+
+	t.Run("one at a time", func(t *testing.T) {
+		app, uc := newApp()
+		done := make(chan int)
+		go func() {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, url, nil), 1000)
+			assert.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+			assert.Equal(t, "archive", string(body))
+			done <- resp.StatusCode
+		}()
+		<-uc.started
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, url, nil), 1000)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+		close(uc.release)
+		assert.Equal(t, fiber.StatusOK, <-done)
+	})
+}
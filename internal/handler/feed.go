@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	contentTypeRSS  = "application/rss+xml; charset=utf-8"
+	contentTypeAtom = "application/atom+xml; charset=utf-8"
+	atomNamespace   = "http://www.w3.org/2005/Atom"
+)
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Description string   `xml:"description"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Link       atomLink       `xml:"link"`
+	Content    atomContent    `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func (h *Handler) GetFeedRSS(c *fiber.Ctx) error {
+	feed, err := h.getFeed(c)
+	if err != nil {
+		return err
+	}
+	blogLink := c.BaseURL() + "/api/blog/" + feed.Blog.BlogID.String()
+	channel := rssChannel{
+		Title:         feed.Blog.Name,
+		Link:          blogLink,
+		Description:   feed.Blog.Name,
+		LastBuildDate: feed.Updated.UTC().Format(time.RFC1123Z),
+		Items:         make([]rssItem, 0, len(feed.Posts)),
+	}
+	for _, post := range feed.Posts {
+		channel.Items = append(channel.Items, rssItem{
+			Title:       post.Title,
+			Link:        blogLink + "/posts/" + post.PostID.String(),
+			GUID:        rssGUID{Value: post.PostID.String()},
+			PubDate:     post.CreatedAt.UTC().Format(time.RFC1123Z),
+			Description: post.Text,
+			Categories:  post.Tags,
+		})
+	}
+	return sendFeed(c, contentTypeRSS, feed.Updated, rss{Version: "2.0", Channel: channel})
+}
+
+func (h *Handler) GetFeedAtom(c *fiber.Ctx) error {
+	feed, err := h.getFeed(c)
+	if err != nil {
+		return err
+	}
+	blogLink := c.BaseURL() + "/api/blog/" + feed.Blog.BlogID.String()
+	atom := atomFeed{
+		Xmlns:   atomNamespace,
+		ID:      uuidURN(feed.Blog.BlogID),
+		Title:   feed.Blog.Name,
+		Updated: feed.Updated.UTC().Format(time.RFC3339),
+		Link:    atomLink{Href: c.BaseURL() + c.OriginalURL(), Rel: "self"},
+		Author:  atomAuthor{Name: feed.Blog.UserID.String()},
+		Entries: make([]atomEntry, 0, len(feed.Posts)),
+	}
+	for _, post := range feed.Posts {
+		entry := atomEntry{
+			ID:        uuidURN(post.PostID),
+			Title:     post.Title,
+			Updated:   post.CreatedAt.UTC().Format(time.RFC3339),
+			Published: post.CreatedAt.UTC().Format(time.RFC3339),
+			Link:      atomLink{Href: blogLink + "/posts/" + post.PostID.String()},
+			Content:   atomContent{Type: "text", Value: post.Text},
+		}
+		for _, tag := range post.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		atom.Entries = append(atom.Entries, entry)
+	}
+	return sendFeed(c, contentTypeAtom, feed.Updated, atom)
+}
+
+func (h *Handler) getFeed(c *fiber.Ctx) (model.FeedGetResp, error) {
+	var req model.FeedGetReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return model.FeedGetResp{}, fiber.ErrBadRequest
+	}
+	if limit := c.Query(LimitQuery); limit != "" {
+		req.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return model.FeedGetResp{}, fiber.ErrBadRequest
+		}
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return model.FeedGetResp{}, fiber.ErrBadRequest
+	}
+	feed, err := h.usecase.GetFeed(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return model.FeedGetResp{}, fiber.ErrNotFound
+		}
+		log.Err(err).Msg("")
+		return model.FeedGetResp{}, fiber.ErrInternalServerError
+	}
+	return feed, nil
+}
+
+// sendFeed writes feed with validators for conditional GET, which is answered by middleware.NotModified.
+// ETag is a hash of the body, so edits of posts are noticed even though they don't move Last-Modified.
+func sendFeed(c *fiber.Ctx, contentType string, updated time.Time, feed any) error {
+	body, err := xml.Marshal(feed)
+	if err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	body = append([]byte(xml.Header), body...)
+	sum := sha256.Sum256(body)
+	c.Set(fiber.HeaderETag, `"`+hex.EncodeToString(sum[:16])+`"`)
+	c.Set(fiber.HeaderLastModified, updated.UTC().Format(http.TimeFormat))
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Send(body)
+}
+
+func uuidURN(id uuid.UUID) string {
+	return "urn:uuid:" + id.String()
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Rolan335/project/internal/middleware"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cache"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type feedUsecase struct {
+	usecase.BlogUsecase
+	feed  model.FeedGetResp
+	calls int
+}
+
+func (u *feedUsecase) GetFeed(context.Context, model.FeedGetReq) (model.FeedGetResp, error) {
+	u.calls++
+	return u.feed, nil
+}
+
+func TestGetFeed(t *testing.T) {
+	updated := time.Date(2025, 4, 20, 12, 0, 0, 0, time.UTC)
+	blogID := uuid.New()
+	uc := &feedUsecase{feed: model.FeedGetResp{
+		Blog:    model.BlogGetResp{BlogID: blogID, Name: "blog"},
+		Posts:   []model.PostGetResp{{PostID: uuid.New(), BlogID: blogID, Title: "post <1>", Text: "text", CreatedAt: updated}},
+		Updated: updated,
+	}}
+	h := New(uc, nil, nil, nil, nil, validator.New())
+	app := fiber.New()
+	app.Get("/blog/:blog_id/feed.rss", middleware.NotModified, cache.New(cache.Config{StoreResponseHeaders: true}), h.GetFeedRSS)
+	app.Get("/blog/:blog_id/feed.atom", middleware.NotModified, h.GetFeedAtom)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/blog/"+blogID.String()+"/feed.rss", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, contentTypeRSS, resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, updated.Format(http.TimeFormat), resp.Header.Get(fiber.HeaderLastModified))
+	etag := resp.Header.Get(fiber.HeaderETag)
+	require.NotEmpty(t, etag)
+	body, _ := io.ReadAll(resp.Body)
+	var feed rss
+	require.NoError(t, xml.Unmarshal(body, &feed))
+	assert.Equal(t, "post <1>", feed.Channel.Items[0].Title)
+
+	// cached response still answers conditional GET
+	req := httptest.NewRequest(fiber.MethodGet, "/blog/"+blogID.String()+"/feed.rss", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+	assert.Equal(t, 1, uc.calls)
+
+	req = httptest.NewRequest(fiber.MethodGet, "/blog/"+blogID.String()+"/feed.atom", nil)
+	req.Header.Set(fiber.HeaderIfModifiedSince, updated.Format(http.TimeFormat))
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+
+	req = httptest.NewRequest(fiber.MethodGet, "/blog/"+blogID.String()+"/feed.atom", nil)
+	req.Header.Set(fiber.HeaderIfModifiedSince, updated.Add(-time.Hour).Format(http.TimeFormat))
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, contentTypeAtom, resp.Header.Get(fiber.HeaderContentType))
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"github.com/Rolan335/project/internal/graph"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// GraphQL serves queries and mutations of blogs, posts and users. Errors of the operation
+// are returned in the errors field of a 200 response, as GraphQL clients expect.
+func (h *Handler) GraphQL() fiber.Handler {
+	schema := graph.New(h.usecase, h.users, h.validate)
+	return func(c *fiber.Ctx) error {
+		var req model.GraphQLReq
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.ErrBadRequest
+		}
+		if err := h.validate.Struct(req); err != nil {
+			log.Err(err).Msg("")
+			return fiber.ErrBadRequest
+		}
+		return c.JSON(schema.Exec(c.UserContext(), req.Query, req.OperationName, req.Variables))
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type graphQLUsecase struct {
+	usecase.BlogUsecase
+}
+
+func (u *graphQLUsecase) GetBlog(_ context.Context, req model.BlogGetReq) (model.BlogGetResp, error) {
+	return model.BlogGetResp{BlogID: req.BlogID, Name: "blog"}, nil
+}
+
+func TestGraphQL(t *testing.T) {
+	h := New(&graphQLUsecase{}, nil, nil, nil, nil, validator.New())
+	app := fiber.New()
+	app.Post("/api/graphql", h.GraphQL())
+	blogID := uuid.NewString()
+
+	testCases := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+		wantErrors bool
+	}{
+		{
+			name:       "query",
+			body:       `{"query":"query ($id: ID!) { blog(id: $id) { id name } }","variables":{"id":"` + blogID + `"}}`,
+			wantStatus: fiber.StatusOK,
+			wantBody:   `{"data":{"blog":{"id":"` + blogID + `","name":"blog"}}}`,
+		},
+		{
+			name:       "invalid query",
+			body:       `{"query":"{ blog }"}`,
+			wantStatus: fiber.StatusOK,
+			wantErrors: true,
+		},
+		{name: "no query", body: `{}`, wantStatus: fiber.StatusBadRequest},
+		{name: "malformed", body: `{`, wantStatus: fiber.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPost, "/api/graphql", strings.NewReader(tc.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStatus, resp.StatusCode)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			if tc.wantBody != "" {
+				assert.JSONEq(t, tc.wantBody, string(body))
+			}
+			if tc.wantErrors {
+				assert.Contains(t, string(body), `"errors"`)
+			}
+		})
+	}
+}
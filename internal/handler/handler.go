@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"strconv"
 
 	"github.com/Rolan335/project/internal/apperror"
 	"github.com/Rolan335/project/internal/model"
@@ -14,19 +15,48 @@ import (
 )
 
 const (
-	BlogIDParam = "blog_id"
-	PostIDParam = "post_id"
+	BlogIDParam     = "blog_id"
+	PostIDParam     = "post_id"
+	RevisionParam   = "revision"
+	CommentIDParam  = "comment_id"
+	UserIDParam     = "user_id"
+	KeyIDParam      = "key_id"
+	BlogSlugParam   = "blog_slug"
+	PostSlugParam   = "post_slug"
+	WebhookIDParam  = "webhook_id"
+	DeliveryIDParam = "delivery_id"
+
+	LimitQuery    = "limit"
+	CursorQuery   = "cursor"
+	SearchQuery   = "q"
+	FromQuery     = "from"
+	ToQuery       = "to"
+	TagQuery      = "tag"
+	ParentIDQuery = "parent_id"
+	ModeQuery     = "mode"
+	FormatQuery   = "format"
+	RenderQuery   = "render"
+
+	renderHTML = "html"
 )
 
 type Handler struct {
 	validate *validator.Validate
 	usecase  usecase.BlogUsecase
+	comments usecase.CommentUsecase
+	users    usecase.UserUsecase
+	keys     usecase.APIKeyUsecase
+	webhooks usecase.WebhookUsecase
 }
 
-func New(usecase usecase.BlogUsecase, validate *validator.Validate) *Handler {
+func New(usecase usecase.BlogUsecase, comments usecase.CommentUsecase, users usecase.UserUsecase, keys usecase.APIKeyUsecase, webhooks usecase.WebhookUsecase, validate *validator.Validate) *Handler {
 	return &Handler{
 		validate: validate,
 		usecase:  usecase,
+		comments: comments,
+		users:    users,
+		keys:     keys,
+		webhooks: webhooks,
 	}
 }
 
@@ -48,6 +78,7 @@ func (h *Handler) GetBlog(c *fiber.Ctx) error {
 		log.Err(err).Msg("")
 		return fiber.ErrInternalServerError
 	}
+	c.Set(fiber.HeaderETag, etag(blog.Version))
 	return c.JSON(blog)
 }
 
@@ -60,8 +91,17 @@ func (h *Handler) CreateBlog(c *fiber.Ctx) error {
 		log.Err(err).Msg("")
 		return fiber.ErrBadRequest
 	}
-	id, err := h.usecase.AddBlog(c.Context(), blog)
+	id, err := h.usecase.AddBlog(c.UserContext(), blog)
 	if err != nil {
+		if errors.Is(err, apperror.ErrNotRegistered) {
+			return fiber.NewError(fiber.StatusForbidden, apperror.ErrNotRegistered.Error())
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
 		log.Err(err).Msg("")
 		return fiber.ErrInternalServerError
 	}
@@ -78,18 +118,32 @@ func (h *Handler) UpdateBlog(c *fiber.Ctx) error {
 	if err := c.BodyParser(&blog); err != nil {
 		return fiber.ErrBadRequest
 	}
+	blog.Version, err = ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
 	if err := h.validate.Struct(blog); err != nil {
 		log.Err(err).Msg("")
 		return fiber.ErrBadRequest
 	}
-	resp, err := h.usecase.UpdateBlog(c.Context(), blog)
+	resp, err := h.usecase.UpdateBlog(c.UserContext(), blog)
 	if err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
 			return fiber.ErrNotFound
 		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		if errors.Is(err, apperror.ErrVersionMismatch) {
+			return fiber.ErrPreconditionFailed
+		}
 		log.Err(err).Msg("")
 		return fiber.ErrInternalServerError
 	}
+	c.Set(fiber.HeaderETag, etag(resp.Version))
 	return c.JSON(resp)
 }
 
@@ -100,27 +154,108 @@ func (h *Handler) DeleteBlog(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.ErrBadRequest
 	}
-	if err := h.usecase.DeleteBlog(c.Context(), blog); err != nil {
+	if err := h.usecase.DeleteBlog(c.UserContext(), blog); err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
 			return fiber.ErrNotFound
 		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
 		log.Err(err).Msg("")
 		return fiber.ErrInternalServerError
 	}
 	return c.SendStatus(fiber.StatusOK)
 }
 
+func (h *Handler) GetBlogsTrash(c *fiber.Ctx) error {
+	resp, err := h.usecase.GetBlogsTrash(c.UserContext(), model.BlogsTrashGetReq{})
+	if err != nil {
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) RestoreBlog(c *fiber.Ctx) error {
+	var req model.BlogRestoreReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.usecase.RestoreBlog(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) GetBlogTags(c *fiber.Ctx) error {
+	var req model.TagsGetReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.usecase.GetBlogTags(c.UserContext(), req)
+	if err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
 func (h *Handler) GetPosts(c *fiber.Ctx) error {
-	blogID, err := uuid.Parse(c.Params(BlogIDParam))
+	var req model.PostsGetReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	if limit := c.Query(LimitQuery); limit != "" {
+		req.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+	}
+	req.Cursor = c.Query(CursorQuery)
+	req.Tag = c.Query(TagQuery)
+	req.RenderHTML, err = parseRender(c)
 	if err != nil {
+		return err
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
 		return fiber.ErrBadRequest
 	}
-	posts, err := h.usecase.GetPosts(c.Context(), model.PostsGetReq{BlogID: blogID})
+	posts, err := h.usecase.GetPosts(c.UserContext(), req)
 	if err != nil {
+		if errors.Is(err, apperror.ErrInvalidCursor) {
+			return fiber.ErrBadRequest
+		}
 		log.Err(err).Msg("")
 		return fiber.ErrInternalServerError
 	}
-	if len(posts) == 0 {
+	if len(posts.Posts) == 0 {
 		return fiber.ErrNotFound
 	}
 	return c.JSON(posts)
@@ -138,7 +273,11 @@ func (h *Handler) GetPost(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.ErrBadRequest
 	}
-	post, err := h.usecase.GetPost(c.Context(), req)
+	req.RenderHTML, err = parseRender(c)
+	if err != nil {
+		return err
+	}
+	post, err := h.usecase.GetPost(c.UserContext(), req)
 	if err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
 			return fiber.ErrNotFound
@@ -147,9 +286,39 @@ func (h *Handler) GetPost(c *fiber.Ctx) error {
 		return fiber.ErrInternalServerError
 	}
 
+	c.Set(fiber.HeaderETag, postETag(post, req.RenderHTML))
 	return c.JSON(post)
 }
 
+func (h *Handler) SearchPosts(c *fiber.Ctx) error {
+	var req model.PostsSearchReq
+	var err error
+	req.Query = c.Query(SearchQuery)
+	if blogID := c.Query(BlogIDParam); blogID != "" {
+		id, err := uuid.Parse(blogID)
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		req.BlogID = &id
+	}
+	if limit := c.Query(LimitQuery); limit != "" {
+		req.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.usecase.SearchPosts(c.UserContext(), req)
+	if err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
 func (h *Handler) CreatePost(c *fiber.Ctx) error {
 	var req model.PostPostReq
 	if err := c.BodyParser(&req); err != nil {
@@ -164,11 +333,17 @@ func (h *Handler) CreatePost(c *fiber.Ctx) error {
 		log.Err(err).Msg("")
 		return fiber.ErrBadRequest
 	}
-	resp, err := h.usecase.AddPost(c.Context(), req)
+	resp, err := h.usecase.AddPost(c.UserContext(), req)
 	if err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
 			return fiber.ErrNotFound
 		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
 		log.Err(err).Msg("")
 		return fiber.ErrInternalServerError
 	}
@@ -189,18 +364,32 @@ func (h *Handler) UpdatePost(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.ErrBadRequest
 	}
+	req.Version, err = ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
 	if err := h.validate.Struct(req); err != nil {
 		log.Err(err).Msg("")
 		return fiber.ErrBadRequest
 	}
-	resp, err := h.usecase.UpdatePost(c.Context(), req)
+	resp, err := h.usecase.UpdatePost(c.UserContext(), req)
 	if err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
 			return fiber.ErrNotFound
 		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		if errors.Is(err, apperror.ErrVersionMismatch) {
+			return fiber.ErrPreconditionFailed
+		}
 		log.Err(err).Msg("")
 		return fiber.ErrInternalServerError
 	}
+	c.Set(fiber.HeaderETag, etag(resp.Version))
 	return c.JSON(resp)
 }
 
@@ -215,12 +404,82 @@ func (h *Handler) DeletePost(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.ErrBadRequest
 	}
-	if err = h.usecase.DeletePost(c.Context(), req); err != nil {
+	if err = h.usecase.DeletePost(c.UserContext(), req); err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
 			return fiber.ErrNotFound
 		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
 		log.Err(err).Msg("")
 		return fiber.ErrInternalServerError
 	}
 	return c.SendStatus(fiber.StatusOK)
 }
+
+func (h *Handler) GetPostsTrash(c *fiber.Ctx) error {
+	var req model.PostsTrashGetReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.usecase.GetPostsTrash(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) RestorePost(c *fiber.Ctx) error {
+	var req model.PostRestoreReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.PostID, err = uuid.Parse(c.Params(PostIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.usecase.RestorePost(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+// parseRender reports whether ?render=html was passed, other values are rejected
+func parseRender(c *fiber.Ctx) (bool, error) {
+	switch c.Query(RenderQuery) {
+	case "":
+		return false, nil
+	case renderHTML:
+		return true, nil
+	default:
+		return false, fiber.ErrBadRequest
+	}
+}
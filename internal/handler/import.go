@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	ImportModeAtomic     = "atomic"
+	ImportModeBestEffort = "best_effort"
+
+	// maxImportLineSize limits one NDJSON line, a post is far smaller
+	maxImportLineSize = 1 << 20
+	// maxImportSize limits the whole NDJSON body, it is read from the stream past the BodyLimit of the app
+	maxImportSize = 256 << 20
+)
+
+// StreamsBody reports whether the route of the request reads the request body stream itself
+func StreamsBody(c *fiber.Ctx) bool {
+	return c.Method() == fiber.MethodPost && strings.HasSuffix(c.Path(), "/posts:import")
+}
+
+// ImportPosts reads posts from NDJSON body, one PostPostReq per line. Invalid lines are reported with
+// their numbers; in atomic mode (default) any invalid line cancels the whole import with 422.
+// The body is read by the usecase in batches once the caller is allowed to import into the blog.
+func (h *Handler) ImportPosts(c *fiber.Ctx) error {
+	blogID, err := uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	mode := c.Query(ModeQuery, ImportModeAtomic)
+	if mode != ImportModeAtomic && mode != ImportModeBestEffort {
+		return fiber.ErrBadRequest
+	}
+
+	// bodies up to BodyLimit of the app are read before the handler and are not streamed
+	var body io.Reader = bytes.NewReader(c.Body())
+	if c.Request().IsBodyStream() {
+		body = c.Context().RequestBodyStream()
+	}
+	scanner := bufio.NewScanner(&limitedReader{r: body, n: maxImportSize})
+	scanner.Buffer(nil, maxImportLineSize)
+	lines := &importLines{scanner: scanner, validate: h.validate, blogID: blogID, atomic: mode == ImportModeAtomic}
+	req := model.PostsImportReq{BlogID: blogID, Atomic: lines.atomic, Next: lines.next}
+
+	resp, err := h.usecase.ImportPosts(c.UserContext(), req)
+	if err != nil {
+		if c.Request().IsBodyStream() {
+			// the rest of the body is not read
+			c.Context().SetConnectionClose()
+		}
+		if errors.Is(err, errImportInvalid) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(model.PostsImportResp{Errors: lines.errors})
+		}
+		if errors.Is(err, errImportTooLarge) {
+			return fiber.ErrRequestEntityTooLarge
+		}
+		if lines.err != nil {
+			return fiber.ErrBadRequest
+		}
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	resp.Errors = append(resp.Errors, lines.errors...)
+	slices.SortFunc(resp.Errors, func(a, b model.PostImportError) int { return a.Line - b.Line })
+	return c.JSON(resp)
+}
+
+// errImportInvalid cancels atomic import which has invalid lines
+var errImportInvalid = errors.New("import has invalid lines")
+
+// importLines decodes and validates NDJSON lines of import, errors of invalid lines are kept
+type importLines struct {
+	scanner  *bufio.Scanner
+	validate *validator.Validate
+	blogID   uuid.UUID
+	atomic   bool
+	line     int
+	errors   []model.PostImportError
+	// err is the error of reading the body
+	err error
+}
+
+// next reads up to n valid lines. Once atomic import has an invalid line it fails with errImportInvalid,
+// the rest of the body is still read to report all the invalid lines.
+func (l *importLines) next(n int) ([]model.PostImportLine, error) {
+	var lines []model.PostImportLine
+	for (len(lines) < n || l.cancelled()) && l.scanner.Scan() {
+		l.line++
+		if post, ok := l.parse(l.scanner.Bytes()); ok && !l.cancelled() {
+			lines = append(lines, model.PostImportLine{Line: l.line, Post: post})
+		}
+	}
+	if err := l.scanner.Err(); err != nil {
+		l.err = err
+		return nil, err
+	}
+	if l.cancelled() {
+		return nil, errImportInvalid
+	}
+	if len(lines) == 0 {
+		return nil, io.EOF
+	}
+	return lines, nil
+}
+
+func (l *importLines) cancelled() bool {
+	return l.atomic && len(l.errors) > 0
+}
+
+// parse decodes one line, an invalid line is recorded in errors
+func (l *importLines) parse(line []byte) (model.PostPostReq, bool) {
+	var post model.PostPostReq
+	if len(bytes.TrimSpace(line)) == 0 {
+		return post, false
+	}
+	if err := json.Unmarshal(line, &post); err != nil {
+		l.errors = append(l.errors, model.PostImportError{Line: l.line, Error: err.Error()})
+		return post, false
+	}
+	post.BlogID = l.blogID
+	if err := l.validate.Struct(post); err != nil {
+		l.errors = append(l.errors, model.PostImportError{Line: l.line, Error: err.Error()})
+		return post, false
+	}
+	// postgres text can not hold NUL, the whole batch would fail on it
+	if strings.ContainsRune(post.Title, 0) || strings.ContainsRune(post.Text, 0) {
+		l.errors = append(l.errors, model.PostImportError{Line: l.line, Error: "post contains NUL character"})
+		return post, false
+	}
+	return post, true
+}
+
+var errImportTooLarge = errors.New("import body is too large")
+
+// limitedReader is io.LimitReader which fails instead of ending when the limit is passed
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, errImportTooLarge
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, errImportTooLarge
+	}
+	return n, err
+}
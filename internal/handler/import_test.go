@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type importUsecase struct {
+	usecase.BlogUsecase
+	// err fails the import before the body is read, as authorization does
+	err   error
+	lines []model.PostImportLine
+}
+
+func (u *importUsecase) ImportPosts(_ context.Context, req model.PostsImportReq) (model.PostsImportResp, error) {
+	if u.err != nil {
+		return model.PostsImportResp{}, u.err
+	}
+	for {
+		lines, err := req.Next(2)
+		if errors.Is(err, io.EOF) {
+			return model.PostsImportResp{Imported: len(u.lines), Errors: []model.PostImportError{}}, nil
+		}
+		if err != nil {
+			return model.PostsImportResp{}, err
+		}
+		u.lines = append(u.lines, lines...)
+	}
+}
+
+func TestImportPosts(t *testing.T) {
+	body := strings.Join([]string{
+		`{"title":"first","text":"text"}`,
+		`{"title":"","text":"text"}`,
+		``,
+		`not json`,
+		`{"title":"second","text":"text","tags":["go"]}`,
+	}, "\n")
+
+	testCases := []struct {
+		name         string
+		mode         string
+		wantStatus   int
+		err          error
+		wantImported int
+		wantLines    []int
+	}{
+		{name: "atomic", mode: "", wantStatus: fiber.StatusUnprocessableEntity, wantLines: []int{2, 4}},
+		{name: "best effort", mode: ImportModeBestEffort, wantStatus: fiber.StatusOK, wantImported: 2, wantLines: []int{2, 4}},
+		{name: "unknown mode", mode: "maybe", wantStatus: fiber.StatusBadRequest},
+		{name: "anonymous", mode: ImportModeBestEffort, err: apperror.ErrUnauthorized, wantStatus: fiber.StatusUnauthorized},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := &importUsecase{err: tc.err}
+			h := New(uc, nil, nil, nil, nil, validator.New())
+			app := fiber.New()
+			app.Post("/blog/:blog_id/posts\\:import", h.ImportPosts)
+
+			url := "/blog/" + uuid.NewString() + "/posts:import"
+			if tc.mode != "" {
+				url += "?mode=" + tc.mode
+			}
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, url, strings.NewReader(body)))
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStatus, resp.StatusCode)
+			if tc.wantStatus != fiber.StatusOK && tc.wantStatus != fiber.StatusUnprocessableEntity {
+				return
+			}
+
+			var report model.PostsImportResp
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+			assert.Equal(t, tc.wantImported, report.Imported)
+			lines := make([]int, 0, len(report.Errors))
+			for _, e := range report.Errors {
+				lines = append(lines, e.Line)
+			}
+			assert.Equal(t, tc.wantLines, lines)
+		})
+	}
+}
+
+func TestImportPosts_stream(t *testing.T) {
+	uc := &importUsecase{}
+	h := New(uc, nil, nil, nil, nil, validator.New())
+	// bodies longer than BodyLimit are streamed to the handler
+	app := fiber.New(fiber.Config{BodyLimit: 64, StreamRequestBody: true})
+	app.Post("/blog/:blog_id/posts\\:import", h.ImportPosts)
+
+	lines := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		lines = append(lines, `{"title":"title","text":"text"}`)
+	}
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/blog/"+uuid.NewString()+"/posts:import", strings.NewReader(strings.Join(lines, "\n"))))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Len(t, uc.lines, 100)
+}
+
+func TestLimitedReader(t *testing.T) {
+	body, err := io.ReadAll(&limitedReader{r: strings.NewReader("12345"), n: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", string(body))
+
+	_, err = io.ReadAll(&limitedReader{r: strings.NewReader("123456"), n: 5})
+	assert.ErrorIs(t, err, errImportTooLarge)
+}
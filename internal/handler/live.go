@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/broadcast"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// liveContextLocal keeps context of the upgrade request with the authenticated caller
+	liveContextLocal = "live_context"
+	// liveReadLimit is the max size of a client message
+	liveReadLimit = 4096
+	// liveOutBuffer is the number of messages waiting to be written to a connection
+	liveOutBuffer = 64
+	liveWriteWait = 10 * time.Second
+)
+
+// LiveUpgrade lets websocket upgrade requests through to Live
+func (h *Handler) LiveUpgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+	c.Locals(liveContextLocal, c.UserContext())
+	return c.Next()
+}
+
+// liveSubscription is a blog the connection is subscribed to
+type liveSubscription struct {
+	events   *broadcast.Subscription
+	presence *broadcast.Subscription
+	// closed tells the subscription was closed by the connection, not dropped by the broadcaster
+	closed chan struct{}
+}
+
+func (s *liveSubscription) close() {
+	close(s.closed)
+	s.events.Close()
+	s.presence.Close()
+}
+
+// liveConn is a websocket connection. Only the writer goroutine writes to the socket,
+// everything else sends messages to out.
+type liveConn struct {
+	conn *websocket.Conn
+	out  chan model.LiveServerMessage
+	done chan struct{}
+	// writerDone is closed when the writer stops, e.g. on a failed write, nobody reads out after it
+	writerDone chan struct{}
+	wg         *sync.WaitGroup
+}
+
+func (l *liveConn) send(msg model.LiveServerMessage) {
+	select {
+	case l.out <- msg:
+	case <-l.done:
+	case <-l.writerDone:
+	}
+}
+
+func (l *liveConn) sendError(blogID *uuid.UUID, text string) {
+	l.send(model.LiveServerMessage{Type: model.LiveError, BlogID: blogID, Error: text})
+}
+
+// Live serves the websocket of live updates. A client subscribes to at most maxSubscriptions blogs,
+// gets post events and presence of other users of them and sends its own presence.
+// Connection is pinged every heartbeat and closed when the client does not answer.
+func (h *Handler) Live(maxSubscriptions int, heartbeat time.Duration) fiber.Handler {
+	return websocket.New(func(conn *websocket.Conn) {
+		ctx, ok := conn.Locals(liveContextLocal).(context.Context)
+		if !ok {
+			ctx = context.Background()
+		}
+		l := &liveConn{
+			conn:       conn,
+			out:        make(chan model.LiveServerMessage, liveOutBuffer),
+			done:       make(chan struct{}),
+			writerDone: make(chan struct{}),
+			wg:         &sync.WaitGroup{},
+		}
+		subs := make(map[uuid.UUID]*liveSubscription)
+		defer func() {
+			close(l.done)
+			for _, s := range subs {
+				s.close()
+			}
+			l.wg.Wait()
+		}()
+
+		l.wg.Add(1)
+		go l.write(heartbeat)
+
+		conn.SetReadLimit(liveReadLimit)
+		conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+		})
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					log.Err(err).Msg("")
+				}
+				return
+			}
+			var msg model.LiveClientMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				l.sendError(nil, "malformed message")
+				continue
+			}
+			if err := h.validate.Struct(msg); err != nil {
+				l.sendError(&msg.BlogID, "invalid message")
+				continue
+			}
+			switch msg.Type {
+			case model.LiveSubscribe:
+				// subscribing again renews the subscription, e.g. after posts.reset
+				if old, ok := subs[msg.BlogID]; ok {
+					old.close()
+					delete(subs, msg.BlogID)
+				}
+				if len(subs) >= maxSubscriptions {
+					l.sendError(&msg.BlogID, "subscription limit reached")
+					continue
+				}
+				s, err := h.liveSubscribe(ctx, msg.BlogID)
+				if err != nil {
+					if errors.Is(err, apperror.ErrNotFound) {
+						l.sendError(&msg.BlogID, "blog not found")
+						continue
+					}
+					log.Err(err).Msg("")
+					l.sendError(&msg.BlogID, "internal error")
+					continue
+				}
+				subs[msg.BlogID] = s
+				l.forward(msg.BlogID, s)
+				l.send(model.LiveServerMessage{Type: model.LiveSubscribed, BlogID: &msg.BlogID})
+			case model.LiveUnsubscribe:
+				if s, ok := subs[msg.BlogID]; ok {
+					s.close()
+					delete(subs, msg.BlogID)
+				}
+				l.send(model.LiveServerMessage{Type: model.LiveUnsubscribed, BlogID: &msg.BlogID})
+			case model.LivePresence:
+				s, ok := subs[msg.BlogID]
+				if !ok {
+					l.sendError(&msg.BlogID, "not subscribed")
+					continue
+				}
+				err := h.usecase.SendPresence(ctx, s.presence, model.PresencePostReq{BlogID: msg.BlogID, PostID: msg.PostID, State: msg.State})
+				if err != nil {
+					if errors.Is(err, apperror.ErrUnauthorized) || errors.Is(err, apperror.ErrForbidden) {
+						l.sendError(&msg.BlogID, "presence requires authentication")
+						continue
+					}
+					log.Err(err).Msg("")
+					l.sendError(&msg.BlogID, "internal error")
+				}
+			}
+		}
+	})
+}
+
+func (h *Handler) liveSubscribe(ctx context.Context, blogID uuid.UUID) (*liveSubscription, error) {
+	events, err := h.usecase.SubscribePostEvents(ctx, model.PostEventsReq{BlogID: blogID})
+	if err != nil {
+		return nil, err
+	}
+	presence, err := h.usecase.SubscribePresence(ctx, model.PresenceSubscribeReq{BlogID: blogID})
+	if err != nil {
+		events.Close()
+		return nil, err
+	}
+	return &liveSubscription{events: events, presence: presence, closed: make(chan struct{})}, nil
+}
+
+// forward passes events of the subscription to the connection until it is closed. A subscription dropped
+// for falling behind gets posts.reset, the client should reload posts of the blog and subscribe again.
+func (l *liveConn) forward(blogID uuid.UUID, s *liveSubscription) {
+	l.wg.Add(2)
+	go func() {
+		defer l.wg.Done()
+		for e := range s.events.Events {
+			l.send(model.LiveServerMessage{Type: model.LiveEvent, BlogID: &blogID, EventID: e.ID, Event: e.Type, Data: e.Data})
+		}
+		select {
+		case <-s.closed:
+		default:
+			l.send(model.LiveServerMessage{Type: model.LiveEvent, BlogID: &blogID, Event: usecase.EventPostsReset})
+		}
+	}()
+	go func() {
+		defer l.wg.Done()
+		for e := range s.presence.Events {
+			l.send(model.LiveServerMessage{Type: model.LivePresence, BlogID: &blogID, Data: e.Data})
+		}
+	}()
+}
+
+// write is the only writer of the socket. A failed write closes the socket, so the read loop
+// stops too, and writerDone releases whoever waits in send.
+func (l *liveConn) write(heartbeat time.Duration) {
+	defer l.wg.Done()
+	defer close(l.writerDone)
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.done:
+			return
+		case msg := <-l.out:
+			l.conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+			if err := l.conn.WriteJSON(msg); err != nil {
+				l.conn.Close()
+				return
+			}
+		case <-ticker.C:
+			if err := l.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(liveWriteWait)); err != nil {
+				l.conn.Close()
+				return
+			}
+		}
+	}
+}
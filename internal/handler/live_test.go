@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/broadcast"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/fasthttp/websocket"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// liveUsecase knows blogs and relays presence of authenticated callers
+type liveUsecase struct {
+	usecase.BlogUsecase
+	events   *broadcast.Broadcaster
+	presence *broadcast.Broadcaster
+	blogs    map[uuid.UUID]bool
+}
+
+func (u *liveUsecase) SubscribePostEvents(_ context.Context, req model.PostEventsReq) (*broadcast.Subscription, error) {
+	if !u.blogs[req.BlogID] {
+		return nil, apperror.ErrNotFound
+	}
+	return u.events.Subscribe(req.BlogID, req.LastEventID, false), nil
+}
+
+func (u *liveUsecase) SubscribePresence(_ context.Context, req model.PresenceSubscribeReq) (*broadcast.Subscription, error) {
+	return u.presence.Subscribe(req.BlogID, "", false), nil
+}
+
+func (u *liveUsecase) SendPresence(ctx context.Context, sub *broadcast.Subscription, req model.PresencePostReq) error {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+	data, _ := json.Marshal(model.PresenceEvent{BlogID: req.BlogID, PostID: req.PostID, UserID: userID, State: req.State})
+	sub.Publish(usecase.EventPresence, data, false)
+	return nil
+}
+
+const testUserHeader = "X-Test-User"
+
+func startLive(t *testing.T, uc *liveUsecase, maxSubscriptions int) string {
+	h := New(uc, nil, nil, nil, nil, validator.New())
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(func(c *fiber.Ctx) error {
+		if id, err := uuid.Parse(c.Get(testUserHeader)); err == nil {
+			c.SetUserContext(auth.WithUserID(c.UserContext(), id))
+		}
+		return c.Next()
+	})
+	app.Get("/api/ws", h.LiveUpgrade, h.Live(maxSubscriptions, time.Second))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+	return "ws://" + ln.Addr().String() + "/api/ws"
+}
+
+func dialLive(t *testing.T, url string, userID uuid.UUID) *websocket.Conn {
+	header := http.Header{}
+	if userID != uuid.Nil {
+		header.Set(testUserHeader, userID.String())
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readLive(t *testing.T, conn *websocket.Conn) model.LiveServerMessage {
+	var msg model.LiveServerMessage
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func TestLive(t *testing.T) {
+	first, second, unknown := uuid.New(), uuid.New(), uuid.New()
+	uc := &liveUsecase{
+		events:   broadcast.New(8),
+		presence: broadcast.New(0),
+		blogs:    map[uuid.UUID]bool{first: true, second: true},
+	}
+	url := startLive(t, uc, 1)
+
+	editor := uuid.New()
+	a := dialLive(t, url, editor)
+	b := dialLive(t, url, uuid.Nil)
+
+	require.NoError(t, a.WriteJSON(model.LiveClientMessage{Type: model.LiveSubscribe, BlogID: first}))
+	assert.Equal(t, model.LiveSubscribed, readLive(t, a).Type)
+	require.NoError(t, b.WriteJSON(model.LiveClientMessage{Type: model.LiveSubscribe, BlogID: first}))
+	assert.Equal(t, model.LiveSubscribed, readLive(t, b).Type)
+
+	t.Run("subscription limit", func(t *testing.T) {
+		require.NoError(t, a.WriteJSON(model.LiveClientMessage{Type: model.LiveSubscribe, BlogID: second}))
+		msg := readLive(t, a)
+		assert.Equal(t, model.LiveError, msg.Type)
+		assert.Equal(t, "subscription limit reached", msg.Error)
+	})
+
+	t.Run("post events", func(t *testing.T) {
+		uc.events.Publish(first, model.EventPostCreated, []byte(`{"post_id":"1"}`), false)
+		uc.events.Publish(second, model.EventPostCreated, []byte(`{"post_id":"2"}`), false)
+		for _, conn := range []*websocket.Conn{a, b} {
+			msg := readLive(t, conn)
+			assert.Equal(t, model.LiveEvent, msg.Type)
+			assert.Equal(t, first, *msg.BlogID)
+			assert.Equal(t, model.EventPostCreated, msg.Event)
+			assert.NotEmpty(t, msg.EventID)
+			assert.JSONEq(t, `{"post_id":"1"}`, string(msg.Data))
+		}
+	})
+
+	t.Run("presence", func(t *testing.T) {
+		postID := uuid.New()
+		require.NoError(t, a.WriteJSON(model.LiveClientMessage{Type: model.LivePresence, BlogID: first, PostID: postID, State: "editing"}))
+		msg := readLive(t, b)
+		assert.Equal(t, model.LivePresence, msg.Type)
+		var presence model.PresenceEvent
+		require.NoError(t, json.Unmarshal(msg.Data, &presence))
+		assert.Equal(t, model.PresenceEvent{BlogID: first, PostID: postID, UserID: editor, State: "editing"}, presence)
+
+		// anonymous client can not send presence, the sender does not get its own presence back
+		require.NoError(t, b.WriteJSON(model.LiveClientMessage{Type: model.LivePresence, BlogID: first, PostID: postID, State: "viewing"}))
+		assert.Equal(t, "presence requires authentication", readLive(t, b).Error)
+		require.NoError(t, a.WriteJSON(model.LiveClientMessage{Type: model.LiveUnsubscribe, BlogID: first}))
+		assert.Equal(t, model.LiveUnsubscribed, readLive(t, a).Type)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		require.NoError(t, a.WriteJSON(model.LiveClientMessage{Type: model.LiveSubscribe, BlogID: unknown}))
+		assert.Equal(t, "blog not found", readLive(t, a).Error)
+		require.NoError(t, a.WriteJSON(model.LiveClientMessage{Type: model.LivePresence, BlogID: second, PostID: uuid.New(), State: "editing"}))
+		assert.Equal(t, "not subscribed", readLive(t, a).Error)
+		require.NoError(t, a.WriteJSON(model.LiveClientMessage{Type: model.LivePresence, BlogID: first}))
+		assert.Equal(t, "invalid message", readLive(t, a).Error)
+		require.NoError(t, a.WriteMessage(websocket.TextMessage, []byte("{")))
+		assert.Equal(t, "malformed message", readLive(t, a).Error)
+	})
+}
+
+func TestLiveUpgrade(t *testing.T) {
+	h := New(&liveUsecase{}, nil, nil, nil, nil, validator.New())
+	app := fiber.New()
+	app.Get("/api/ws", h.LiveUpgrade, h.Live(1, time.Second))
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/ws", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUpgradeRequired, resp.StatusCode)
+}
+
+func TestLiveConn_sendAfterWriter(t *testing.T) {
+	l := &liveConn{out: make(chan model.LiveServerMessage, 1), done: make(chan struct{}), writerDone: make(chan struct{})}
+	l.send(model.LiveServerMessage{Type: model.LiveSubscribed})
+	close(l.writerDone)
+
+	sent := make(chan struct{})
+	go func() {
+		l.send(model.LiveServerMessage{Type: model.LiveSubscribed})
+		close(sent)
+	}()
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("send blocks on full buffer after the writer stopped")
+	}
+}
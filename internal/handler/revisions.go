@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *Handler) GetPostRevisions(c *fiber.Ctx) error {
+	var req model.PostRevisionsGetReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.PostID, err = uuid.Parse(c.Params(PostIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.usecase.GetPostRevisions(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) GetPostRevision(c *fiber.Ctx) error {
+	var req model.PostRevisionGetReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.PostID, err = uuid.Parse(c.Params(PostIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.Revision, err = c.ParamsInt(RevisionParam)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.usecase.GetPostRevision(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) DiffPostRevisions(c *fiber.Ctx) error {
+	var req model.PostRevisionsDiffReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.PostID, err = uuid.Parse(c.Params(PostIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.From, err = strconv.Atoi(c.Query(FromQuery))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.To, err = strconv.Atoi(c.Query(ToQuery))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.usecase.DiffPostRevisions(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) RestorePostRevision(c *fiber.Ctx) error {
+	var req model.PostRevisionRestoreReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.PostID, err = uuid.Parse(c.Params(PostIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.Revision, err = c.ParamsInt(RevisionParam)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.usecase.RestorePostRevision(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
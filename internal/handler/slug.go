@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *Handler) GetBlogBySlug(c *fiber.Ctx) error {
+	req := model.BlogSlugGetReq{Slug: c.Params(BlogSlugParam)}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	blog, err := h.usecase.GetBlogBySlug(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	if blog.Slug != req.Slug {
+		return slugRedirect(c, req.Slug, blog.Slug)
+	}
+	c.Set(fiber.HeaderETag, etag(blog.Version))
+	return c.JSON(blog)
+}
+
+func (h *Handler) GetPostBySlug(c *fiber.Ctx) error {
+	req := model.PostSlugGetReq{
+		BlogSlug: c.Params(BlogSlugParam),
+		PostSlug: c.Params(PostSlugParam),
+	}
+	var err error
+	req.RenderHTML, err = parseRender(c)
+	if err != nil {
+		return err
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.usecase.GetPostBySlug(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	if resp.BlogSlug != req.BlogSlug || resp.Post.Slug != req.PostSlug {
+		return slugRedirect(c, req.BlogSlug+"/"+req.PostSlug, resp.BlogSlug+"/"+resp.Post.Slug)
+	}
+	c.Set(fiber.HeaderETag, postETag(resp.Post, req.RenderHTML))
+	return c.JSON(resp.Post)
+}
+
+// slugRedirect sends 301 to the same route with old slugs at the end of path replaced by current ones.
+// Query is kept, so ?render=html survives the redirect.
+func slugRedirect(c *fiber.Ctx, old string, current string) error {
+	location := strings.TrimSuffix(c.Path(), old) + current
+	if query := c.Request().URI().QueryString(); len(query) > 0 {
+		location += "?" + string(query)
+	}
+	return c.Redirect(location, fiber.StatusMovedPermanently)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slugUsecase knows blog "go-news" with post "hello-world", both were renamed from "news" and "hello"
+type slugUsecase struct {
+	usecase.BlogUsecase
+}
+
+func (u *slugUsecase) blogSlug(slug string) (string, bool) {
+	switch slug {
+	case "go-news", "news":
+		return "go-news", true
+	}
+	return "", false
+}
+
+func (u *slugUsecase) GetBlogBySlug(_ context.Context, req model.BlogSlugGetReq) (model.BlogGetResp, error) {
+	slug, ok := u.blogSlug(req.Slug)
+	if !ok {
+		return model.BlogGetResp{}, apperror.ErrNotFound
+	}
+	return model.BlogGetResp{Slug: slug, Version: 1}, nil
+}
+
+func (u *slugUsecase) GetPostBySlug(_ context.Context, req model.PostSlugGetReq) (model.PostSlugGetResp, error) {
+	blogSlug, ok := u.blogSlug(req.BlogSlug)
+	if !ok || (req.PostSlug != "hello-world" && req.PostSlug != "hello") {
+		return model.PostSlugGetResp{}, apperror.ErrNotFound
+	}
+	return model.PostSlugGetResp{BlogSlug: blogSlug, Post: model.PostGetResp{Slug: "hello-world", Version: 1}}, nil
+}
+
+func TestGetBySlug(t *testing.T) {
+	h := New(&slugUsecase{}, nil, nil, nil, nil, validator.New())
+	app := fiber.New()
+	app.Get("/api/b/:blog_slug", h.GetBlogBySlug)
+	app.Get("/api/b/:blog_slug/:post_slug", h.GetPostBySlug)
+
+	testCases := []struct {
+		name     string
+		path     string
+		status   int
+		location string
+	}{
+		{name: "blog", path: "/api/b/go-news", status: fiber.StatusOK},
+		{name: "old blog slug", path: "/api/b/news", status: fiber.StatusMovedPermanently, location: "/api/b/go-news"},
+		{name: "unknown blog", path: "/api/b/rust-news", status: fiber.StatusNotFound},
+		{name: "post", path: "/api/b/go-news/hello-world", status: fiber.StatusOK},
+		{name: "old post slug", path: "/api/b/go-news/hello", status: fiber.StatusMovedPermanently, location: "/api/b/go-news/hello-world"},
+		{name: "old blog slug of post", path: "/api/b/news/hello-world?render=html", status: fiber.StatusMovedPermanently, location: "/api/b/go-news/hello-world?render=html"},
+		{name: "bad render", path: "/api/b/go-news/hello-world?render=pdf", status: fiber.StatusBadRequest},
+		{name: "unknown post", path: "/api/b/go-news/bye", status: fiber.StatusNotFound},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.location, resp.Header.Get(fiber.HeaderLocation))
+		})
+	}
+}
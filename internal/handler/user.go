@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *Handler) CreateUser(c *fiber.Ctx) error {
+	var req model.UserPostReq
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.ErrBadRequest
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.users.AddUser(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		if errors.Is(err, apperror.ErrConflict) {
+			return fiber.ErrConflict
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) GetUser(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params(UserIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.users.GetUser(c.UserContext(), model.UserGetReq{UserID: userID})
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) UpdateUser(c *fiber.Ctx) error {
+	var req model.UserPutReq
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.ErrBadRequest
+	}
+	var err error
+	req.UserID, err = uuid.Parse(c.Params(UserIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.users.UpdateUser(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		if errors.Is(err, apperror.ErrConflict) {
+			return fiber.ErrConflict
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) GetUserBlogs(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params(UserIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.users.GetUserBlogs(c.UserContext(), model.UserBlogsGetReq{UserID: userID})
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *Handler) CreateWebhook(c *fiber.Ctx) error {
+	var req model.WebhookPostReq
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.ErrBadRequest
+	}
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.webhooks.AddWebhook(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) GetWebhooks(c *fiber.Ctx) error {
+	var req model.WebhooksGetReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.webhooks.GetWebhooks(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) DeleteWebhook(c *fiber.Ctx) error {
+	var req model.WebhookDeleteReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.WebhookID, err = uuid.Parse(c.Params(WebhookIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	if err := h.webhooks.DeleteWebhook(c.UserContext(), req); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) GetWebhookDeliveries(c *fiber.Ctx) error {
+	var req model.WebhookDeliveriesGetReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.WebhookID, err = uuid.Parse(c.Params(WebhookIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	if limit := c.Query(LimitQuery); limit != "" {
+		req.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Err(err).Msg("")
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.webhooks.GetWebhookDeliveries(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) RedeliverWebhook(c *fiber.Ctx) error {
+	var req model.WebhookRedeliverReq
+	var err error
+	req.BlogID, err = uuid.Parse(c.Params(BlogIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.WebhookID, err = uuid.Parse(c.Params(WebhookIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	req.DeliveryID, err = uuid.Parse(c.Params(DeliveryIDParam))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+	resp, err := h.webhooks.RedeliverWebhook(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fiber.ErrNotFound
+		}
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			return fiber.ErrUnauthorized
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			return fiber.ErrForbidden
+		}
+		log.Err(err).Msg("")
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(resp)
+}
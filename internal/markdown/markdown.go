@@ -0,0 +1,25 @@
+// Package markdown renders CommonMark post text to HTML which is safe to embed into a page.
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+)
+
+var (
+	// goldmark drops raw HTML by default, the policy is a second line of defence,
+	// it also removes javascript: links which are valid CommonMark
+	renderer = goldmark.New()
+	policy   = bluemonday.UGCPolicy()
+)
+
+// Render converts markdown to sanitized HTML
+func Render(text string) (string, error) {
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(text), &buf); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,29 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "emphasis", text: "*hi* **there**", want: "<p><em>hi</em> <strong>there</strong></p>\n"},
+		{name: "heading", text: "# title", want: "<h1>title</h1>\n"},
+		{name: "raw html", text: "<script>alert(1)</script>", want: "\n"},
+		{name: "inline html", text: "a <img src=x onerror=alert(1)> b", want: "<p>a  b</p>\n"},
+		{name: "javascript link", text: "[x](javascript:alert(1))", want: "<p>x</p>\n"},
+		{name: "link", text: "[x](https://example.com)", want: "<p><a href=\"https://example.com\" rel=\"nofollow\">x</a></p>\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Render(tc.text)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
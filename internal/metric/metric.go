@@ -24,13 +24,20 @@ var (
 		},
 		[]string{"cachename"},
 	)
+	RateLimitRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ratelimit_rejections_total",
+			Help: "total number of requests rejected by rate limiter",
+		},
+		[]string{"method", "route"},
+	)
 )
 
 var once sync.Once
 
 func MustRegisterMetrics() {
 	once.Do(func() {
-		prometheus.MustRegister(RequestsCounter, CacheSize)
+		prometheus.MustRegister(RequestsCounter, CacheSize, RateLimitRejections)
 	})
 }
 
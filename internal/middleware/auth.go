@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	bearerPrefix = "Bearer "
+	apiKeyPrefix = "ApiKey "
+)
+
+// Auth puts the caller of bearer token or API key into user context. Requests without credentials
+// stay anonymous, usecases decide whether anonymous caller is allowed.
+func Auth(verifier *auth.Verifier, keys usecase.APIKeyUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(fiber.HeaderAuthorization)
+		switch {
+		case header == "":
+			return c.Next()
+		case strings.HasPrefix(header, bearerPrefix):
+			userID, err := verifier.Verify(strings.TrimPrefix(header, bearerPrefix))
+			if err != nil {
+				return fiber.ErrUnauthorized
+			}
+			c.SetUserContext(auth.WithUserID(c.UserContext(), userID))
+			return c.Next()
+		case strings.HasPrefix(header, apiKeyPrefix):
+			key, err := keys.AuthenticateAPIKey(c.UserContext(), strings.TrimPrefix(header, apiKeyPrefix))
+			if err != nil {
+				if errors.Is(err, apperror.ErrUnauthorized) {
+					return fiber.ErrUnauthorized
+				}
+				log.Err(err).Msg("")
+				return fiber.ErrInternalServerError
+			}
+			ctx := auth.WithScopes(auth.WithUserID(c.UserContext(), key.UserID), key.Scopes)
+			// changes are checked by usecases, reads need read scope here
+			if (c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead) && !auth.HasScope(ctx, auth.ScopeRead) {
+				return fiber.ErrForbidden
+			}
+			c.SetUserContext(ctx)
+			return c.Next()
+		}
+		return fiber.ErrUnauthorized
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit reads streamed request body into memory, bodies longer than limit are rejected with 413.
+// The app streams bodies longer than its BodyLimit, so that routes which read the stream themselves
+// are not limited by it; stream tells such routes, the rest get the whole body as with no streaming.
+func BodyLimit(limit int, stream func(c *fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !c.Request().IsBodyStream() || stream(c) {
+			return c.Next()
+		}
+		if c.Request().Header.ContentLength() > limit {
+			c.Context().SetConnectionClose()
+			return fiber.ErrRequestEntityTooLarge
+		}
+		body, err := io.ReadAll(io.LimitReader(c.Context().RequestBodyStream(), int64(limit)+1))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		if len(body) > limit {
+			c.Context().SetConnectionClose()
+			return fiber.ErrRequestEntityTooLarge
+		}
+		c.Request().SetBody(body)
+		return c.Next()
+	}
+}
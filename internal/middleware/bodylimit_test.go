@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyLimit(t *testing.T) {
+	app := fiber.New(fiber.Config{BodyLimit: 4, StreamRequestBody: true})
+	app.Use(BodyLimit(8, func(c *fiber.Ctx) bool { return c.Path() == "/stream" }))
+	app.Post("/body", func(c *fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+	app.Post("/stream", func(c *fiber.Ctx) error {
+		n, err := io.Copy(io.Discard, c.Context().RequestBodyStream())
+		if err != nil {
+			return err
+		}
+		return c.SendString(strings.Repeat("n", int(n)))
+	})
+
+	testCases := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "not streamed", path: "/body", body: "abc", wantStatus: fiber.StatusOK, wantBody: "abc"},
+		{name: "streamed", path: "/body", body: "abcdefgh", wantStatus: fiber.StatusOK, wantBody: "abcdefgh"},
+		{name: "too large", path: "/body", body: "abcdefghi", wantStatus: fiber.StatusRequestEntityTooLarge},
+		{name: "read by the route", path: "/stream", body: strings.Repeat("a", 100), wantStatus: fiber.StatusOK, wantBody: strings.Repeat("n", 100)},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, tt.path, strings.NewReader(tt.body)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus != fiber.StatusOK {
+				return
+			}
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBody, string(body))
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strings"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed marks response replayed from storage
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLen = 255
+)
+
+// Idempotency replays stored response when request is repeated with the same Idempotency-Key.
+// Keys are scoped by caller and path. Only successful responses are stored, failed request can be retried.
+func Idempotency(idempotency usecase.IdempotencyUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			return fiber.ErrBadRequest
+		}
+		caller := "anonymous"
+		if userID, ok := auth.UserID(c.UserContext()); ok {
+			caller = userID.String()
+		}
+		key = strings.Join([]string{caller, c.Method(), c.Path(), key}, " ")
+		requestHash := sha256.Sum256(c.Body())
+
+		stored, err := idempotency.Begin(c.UserContext(), key, requestHash[:])
+		if err != nil {
+			if errors.Is(err, apperror.ErrIdempotencyKeyReused) {
+				return fiber.ErrUnprocessableEntity
+			}
+			if errors.Is(err, apperror.ErrRequestInProgress) {
+				return fiber.ErrConflict
+			}
+			log.Err(err).Msg("")
+			return fiber.ErrInternalServerError
+		}
+		if stored != nil {
+			c.Set(HeaderIdempotentReplayed, "true")
+			c.Set(fiber.HeaderContentType, stored.ContentType)
+			return c.Status(stored.Status).Send(stored.Body)
+		}
+
+		if err := c.Next(); err != nil || c.Response().StatusCode() >= fiber.StatusMultipleChoices {
+			if abortErr := idempotency.Abort(c.UserContext(), key); abortErr != nil {
+				log.Err(abortErr).Msg("")
+			}
+			return err
+		}
+		resp := model.IdempotentResp{
+			Status:      c.Response().StatusCode(),
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}
+		if err := idempotency.Finish(c.UserContext(), key, resp); err != nil {
+			log.Err(err).Msg("")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryIdempotencyRepo struct {
+	records map[string]model.DbIdempotencyRecord
+}
+
+func (r *memoryIdempotencyRepo) ClaimKey(_ context.Context, key string, requestHash []byte, _ time.Duration) (model.DbIdempotencyRecord, bool, error) {
+	if record, ok := r.records[key]; ok {
+		return record, false, nil
+	}
+	r.records[key] = model.DbIdempotencyRecord{Key: key, RequestHash: requestHash}
+	return model.DbIdempotencyRecord{}, true, nil
+}
+
+func (r *memoryIdempotencyRepo) SaveResponse(_ context.Context, key string, status int, contentType string, body []byte) error {
+	record := r.records[key]
+	record.Status, record.ContentType, record.Body = status, contentType, body
+	r.records[key] = record
+	return nil
+}
+
+func (r *memoryIdempotencyRepo) ReleaseKey(_ context.Context, key string) error {
+	delete(r.records, key)
+	return nil
+}
+
+func (r *memoryIdempotencyRepo) DeleteExpired(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestIdempotency(t *testing.T) {
+	a := assert.New(t)
+	repo := &memoryIdempotencyRepo{records: map[string]model.DbIdempotencyRecord{}}
+	app := fiber.New()
+	calls := 0
+	app.Post("/blog", Idempotency(usecase.NewIdempotencyProvider(repo, time.Hour)), func(c *fiber.Ctx) error {
+		calls++
+		if strings.Contains(string(c.Body()), "fail") {
+			return fiber.ErrBadRequest
+		}
+		return c.JSON(fiber.Map{"call": calls})
+	})
+	do := func(key, body string) (int, string, string) {
+		req := httptest.NewRequest(fiber.MethodPost, "/blog", bytes.NewBufferString(body))
+		req.Header.Set(HeaderIdempotencyKey, key)
+		resp, err := app.Test(req)
+		a.NoError(err)
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data), resp.Header.Get(HeaderIdempotentReplayed)
+	}
+
+	status, body, replayed := do("k1", `{"name":"a"}`)
+	a.Equal(fiber.StatusOK, status)
+	a.Equal(`{"call":1}`, body)
+	a.Empty(replayed)
+
+	status, body, replayed = do("k1", `{"name":"a"}`)
+	a.Equal(fiber.StatusOK, status)
+	a.Equal(`{"call":1}`, body)
+	a.Equal("true", replayed)
+
+	status, _, _ = do("k1", `{"name":"b"}`)
+	a.Equal(fiber.StatusUnprocessableEntity, status)
+
+	// failed request is not stored
+	status, _, _ = do("k2", `fail`)
+	a.Equal(fiber.StatusBadRequest, status)
+	status, _, _ = do("k2", `fail`)
+	a.Equal(fiber.StatusBadRequest, status)
+	a.Equal(3, calls)
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NotModified answers conditional GET with 304 when ETag or Last-Modified of the response
+// match the request. It works on cached responses too, so it is set before the cache.
+func NotModified(c *fiber.Ctx) error {
+	if err := c.Next(); err != nil {
+		return err
+	}
+	if c.Response().StatusCode() == fiber.StatusOK && notModified(c) {
+		c.Response().ResetBody()
+		c.Status(fiber.StatusNotModified)
+	}
+	return nil
+}
+
+// notModified follows RFC 9110: If-None-Match wins over If-Modified-Since when both are sent.
+// Ctx.Fresh is not used because it ignores the date when only If-Modified-Since is sent.
+func notModified(c *fiber.Ctx) bool {
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		etag := strings.TrimPrefix(c.GetRespHeader(fiber.HeaderETag), "W/")
+		if etag == "" {
+			return false
+		}
+		for _, tag := range strings.Split(noneMatch, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+	modifiedSince, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+	lastModified, err := http.ParseTime(c.GetRespHeader(fiber.HeaderLastModified))
+	if err != nil {
+		return false
+	}
+	return !lastModified.After(modifiedSince)
+}
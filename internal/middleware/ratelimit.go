@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/graph"
+	"github.com/Rolan335/project/internal/metric"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/ratelimit"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimit limits requests of authenticated user or client IP. It is set per route after Auth,
+// so that rejections are counted by route pattern and the user is known.
+func RateLimit(limiter *ratelimit.Limiter) fiber.Handler {
+	return rateLimit(limiter, func(c *fiber.Ctx) string {
+		if userID, ok := auth.UserID(c.UserContext()); ok {
+			return "user:" + userID.String()
+		}
+		return "ip:" + c.IP()
+	})
+}
+
+// IPRateLimit limits requests of client IP. It is set on the group before Auth,
+// so that checking tokens and API keys is limited too.
+func IPRateLimit(limiter *ratelimit.Limiter) fiber.Handler {
+	return rateLimit(limiter, func(c *fiber.Ctx) string {
+		return "ip:" + c.IP()
+	})
+}
+
+// GraphQLRateLimit counts requests with a mutation against write limiter and the rest against read limiter
+func GraphQLRateLimit(readLimiter, writeLimiter *ratelimit.Limiter) fiber.Handler {
+	read, write := RateLimit(readLimiter), RateLimit(writeLimiter)
+	return func(c *fiber.Ctx) error {
+		var req model.GraphQLReq
+		// a body which can't be parsed is rejected by the handler
+		if err := c.BodyParser(&req); err == nil && graph.HasMutation(req.Query) {
+			return write(c)
+		}
+		return read(c)
+	}
+}
+
+func rateLimit(limiter *ratelimit.Limiter, key func(c *fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		res := limiter.Allow(key(c))
+		if !res.Allowed {
+			metric.RateLimitRejections.WithLabelValues(c.Method(), c.Route().Path).Inc()
+			setRateLimitHeaders(c, res)
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds(res.RetryAfter)))
+			return fiber.ErrTooManyRequests
+		}
+		err := c.Next()
+		// set after the handler, so that response cache down the chain can't store them
+		setRateLimitHeaders(c, res)
+		return err
+	}
+}
+
+func setRateLimitHeaders(c *fiber.Ctx, res ratelimit.Result) {
+	c.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	c.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	c.Set("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/ratelimit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	a := assert.New(t)
+	app := fiber.New()
+	app.Get("/blog/:blog_id", RateLimit(ratelimit.New(1, 1)), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/blog/1", nil))
+	a.NoError(err)
+	a.Equal(fiber.StatusOK, resp.StatusCode)
+	a.Equal("1", resp.Header.Get("RateLimit-Limit"))
+	a.Equal("0", resp.Header.Get("RateLimit-Remaining"))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/blog/2", nil))
+	a.NoError(err)
+	a.Equal(fiber.StatusTooManyRequests, resp.StatusCode)
+	a.Equal("1", resp.Header.Get(fiber.HeaderRetryAfter))
+}
+
+func TestIPRateLimit(t *testing.T) {
+	a := assert.New(t)
+	app := fiber.New()
+	app.Use(IPRateLimit(ratelimit.New(1, 1)))
+	app.Use(Auth(auth.NewVerifier([]byte("secret"), nil), nil))
+	app.Get("/blog/:blog_id", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// guessing tokens is limited before they are verified
+	req := httptest.NewRequest(fiber.MethodGet, "/blog/1", nil)
+	req.Header.Set(fiber.HeaderAuthorization, bearerPrefix+"invalid")
+	resp, err := app.Test(req)
+	a.NoError(err)
+	a.Equal(fiber.StatusUnauthorized, resp.StatusCode)
+
+	req = httptest.NewRequest(fiber.MethodGet, "/blog/1", nil)
+	req.Header.Set(fiber.HeaderAuthorization, bearerPrefix+"invalid")
+	resp, err = app.Test(req)
+	a.NoError(err)
+	a.Equal(fiber.StatusTooManyRequests, resp.StatusCode)
+}
+
+func TestGraphQLRateLimit(t *testing.T) {
+	app := fiber.New()
+	app.Post("/graphql", GraphQLRateLimit(ratelimit.New(1, 1), ratelimit.New(1, 1)), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	post := func(query string) int {
+		req := httptest.NewRequest(fiber.MethodPost, "/graphql", strings.NewReader(`{"query":`+strconv.Quote(query)+`}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusOK, post(`mutation { deletePost(id: "1") }`))
+	assert.Equal(t, fiber.StatusTooManyRequests, post(`mutation { deletePost(id: "2") }`))
+	// queries have their own bucket
+	assert.Equal(t, fiber.StatusOK, post(`{ me { id } }`))
+	assert.Equal(t, fiber.StatusTooManyRequests, post(`{ me { id } }`))
+}
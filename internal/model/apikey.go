@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyPostReq struct {
+	Name   string   `json:"name" validate:"required,min=1,max=64"`
+	Scopes []string `json:"scopes" validate:"required,min=1,max=3,dive,oneof=read write:posts admin"`
+}
+
+// APIKeyPostResp is the only place where the key is shown, only its hash is stored
+type APIKeyPostResp struct {
+	KeyID     uuid.UUID `json:"id"`
+	Key       string    `json:"key"`
+	Prefix    string    `json:"prefix"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type APIKeyResp struct {
+	KeyID     uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Prefix    string    `json:"prefix"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type APIKeysGetResp struct {
+	Keys []APIKeyResp `json:"keys"`
+}
+
+type APIKeyDeleteReq struct {
+	KeyID uuid.UUID `json:"key_id" validate:"required,uuid"`
+}
+
+// APIKeyAuthResp is the caller authenticated by API key
+type APIKeyAuthResp struct {
+	UserID uuid.UUID
+	Scopes []string
+}
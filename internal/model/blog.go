@@ -6,67 +6,130 @@ import (
 	"github.com/google/uuid"
 )
 
+// post statuses, drafts and scheduled posts are visible only to the blog owner
+const (
+	PostStatusDraft     = "draft"
+	PostStatusScheduled = "scheduled"
+	PostStatusPublished = "published"
+)
+
 type BlogGetReq struct {
 	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
 }
 
 type BlogGetResp struct {
-	BlogID    uuid.UUID `json:"id"`
-	UserID    uuid.UUID `json:"user_id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
+	BlogID    uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Name      string     `json:"name"`
+	Slug      string     `json:"slug"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Version   int        `json:"version"`
 }
+
+// BlogPostReq creates blog owned by the authenticated user
 type BlogPostReq struct {
-	UserID uuid.UUID `json:"user_id" validate:"required,uuid"`
-	Name   string    `json:"name" validate:"required,min=1,max=64"`
+	Name string `json:"name" validate:"required,min=1,max=64"`
 }
 
 type BlogPostResp struct {
 	BlogID uuid.UUID `json:"id"`
+	Slug   string    `json:"slug"`
 }
 
 type BlogPutReq struct {
 	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
-	UserID uuid.UUID `json:"user_id" validate:"required,uuid"`
 	Name   string    `json:"name" validate:"required,min=1,max=64"`
+	// Version is taken from If-Match header, zero means unconditional update
+	Version int `json:"-" validate:"min=0"`
 }
 
 type BlogPutResp struct {
 	BlogID    uuid.UUID `json:"id"`
 	UserID    uuid.UUID `json:"user_id"`
 	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
 	CreatedAt time.Time `json:"created_at"`
+	Version   int       `json:"version"`
 }
 
 type BlogDeleteReq struct {
 	BlogID uuid.UUID `json:"id" validate:"required,uuid"`
 }
 
+// BlogsTrashGetReq lists deleted blogs of the authenticated user
+type BlogsTrashGetReq struct{}
+
+type BlogsTrashGetResp struct {
+	Blogs []BlogGetResp `json:"blogs"`
+}
+
+type BlogRestoreReq struct {
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+}
+
 type PostsGetReq struct {
 	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	Limit  int       `json:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor string    `json:"cursor"`
+	Tag    string    `json:"tag" validate:"omitempty,max=32"`
+	// RenderHTML fills PostGetResp.HTML with rendered text
+	RenderHTML bool `json:"render_html"`
+}
+
+type PostsGetResp struct {
+	Posts      []PostGetResp `json:"posts"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// BlogsPostsGetReq gets the first page of posts of many blogs at once
+type BlogsPostsGetReq struct {
+	BlogIDs []uuid.UUID `json:"blog_ids" validate:"required,max=100"`
+	Limit   int         `json:"limit" validate:"omitempty,min=1,max=100"`
+	Tag     string      `json:"tag" validate:"omitempty,max=32"`
+}
+
+// BlogsPostsGetResp has a page for every requested blog, unknown blogs get empty pages
+type BlogsPostsGetResp struct {
+	Posts map[uuid.UUID]PostsGetResp `json:"posts"`
 }
 
 type PostGetReq struct {
-	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
-	PostID uuid.UUID `json:"post_id" validate:"required,uuid"`
+	BlogID     uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	PostID     uuid.UUID `json:"post_id" validate:"required,uuid"`
+	RenderHTML bool      `json:"render_html"`
 }
 
 type PostGetResp struct {
-	PostID    uuid.UUID `json:"post_id"`
-	BlogID    uuid.UUID `json:"blog_id"`
-	Title     string    `json:"title"`
-	Text      string    `json:"text"`
-	CreatedAt time.Time `json:"created_at"`
+	PostID        uuid.UUID  `json:"post_id"`
+	BlogID        uuid.UUID  `json:"blog_id"`
+	Title         string     `json:"title"`
+	Slug          string     `json:"slug"`
+	Text          string     `json:"text"`
+	HTML          string     `json:"html,omitempty"`
+	Status        string     `json:"status"`
+	PublishAt     *time.Time `json:"publish_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	Version       int        `json:"version"`
+	Tags          []string   `json:"tags"`
+	CommentsCount int        `json:"comments_count"`
 }
 
 type PostPostReq struct {
 	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
 	Title  string    `json:"title" validate:"required,min=1,max=64"`
 	Text   string    `json:"text" validate:"required,min=1,max=2048"`
+	Tags   []string  `json:"tags" validate:"omitempty,max=16,dive,min=1,max=32"`
+	// Status is published when omitted, scheduled post is published at PublishAt
+	Status    string     `json:"status" validate:"omitempty,oneof=draft scheduled published"`
+	PublishAt *time.Time `json:"publish_at" validate:"required_if=Status scheduled"`
 }
 
 type PostPostResp struct {
 	PostID uuid.UUID `json:"post_id"`
+	Slug   string    `json:"slug"`
+	Status string    `json:"status"`
 }
 
 type PostPutReq struct {
@@ -74,17 +137,193 @@ type PostPutReq struct {
 	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
 	Title  string    `json:"title" validate:"required,min=1,max=64"`
 	Text   string    `json:"text" validate:"required,min=1,max=2048"`
+	// Tags replace tags of the post, tags are left as is when the field is omitted
+	Tags []string `json:"tags" validate:"omitempty,max=16,dive,min=1,max=32"`
+	// Status is left as is when omitted
+	Status    string     `json:"status" validate:"omitempty,oneof=draft scheduled published"`
+	PublishAt *time.Time `json:"publish_at" validate:"required_if=Status scheduled"`
+	// Version is taken from If-Match header, zero means unconditional update
+	Version int `json:"-" validate:"min=0"`
 }
 
 type PostPutResp struct {
+	PostID    uuid.UUID  `json:"post_id"`
+	BlogID    uuid.UUID  `json:"blog_id"`
+	Title     string     `json:"title"`
+	Slug      string     `json:"slug"`
+	Text      string     `json:"text"`
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	Version   int        `json:"version"`
+	Tags      []string   `json:"tags"`
+}
+
+type PostDeleteReq struct {
+	PostID uuid.UUID `json:"post_id" validate:"required,uuid"`
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+}
+
+type PostEventsReq struct {
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	// LastEventID resumes the stream after the event the client has got last
+	LastEventID string `json:"last_event_id" validate:"max=64"`
+}
+
+// PostDeletedEvent is data of post.deleted stream event
+type PostDeletedEvent struct {
+	PostID uuid.UUID `json:"post_id"`
+	BlogID uuid.UUID `json:"blog_id"`
+}
+
+type PostsTrashGetReq struct {
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+}
+
+type PostsTrashGetResp struct {
+	Posts []PostGetResp `json:"posts"`
+}
+
+type PostRestoreReq struct {
+	PostID uuid.UUID `json:"post_id" validate:"required,uuid"`
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+}
+
+type PostsSearchReq struct {
+	Query  string     `json:"q" validate:"required,min=1,max=256"`
+	BlogID *uuid.UUID `json:"blog_id" validate:"omitempty,uuid"`
+	Limit  int        `json:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type PostSearchResp struct {
 	PostID    uuid.UUID `json:"post_id"`
 	BlogID    uuid.UUID `json:"blog_id"`
 	Title     string    `json:"title"`
-	Text      string    `json:"text"`
+	Slug      string    `json:"slug"`
+	Snippet   string    `json:"snippet"`
+	Rank      float32   `json:"rank"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
-type PostDeleteReq struct {
+type PostsSearchResp struct {
+	Posts []PostSearchResp `json:"posts"`
+}
+
+type PostRevisionsGetReq struct {
 	PostID uuid.UUID `json:"post_id" validate:"required,uuid"`
 	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
 }
+
+type PostRevisionResp struct {
+	Revision  int       `json:"revision"`
+	Title     string    `json:"title"`
+	Text      string    `json:"text,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PostRevisionsGetResp struct {
+	Revisions []PostRevisionResp `json:"revisions"`
+}
+
+type PostRevisionGetReq struct {
+	PostID   uuid.UUID `json:"post_id" validate:"required,uuid"`
+	BlogID   uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	Revision int       `json:"revision" validate:"required,min=1"`
+}
+
+type PostRevisionsDiffReq struct {
+	PostID uuid.UUID `json:"post_id" validate:"required,uuid"`
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	From   int       `json:"from" validate:"required,min=1"`
+	To     int       `json:"to" validate:"required,min=1"`
+}
+
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+type PostRevisionsDiffResp struct {
+	From  int        `json:"from"`
+	To    int        `json:"to"`
+	Title []DiffLine `json:"title"`
+	Text  []DiffLine `json:"text"`
+}
+
+type PostRevisionRestoreReq struct {
+	PostID   uuid.UUID `json:"post_id" validate:"required,uuid"`
+	BlogID   uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	Revision int       `json:"revision" validate:"required,min=1"`
+}
+
+type TagsGetReq struct {
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+}
+
+type TagResp struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type TagsGetResp struct {
+	Tags []TagResp `json:"tags"`
+}
+
+// PostImportLine is a post read from line of NDJSON import
+type PostImportLine struct {
+	Line int
+	Post PostPostReq
+}
+
+type PostsImportReq struct {
+	BlogID uuid.UUID
+	// Atomic imports all lines or none of them, otherwise valid lines are imported
+	Atomic bool
+	// Next reads up to n next valid lines from the body, it returns io.EOF after the last one.
+	// It is not called before the caller is allowed to import into the blog.
+	Next func(n int) ([]PostImportLine, error)
+}
+
+type PostImportError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+type PostsImportResp struct {
+	Imported int               `json:"imported"`
+	Errors   []PostImportError `json:"errors"`
+}
+
+type BlogExportReq struct {
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	Format string    `json:"format" validate:"oneof=json markdown"`
+}
+
+type FeedGetReq struct {
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	Limit  int       `json:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// FeedGetResp holds the newest posts of the blog, newest first
+type FeedGetResp struct {
+	Blog  BlogGetResp
+	Posts []PostGetResp
+	// Updated is the time of the newest change seen in the feed
+	Updated time.Time
+}
+
+type BlogSlugGetReq struct {
+	Slug string `json:"slug" validate:"required,max=64"`
+}
+
+type PostSlugGetReq struct {
+	BlogSlug   string `json:"blog_slug" validate:"required,max=64"`
+	PostSlug   string `json:"post_slug" validate:"required,max=64"`
+	RenderHTML bool   `json:"render_html"`
+}
+
+// PostSlugGetResp has current slugs of the blog and the post, they differ from requested ones for old slugs
+type PostSlugGetResp struct {
+	BlogSlug string
+	Post     PostGetResp
+}
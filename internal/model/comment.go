@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommentPostReq comments the post on behalf of the authenticated user
+type CommentPostReq struct {
+	BlogID   uuid.UUID  `json:"blog_id" validate:"required,uuid"`
+	PostID   uuid.UUID  `json:"post_id" validate:"required,uuid"`
+	ParentID *uuid.UUID `json:"parent_id" validate:"omitempty,uuid"`
+	Text     string     `json:"text" validate:"required,min=1,max=1024"`
+}
+
+type CommentPostResp struct {
+	CommentID uuid.UUID `json:"comment_id"`
+}
+
+// CommentsGetReq lists replies to ParentID, top level comments are listed when it is nil
+type CommentsGetReq struct {
+	BlogID   uuid.UUID  `json:"blog_id" validate:"required,uuid"`
+	PostID   uuid.UUID  `json:"post_id" validate:"required,uuid"`
+	ParentID *uuid.UUID `json:"parent_id" validate:"omitempty,uuid"`
+	Limit    int        `json:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor   string     `json:"cursor"`
+}
+
+type CommentGetResp struct {
+	CommentID    uuid.UUID  `json:"comment_id"`
+	PostID       uuid.UUID  `json:"post_id"`
+	ParentID     *uuid.UUID `json:"parent_id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	Text         string     `json:"text"`
+	RepliesCount int        `json:"replies_count"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+type CommentsGetResp struct {
+	Comments   []CommentGetResp `json:"comments"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+type CommentPutReq struct {
+	BlogID    uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	PostID    uuid.UUID `json:"post_id" validate:"required,uuid"`
+	CommentID uuid.UUID `json:"comment_id" validate:"required,uuid"`
+	Text      string    `json:"text" validate:"required,min=1,max=1024"`
+}
+
+type CommentPutResp struct {
+	CommentID uuid.UUID  `json:"comment_id"`
+	PostID    uuid.UUID  `json:"post_id"`
+	ParentID  *uuid.UUID `json:"parent_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Text      string     `json:"text"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type CommentDeleteReq struct {
+	BlogID    uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	PostID    uuid.UUID `json:"post_id" validate:"required,uuid"`
+	CommentID uuid.UUID `json:"comment_id" validate:"required,uuid"`
+}
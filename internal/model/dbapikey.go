@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DbAPIKey struct {
+	ID     uuid.UUID `db:"id"`
+	UserID uuid.UUID `db:"users_id"`
+	Name   string    `db:"name"`
+	// Prefix is a public part of the key used to find it
+	Prefix string `db:"prefix"`
+	Salt   []byte `db:"salt"`
+	// Hash is sha256 of salt and secret part of the key
+	Hash      []byte     `db:"hash"`
+	Scopes    []string   `db:"scopes"`
+	CreatedAt time.Time  `db:"created_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
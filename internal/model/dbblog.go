@@ -7,20 +7,89 @@ import (
 )
 
 type DbUser struct {
-	ID uuid.UUID `json:"id,omitempty" db:"id"`
+	ID          uuid.UUID `json:"id,omitempty" db:"id"`
+	DisplayName string    `json:"display_name,omitempty" db:"display_name"`
+	Email       string    `json:"email,omitempty" db:"email"`
+	Bio         string    `json:"bio,omitempty" db:"bio"`
+	CreatedAt   time.Time `json:"created_at,omitempty" db:"created_at"`
 }
 
 type DbBlog struct {
-	ID        uuid.UUID `json:"id,omitempty" db:"id"`
-	UserID    uuid.UUID `json:"user_id,omitempty" db:"users_id"`
-	Name      string    `json:"name,omitempty" db:"name"`
-	CreatedAt time.Time `json:"created_at,omitempty" db:"created_at"`
+	ID        uuid.UUID  `json:"id,omitempty" db:"id"`
+	UserID    uuid.UUID  `json:"user_id,omitempty" db:"users_id"`
+	Name      string     `json:"name,omitempty" db:"name"`
+	Slug      string     `json:"slug,omitempty" db:"slug"`
+	CreatedAt time.Time  `json:"created_at,omitempty" db:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	Version   int        `json:"version,omitempty" db:"version"`
 }
 
 type DbPost struct {
-	ID        uuid.UUID `json:"id,omitempty" db:"id"`
-	BlogID    uuid.UUID `json:"blog_id,omitempty" db:"blogs_id"`
-	Title     string    `json:"title,omitempty" db:"title"`
-	Text      string    `json:"text,omitempty" db:"text"`
-	CreatedAt time.Time `json:"created_at,omitempty" db:"created_at"`
+	ID            uuid.UUID  `json:"id,omitempty" db:"id"`
+	BlogID        uuid.UUID  `json:"blog_id,omitempty" db:"blogs_id"`
+	Title         string     `json:"title,omitempty" db:"title"`
+	Text          string     `json:"text,omitempty" db:"text"`
+	Slug          string     `json:"slug,omitempty" db:"slug"`
+	Status        string     `json:"status,omitempty" db:"status"`
+	PublishAt     *time.Time `json:"publish_at,omitempty" db:"publish_at"`
+	CreatedAt     time.Time  `json:"created_at,omitempty" db:"created_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	Version       int        `json:"version,omitempty" db:"version"`
+	Tags          []string   `json:"tags,omitempty" db:"tags"`
+	CommentsCount int        `json:"comments_count,omitempty" db:"comments_count"`
+}
+
+// DbCursor points at the last row of a keyset page ordered by (created_at, id)
+type DbCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+type DbPostsFilter struct {
+	BlogID uuid.UUID
+	After  *DbCursor
+	Limit  int
+	// Tag filters posts by tag, empty means any
+	Tag string
+	// OnlyPublished hides drafts and scheduled posts
+	OnlyPublished bool
+}
+
+// DbBlogsPostsFilter selects the first page of posts of many blogs at once
+type DbBlogsPostsFilter struct {
+	BlogIDs []uuid.UUID
+	// Limit is the number of posts of each blog
+	Limit int
+	Tag   string
+	// ViewerID also sees drafts and scheduled posts of own blogs, nil sees published posts only
+	ViewerID *uuid.UUID
+}
+
+type DbPostsSearch struct {
+	Query  string
+	BlogID *uuid.UUID
+	Limit  int
+}
+
+type DbPostSearchResult struct {
+	ID        uuid.UUID `db:"id"`
+	BlogID    uuid.UUID `db:"blogs_id"`
+	Title     string    `db:"title"`
+	Slug      string    `db:"slug"`
+	Snippet   string    `db:"snippet"`
+	Rank      float32   `db:"rank"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type DbPostRevision struct {
+	PostID    uuid.UUID `db:"posts_id"`
+	Revision  int       `db:"revision"`
+	Title     string    `db:"title"`
+	Text      string    `db:"text"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type DbTagCount struct {
+	Name  string `db:"name"`
+	Count int    `db:"count"`
 }
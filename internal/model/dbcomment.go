@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DbComment struct {
+	ID           uuid.UUID  `json:"id,omitempty" db:"id"`
+	PostID       uuid.UUID  `json:"post_id,omitempty" db:"posts_id"`
+	ParentID     *uuid.UUID `json:"parent_id,omitempty" db:"parent_id"`
+	UserID       uuid.UUID  `json:"user_id,omitempty" db:"users_id"`
+	Text         string     `json:"text,omitempty" db:"text"`
+	RepliesCount int        `json:"replies_count,omitempty" db:"replies_count"`
+	CreatedAt    time.Time  `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// DbCommentOwners are the users allowed to change the comment: its author and the owner of the blog
+type DbCommentOwners struct {
+	AuthorID    uuid.UUID `db:"author_id"`
+	BlogOwnerID uuid.UUID `db:"blog_owner_id"`
+}
+
+// DbPostAccess tells who can see the commented post
+type DbPostAccess struct {
+	Status      string    `db:"status"`
+	BlogOwnerID uuid.UUID `db:"blog_owner_id"`
+}
+
+type DbCommentsFilter struct {
+	BlogID   uuid.UUID
+	PostID   uuid.UUID
+	ParentID *uuid.UUID
+	After    *DbCursor
+	Limit    int
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type DbIdempotencyRecord struct {
+	Key         string    `db:"key"`
+	RequestHash []byte    `db:"request_hash"`
+	Status      int       `db:"status"`
+	ContentType string    `db:"content_type"`
+	Body        []byte    `db:"body"`
+	ExpiresAt   time.Time `db:"expires_at"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// domain events written to outbox by BlogRepo
+const (
+	EventBlogCreated   = "blog.created"
+	EventBlogUpdated   = "blog.updated"
+	EventBlogDeleted   = "blog.deleted"
+	EventBlogRestored  = "blog.restored"
+	EventPostCreated   = "post.created"
+	EventPostUpdated   = "post.updated"
+	EventPostDeleted   = "post.deleted"
+	EventPostRestored  = "post.restored"
+	EventPostPublished = "post.published"
+)
+
+// DbOutboxEvent is a domain event, events are delivered in ID order
+type DbOutboxEvent struct {
+	ID          int64           `json:"id" db:"id"`
+	Type        string          `json:"type" db:"event_type"`
+	AggregateID uuid.UUID       `json:"aggregate_id" db:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	// TraceContext is the trace of the request that made the event, it is not a part of the event
+	TraceContext map[string]string `json:"-" db:"trace_context"`
+}
+
+// DeletedPayload is payload of deleted events
+type DeletedPayload struct {
+	ID     uuid.UUID `json:"id"`
+	BlogID uuid.UUID `json:"blog_id"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// states of a webhook delivery, a pending delivery is tried until it is delivered
+// or runs out of attempts and becomes dead
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+type DbWebhook struct {
+	ID     uuid.UUID `db:"id"`
+	BlogID uuid.UUID `db:"blogs_id"`
+	URL    string    `db:"url"`
+	// Secret signs deliveries, it is kept as is because signing needs it
+	Secret    string    `db:"secret"`
+	Events    []string  `db:"events"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type DbWebhookDelivery struct {
+	ID        uuid.UUID `db:"id"`
+	WebhookID uuid.UUID `db:"webhooks_id"`
+	EventID   int64     `db:"event_id"`
+	EventType string    `db:"event_type"`
+	// Payload is the request body, it is signed and sent as is on every attempt
+	Payload        json.RawMessage   `db:"payload"`
+	TraceContext   map[string]string `db:"trace_context"`
+	Status         string            `db:"status"`
+	Attempts       int               `db:"attempts"`
+	NextAttemptAt  time.Time         `db:"next_attempt_at"`
+	ResponseStatus *int              `db:"response_status"`
+	LastError      *string           `db:"last_error"`
+	CreatedAt      time.Time         `db:"created_at"`
+	DeliveredAt    *time.Time        `db:"delivered_at"`
+	// URL and Secret of the webhook are filled for claimed deliveries only
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+// DbWebhookAttempt is the outcome of one delivery attempt
+type DbWebhookAttempt struct {
+	DeliveryID uuid.UUID
+	// Status is the state of the delivery after the attempt
+	Status         string
+	ResponseStatus *int
+	Error          *string
+	NextAttemptAt  time.Time
+}
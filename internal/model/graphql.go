@@ -0,0 +1,8 @@
+package model
+
+// GraphQLReq is an operation sent to POST /api/graphql
+type GraphQLReq struct {
+	Query         string         `json:"query" validate:"required,max=16384"`
+	OperationName string         `json:"operationName" validate:"max=128"`
+	Variables     map[string]any `json:"variables"`
+}
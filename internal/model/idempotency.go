@@ -0,0 +1,8 @@
+package model
+
+// IdempotentResp is a stored response replayed for repeated request
+type IdempotentResp struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
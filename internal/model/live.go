@@ -0,0 +1,55 @@
+package model
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// types of messages of the live updates websocket
+const (
+	LiveSubscribe    = "subscribe"
+	LiveUnsubscribe  = "unsubscribe"
+	LivePresence     = "presence"
+	LiveSubscribed   = "subscribed"
+	LiveUnsubscribed = "unsubscribed"
+	LiveEvent        = "event"
+	LiveError        = "error"
+)
+
+// LiveClientMessage is a message sent by a websocket client
+type LiveClientMessage struct {
+	Type   string    `json:"type" validate:"required,oneof=subscribe unsubscribe presence"`
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	PostID uuid.UUID `json:"post_id" validate:"required_if=Type presence"`
+	State  string    `json:"state" validate:"required_if=Type presence,omitempty,oneof=viewing editing idle"`
+}
+
+// LiveServerMessage is a message sent to a websocket client. Event messages carry a post event
+// of the stream with its id and data, presence messages carry PresenceEvent.
+type LiveServerMessage struct {
+	Type    string          `json:"type"`
+	BlogID  *uuid.UUID      `json:"blog_id,omitempty"`
+	EventID string          `json:"id,omitempty"`
+	Event   string          `json:"event,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+type PresenceSubscribeReq struct {
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+}
+
+type PresencePostReq struct {
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	PostID uuid.UUID `json:"post_id" validate:"required,uuid"`
+	State  string    `json:"state" validate:"required,oneof=viewing editing idle"`
+}
+
+// PresenceEvent tells subscribers of the blog what the user is doing with the post
+type PresenceEvent struct {
+	BlogID uuid.UUID `json:"blog_id"`
+	PostID uuid.UUID `json:"post_id"`
+	UserID uuid.UUID `json:"user_id"`
+	State  string    `json:"state"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserPostReq struct {
+	DisplayName string `json:"display_name" validate:"required,min=1,max=64"`
+	Email       string `json:"email" validate:"required,email,max=254"`
+	Bio         string `json:"bio" validate:"max=1024"`
+}
+
+type UserPostResp struct {
+	UserID uuid.UUID `json:"id"`
+}
+
+type UserGetReq struct {
+	UserID uuid.UUID `json:"user_id" validate:"required,uuid"`
+}
+
+// UserGetResp is a public profile, Email is filled only for the user themself
+type UserGetResp struct {
+	UserID      uuid.UUID `json:"id"`
+	DisplayName string    `json:"display_name"`
+	Email       string    `json:"email,omitempty"`
+	Bio         string    `json:"bio"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type UserPutReq struct {
+	UserID      uuid.UUID `json:"user_id" validate:"required,uuid"`
+	DisplayName string    `json:"display_name" validate:"required,min=1,max=64"`
+	Email       string    `json:"email" validate:"required,email,max=254"`
+	Bio         string    `json:"bio" validate:"max=1024"`
+}
+
+type UserPutResp struct {
+	UserID      uuid.UUID `json:"id"`
+	DisplayName string    `json:"display_name"`
+	Email       string    `json:"email"`
+	Bio         string    `json:"bio"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type UserBlogsGetReq struct {
+	UserID uuid.UUID `json:"user_id" validate:"required,uuid"`
+}
+
+type UserBlogsGetResp struct {
+	Blogs []BlogGetResp `json:"blogs"`
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookPostReq struct {
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	URL    string    `json:"url" validate:"required,http_url,max=2048"`
+	Events []string  `json:"events" validate:"required,min=1,max=9,dive,oneof=blog.created blog.updated blog.deleted blog.restored post.created post.updated post.deleted post.restored post.published"`
+}
+
+// WebhookPostResp is the only place where the secret is shown
+type WebhookPostResp struct {
+	WebhookID uuid.UUID `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookResp struct {
+	WebhookID uuid.UUID `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhooksGetReq struct {
+	BlogID uuid.UUID `json:"blog_id" validate:"required,uuid"`
+}
+
+type WebhooksGetResp struct {
+	Webhooks []WebhookResp `json:"webhooks"`
+}
+
+type WebhookDeleteReq struct {
+	BlogID    uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	WebhookID uuid.UUID `json:"webhook_id" validate:"required,uuid"`
+}
+
+type WebhookDeliveriesGetReq struct {
+	BlogID    uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	WebhookID uuid.UUID `json:"webhook_id" validate:"required,uuid"`
+	Limit     int       `json:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type WebhookDeliveryResp struct {
+	DeliveryID     uuid.UUID       `json:"id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+type WebhookDeliveriesGetResp struct {
+	Deliveries []WebhookDeliveryResp `json:"deliveries"`
+}
+
+type WebhookRedeliverReq struct {
+	BlogID     uuid.UUID `json:"blog_id" validate:"required,uuid"`
+	WebhookID  uuid.UUID `json:"webhook_id" validate:"required,uuid"`
+	DeliveryID uuid.UUID `json:"delivery_id" validate:"required,uuid"`
+}
@@ -0,0 +1,181 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const componentsPrefix = "#/components/schemas/"
+
+var (
+	uuidType    = reflect.TypeOf(uuid.UUID{})
+	timeType    = reflect.TypeOf(time.Time{})
+	rawJSONType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemas builds JSON Schemas of model types, structs are registered in components
+// and referenced by their Go names
+type schemas map[string]*Schema
+
+// ref registers the struct and returns a reference to it, properties named in omit are left out
+func (s schemas) ref(t reflect.Type, omit ...string) *Schema {
+	name := t.Name()
+	if _, ok := s[name]; !ok {
+		// the placeholder stops recursion of self referencing types
+		s[name] = nil
+		s[name] = s.object(t, omit)
+	}
+	return &Schema{Ref: componentsPrefix + name}
+}
+
+func (s schemas) object(t reflect.Type, omit []string) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, ok := jsonName(field)
+		if !ok || slices.Contains(omit, name) {
+			continue
+		}
+		prop, required := s.field(field)
+		schema.Properties[name] = prop
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+// property returns schema of the struct field with the json name
+func (s schemas) property(t reflect.Type, name string) (schema *Schema, required bool, ok bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if fieldName, ok := jsonName(field); ok && fieldName == name {
+			schema, required = s.field(field)
+			return schema, required, true
+		}
+	}
+	return nil, false, false
+}
+
+// field returns schema of the field with constraints of its validate tag
+func (s schemas) field(field reflect.StructField) (*Schema, bool) {
+	schema := s.of(field.Type)
+	return schema, applyRules(schema, field.Tag.Get("validate"))
+}
+
+func (s schemas) of(t reflect.Type) *Schema {
+	switch t {
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawJSONType:
+		return &Schema{}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := s.of(t.Elem())
+		if typ, ok := schema.Type.(string); ok {
+			schema.Type = []string{typ, "null"}
+		}
+		return schema
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		return s.ref(t)
+	}
+	// interfaces hold any JSON value
+	return &Schema{}
+}
+
+// jsonName returns the name encoding/json gives the field, false for skipped fields
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// applyRules sets constraints of validator rules on the schema and reports whether the value is required.
+// Rules after dive apply to items of the list.
+func applyRules(schema *Schema, tag string) bool {
+	var required bool
+	target := schema
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = target == schema
+		case "required_if":
+			field, value, _ := strings.Cut(param, " ")
+			target.Description = "required when " + strings.ToLower(field) + " is " + value
+		case "dive":
+			if target.Items != nil {
+				target = target.Items
+			}
+		case "min", "max", "len":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			bound(target, name, n)
+		case "oneof":
+			target.Enum = strings.Fields(param)
+		case "email":
+			target.Format = "email"
+		case "uuid":
+			target.Format = "uuid"
+		case "url", "http_url":
+			target.Format = "uri"
+		}
+	}
+	return required
+}
+
+// bound sets min or max of the schema, it limits length of strings, items of lists and value of numbers
+func bound(schema *Schema, rule string, n int) {
+	var lower, upper **int
+	switch {
+	case schema.Is("string"):
+		lower, upper = &schema.MinLength, &schema.MaxLength
+	case schema.Is("array"):
+		lower, upper = &schema.MinItems, &schema.MaxItems
+	case schema.Is("integer"), schema.Is("number"):
+		lower, upper = &schema.Minimum, &schema.Maximum
+	default:
+		return
+	}
+	if rule != "max" {
+		*lower = &n
+	}
+	if rule != "min" {
+		*upper = &n
+	}
+}
@@ -0,0 +1,490 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Rolan335/project/internal/model"
+)
+
+const (
+	contentJSON   = "application/json"
+	contentNDJSON = "application/x-ndjson"
+)
+
+// route documents one route of app.GetRouter, paths are written the way they are registered in fiber
+type route struct {
+	method  string
+	path    string
+	id      string
+	tag     string
+	summary string
+	// description is a longer explanation shown next to summary
+	description string
+	// req is the model the handler fills. Path parameters and params with no schema are its fields,
+	// the rest of its fields are the request body when body is set.
+	req    any
+	params []param
+	body   string
+	resp   []response
+	// errors are statuses of failures besides the ones every route may answer with
+	errors []int
+}
+
+type param struct {
+	in   string
+	name string
+	// field is the json name of the field of route.req, name when empty
+	field       string
+	schema      *Schema
+	description string
+}
+
+// response body is a model, a *Schema or nil for an empty body
+type response struct {
+	status      int
+	content     string
+	body        any
+	description string
+}
+
+func query(name string) param {
+	return param{in: "query", name: name}
+}
+
+func success(body any) response {
+	return response{status: http.StatusOK, content: contentJSON, body: body}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+var (
+	renderParam = param{in: "query", name: "render", schema: &Schema{Type: "string", Enum: []string{"html"}},
+		description: "html fills the html field with text rendered from markdown"}
+	ifMatchParam = param{in: "header", name: "If-Match", schema: &Schema{Type: "string"},
+		description: "ETag of the version being changed as sent by GET or a previous update, " +
+			"the update fails with 412 when it is not the current one or the tag is weak"}
+	idempotencyParam = param{in: "header", name: "Idempotency-Key", schema: &Schema{Type: "string", MaxLength: intPtr(255)},
+		description: "a repeated request with the same key gets the stored response instead of being applied again"}
+	slugMoved = response{status: http.StatusMovedPermanently,
+		description: "Old slug, Location has the URL with the current one"}
+	notModified = response{status: http.StatusNotModified,
+		description: "Not Modified, the feed has not changed since If-None-Match or If-Modified-Since"}
+)
+
+// commonErrors may be answered by every route: by authentication, rate limits or failures
+var commonErrors = []int{
+	http.StatusUnauthorized,
+	http.StatusForbidden,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+}
+
+var routes = []route{
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id", id: "GetBlog", tag: "blogs", summary: "Get blog",
+		req: model.BlogGetReq{}, resp: []response{success(model.BlogGetResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodPost, path: "/api/blog", id: "CreateBlog", tag: "blogs", summary: "Create blog owned by the caller",
+		req: model.BlogPostReq{}, params: []param{idempotencyParam}, body: contentJSON,
+		resp: []response{success(model.BlogPostResp{})}, errors: []int{400, 409, 422},
+	},
+	{
+		method: http.MethodPut, path: "/api/blog/:blog_id", id: "UpdateBlog", tag: "blogs", summary: "Rename blog",
+		req: model.BlogPutReq{}, params: []param{ifMatchParam}, body: contentJSON,
+		resp: []response{success(model.BlogPutResp{})}, errors: []int{400, 404, 412},
+	},
+	{
+		method: http.MethodDelete, path: "/api/blog/:blog_id", id: "DeleteBlog", tag: "blogs", summary: "Move blog to trash",
+		params: []param{{in: "path", name: "blog_id", field: "id"}}, req: model.BlogDeleteReq{},
+		resp: []response{{status: http.StatusOK}}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodPost, path: "/api/blog/:blog_id/restore", id: "RestoreBlog", tag: "blogs", summary: "Restore blog from trash",
+		req: model.BlogRestoreReq{}, resp: []response{success(model.BlogGetResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/trash/blogs", id: "GetBlogsTrash", tag: "blogs", summary: "List deleted blogs of the caller",
+		resp: []response{success(model.BlogsTrashGetResp{})},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/tags", id: "GetBlogTags", tag: "blogs", summary: "List tags of blog posts",
+		req: model.TagsGetReq{}, resp: []response{success(model.TagsGetResp{})}, errors: []int{400},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/export", id: "ExportBlog", tag: "blogs", summary: "Export blog as tar.gz",
+		req: model.BlogExportReq{}, params: []param{query("format")},
+		resp: []response{{status: http.StatusOK, content: "application/gzip",
+			description: "Archive with blog.json and one file per post"}},
+		errors: []int{400, 404, 503},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/feed.rss", id: "GetFeedRSS", tag: "feeds", summary: "RSS 2.0 feed of the newest posts",
+		req: model.FeedGetReq{}, params: []param{query("limit")},
+		resp:   []response{{status: http.StatusOK, content: "application/rss+xml", description: "RSS 2.0 feed"}, notModified},
+		errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/feed.atom", id: "GetFeedAtom", tag: "feeds", summary: "Atom feed of the newest posts",
+		req: model.FeedGetReq{}, params: []param{query("limit")},
+		resp:   []response{{status: http.StatusOK, content: "application/atom+xml", description: "Atom feed"}, notModified},
+		errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/events", id: "StreamPostEvents", tag: "live", summary: "Stream post changes as Server-Sent Events",
+		req: model.PostEventsReq{}, params: []param{{in: "header", name: "Last-Event-ID", field: "last_event_id",
+			description: "resumes the stream after the event the client has got last"}},
+		resp: []response{{status: http.StatusOK, content: "text/event-stream", body: &Schema{Type: "string"},
+			description: "Events named after the post change, their data is JSON"}},
+		errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/posts", id: "GetPosts", tag: "posts", summary: "List posts of blog, newest first",
+		req: model.PostsGetReq{}, params: []param{query("limit"), query("cursor"), query("tag"), renderParam},
+		resp: []response{success(model.PostsGetResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/posts/:post_id", id: "GetPost", tag: "posts", summary: "Get post",
+		req: model.PostGetReq{}, params: []param{renderParam},
+		resp: []response{success(model.PostGetResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodPut, path: "/api/blog/:blog_id/posts/:post_id", id: "UpdatePost", tag: "posts", summary: "Update post",
+		req: model.PostPutReq{}, params: []param{ifMatchParam}, body: contentJSON,
+		resp: []response{success(model.PostPutResp{})}, errors: []int{400, 404, 412},
+	},
+	{
+		method: http.MethodPost, path: "/api/blog/:blog_id/posts", id: "CreatePost", tag: "posts", summary: "Create post",
+		req: model.PostPostReq{}, params: []param{idempotencyParam}, body: contentJSON,
+		resp: []response{success(model.PostPostResp{})}, errors: []int{400, 404, 409, 422},
+	},
+	{
+		method: http.MethodPost, path: "/api/blog/:blog_id/posts\\:import", id: "ImportPosts", tag: "posts", summary: "Import posts from NDJSON, one post per line",
+		req: model.PostPostReq{}, body: contentNDJSON,
+		params: []param{{in: "query", name: "mode", schema: &Schema{Type: "string", Enum: []string{"atomic", "best_effort"}, Default: "atomic"},
+			description: "atomic imports all lines or none of them, best_effort imports valid lines"}},
+		resp: []response{
+			success(model.PostsImportResp{}),
+			{status: http.StatusUnprocessableEntity, content: contentJSON, body: model.PostsImportResp{},
+				description: "Invalid lines, nothing is imported in atomic mode"},
+		},
+		errors: []int{400, 404, 413},
+	},
+	{
+		method: http.MethodDelete, path: "/api/blog/:blog_id/posts/:post_id", id: "DeletePost", tag: "posts", summary: "Move post to trash",
+		req: model.PostDeleteReq{}, resp: []response{{status: http.StatusOK}}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodPost, path: "/api/blog/:blog_id/posts/:post_id/restore", id: "RestorePost", tag: "posts", summary: "Restore post from trash",
+		req: model.PostRestoreReq{}, resp: []response{success(model.PostGetResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/trash", id: "GetPostsTrash", tag: "posts", summary: "List deleted posts of blog",
+		req: model.PostsTrashGetReq{}, resp: []response{success(model.PostsTrashGetResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/posts/:post_id/revisions", id: "GetPostRevisions", tag: "revisions", summary: "List revisions of post",
+		req: model.PostRevisionsGetReq{}, resp: []response{success(model.PostRevisionsGetResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/posts/:post_id/revisions/diff", id: "DiffPostRevisions", tag: "revisions", summary: "Diff two revisions of post",
+		req: model.PostRevisionsDiffReq{}, params: []param{query("from"), query("to")},
+		resp: []response{success(model.PostRevisionsDiffResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/posts/:post_id/revisions/:revision", id: "GetPostRevision", tag: "revisions", summary: "Get revision of post",
+		req: model.PostRevisionGetReq{}, resp: []response{success(model.PostRevisionResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodPost, path: "/api/blog/:blog_id/posts/:post_id/revisions/:revision/restore", id: "RestorePostRevision", tag: "revisions", summary: "Restore post to revision",
+		req: model.PostRevisionRestoreReq{}, resp: []response{success(model.PostPutResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/posts/:post_id/comments", id: "GetComments", tag: "comments", summary: "List comments, replies to parent_id when it is set",
+		req: model.CommentsGetReq{}, params: []param{query("parent_id"), query("limit"), query("cursor")},
+		resp: []response{success(model.CommentsGetResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodPost, path: "/api/blog/:blog_id/posts/:post_id/comments", id: "CreateComment", tag: "comments", summary: "Comment post",
+		req: model.CommentPostReq{}, body: contentJSON, resp: []response{success(model.CommentPostResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodPut, path: "/api/blog/:blog_id/posts/:post_id/comments/:comment_id", id: "UpdateComment", tag: "comments", summary: "Update comment",
+		req: model.CommentPutReq{}, body: contentJSON, resp: []response{success(model.CommentPutResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodDelete, path: "/api/blog/:blog_id/posts/:post_id/comments/:comment_id", id: "DeleteComment", tag: "comments", summary: "Delete comment",
+		req: model.CommentDeleteReq{}, resp: []response{{status: http.StatusOK}}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodPost, path: "/api/blog/:blog_id/webhooks", id: "CreateWebhook", tag: "webhooks", summary: "Subscribe URL to blog events",
+		req: model.WebhookPostReq{}, body: contentJSON, resp: []response{success(model.WebhookPostResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/webhooks", id: "GetWebhooks", tag: "webhooks", summary: "List webhooks of blog",
+		req: model.WebhooksGetReq{}, resp: []response{success(model.WebhooksGetResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodDelete, path: "/api/blog/:blog_id/webhooks/:webhook_id", id: "DeleteWebhook", tag: "webhooks", summary: "Delete webhook",
+		req: model.WebhookDeleteReq{}, resp: []response{{status: http.StatusOK}}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/blog/:blog_id/webhooks/:webhook_id/deliveries", id: "GetWebhookDeliveries", tag: "webhooks", summary: "List deliveries of webhook, newest first",
+		req: model.WebhookDeliveriesGetReq{}, params: []param{query("limit")},
+		resp: []response{success(model.WebhookDeliveriesGetResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodPost, path: "/api/blog/:blog_id/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", id: "RedeliverWebhook", tag: "webhooks", summary: "Send delivery again",
+		req: model.WebhookRedeliverReq{}, resp: []response{success(model.WebhookDeliveryResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/ws", id: "Live", tag: "live", summary: "Websocket of post events and presence",
+		resp: []response{{status: http.StatusSwitchingProtocols,
+			description: "Websocket, the client sends LiveClientMessage and gets LiveServerMessage"}},
+		errors: []int{426},
+	},
+	{
+		method: http.MethodGet, path: "/api/search", id: "SearchPosts", tag: "posts", summary: "Full text search of posts",
+		req: model.PostsSearchReq{}, params: []param{query("q"), query("blog_id"), query("limit")},
+		resp: []response{success(model.PostsSearchResp{})}, errors: []int{400},
+	},
+	{
+		method: http.MethodPost, path: "/api/graphql", id: "GraphQL", tag: "graphql", summary: "Run GraphQL operation",
+		description: "A request with a mutation is counted against the write rate limit, other requests against the read one. " +
+			"Mutations are not idempotent, Idempotency-Key is not supported and a repeated request is applied again.",
+		req: model.GraphQLReq{}, body: contentJSON,
+		resp: []response{{status: http.StatusOK, content: contentJSON, body: &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"data":   {},
+				"errors": {Type: "array", Items: &Schema{Type: "object"}},
+			},
+		}, description: "Result, errors of the operation are in the errors field"}},
+		errors: []int{400},
+	},
+	{
+		method: http.MethodGet, path: "/api/b/:blog_slug", id: "GetBlogBySlug", tag: "blogs", summary: "Get blog by slug",
+		req: model.BlogSlugGetReq{}, params: []param{{in: "path", name: "blog_slug", field: "slug"}},
+		resp: []response{success(model.BlogGetResp{}), slugMoved}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/b/:blog_slug/:post_slug", id: "GetPostBySlug", tag: "posts", summary: "Get post by slugs",
+		req: model.PostSlugGetReq{}, params: []param{renderParam},
+		resp: []response{success(model.PostGetResp{}), slugMoved}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodPost, path: "/api/users", id: "CreateUser", tag: "users", summary: "Register the caller as user",
+		description: "The user gets id of the authenticated subject. " +
+			"Registering again or with an email of another user fails with 409.",
+		req: model.UserPostReq{}, body: contentJSON, resp: []response{success(model.UserPostResp{})}, errors: []int{400, 409},
+	},
+	{
+		method: http.MethodGet, path: "/api/users/:user_id", id: "GetUser", tag: "users", summary: "Get user",
+		req: model.UserGetReq{}, resp: []response{success(model.UserGetResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodPut, path: "/api/users/:user_id", id: "UpdateUser", tag: "users", summary: "Update user",
+		req: model.UserPutReq{}, body: contentJSON, resp: []response{success(model.UserPutResp{})}, errors: []int{400, 404, 409},
+	},
+	{
+		method: http.MethodGet, path: "/api/users/:user_id/blogs", id: "GetUserBlogs", tag: "users", summary: "List blogs of user",
+		req: model.UserBlogsGetReq{}, resp: []response{success(model.UserBlogsGetResp{})}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodPost, path: "/api/keys", id: "CreateAPIKey", tag: "keys", summary: "Create API key of the caller",
+		req: model.APIKeyPostReq{}, body: contentJSON, resp: []response{success(model.APIKeyPostResp{})}, errors: []int{400},
+	},
+	{
+		method: http.MethodGet, path: "/api/keys", id: "GetAPIKeys", tag: "keys", summary: "List API keys of the caller",
+		resp: []response{success(model.APIKeysGetResp{})},
+	},
+	{
+		method: http.MethodDelete, path: "/api/keys/:key_id", id: "RevokeAPIKey", tag: "keys", summary: "Revoke API key",
+		req: model.APIKeyDeleteReq{}, resp: []response{{status: http.StatusOK}}, errors: []int{400, 404},
+	},
+	{
+		method: http.MethodGet, path: "/api/openapi.json", id: "OpenAPI", tag: "docs", summary: "This document",
+		resp: []response{success(&Schema{Type: "object"})},
+	},
+}
+
+var tags = []Tag{
+	{Name: "blogs"},
+	{Name: "posts"},
+	{Name: "revisions", Description: "Every update of a post is kept as a revision"},
+	{Name: "comments"},
+	{Name: "feeds", Description: "Cached for a short time, conditional requests are answered with 304"},
+	{Name: "live", Description: "Post changes pushed to clients"},
+	{Name: "webhooks", Description: "Signed POST requests sent on blog events, failed ones are retried"},
+	{Name: "graphql"},
+	{Name: "users"},
+	{Name: "keys", Description: "API keys with scopes read, write:posts and admin"},
+	{Name: "docs"},
+}
+
+// messages are documented in components though no route has them in body, the websocket sends them
+var messages = []any{model.LiveClientMessage{}, model.LiveServerMessage{}}
+
+// Build returns the document of routes registered in app.GetRouter
+func Build() *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "Blog API",
+			Description: "Blogs, posts and comments. Requests without credentials are anonymous, they see only published posts.",
+			Version:     "1.0.0",
+		},
+		Tags:  tags,
+		Paths: make(map[string]PathItem),
+		Components: Components{SecuritySchemes: map[string]*SecurityScheme{
+			"bearer": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			"apiKey": {Type: "apiKey", In: "header", Name: "Authorization",
+				Description: "API key sent as `ApiKey <key>`, GET requests need read scope"},
+		}},
+		// the empty requirement allows anonymous requests
+		Security: []map[string][]string{{}, {"bearer": {}}, {"apiKey": {}}},
+	}
+	s := make(schemas)
+	for _, r := range routes {
+		path := Path(r.path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(PathItem)
+		}
+		doc.Paths[path][strings.ToLower(r.method)] = s.operation(r)
+	}
+	for _, msg := range messages {
+		s.ref(reflect.TypeOf(msg))
+	}
+	doc.Components.Schemas = s
+	return doc
+}
+
+func (s schemas) operation(r route) *Operation {
+	op := &Operation{
+		OperationID: r.id,
+		Summary:     r.summary,
+		Description: r.description,
+		Tags:        []string{r.tag},
+		Responses:   make(map[string]*Response),
+	}
+
+	var req reflect.Type
+	if r.req != nil {
+		req = reflect.TypeOf(r.req)
+	}
+	params := r.params
+	for _, name := range pathParams(r.path) {
+		explicit := slices.ContainsFunc(params, func(p param) bool { return p.in == "path" && p.name == name })
+		if !explicit {
+			params = append([]param{{in: "path", name: name}}, params...)
+		}
+	}
+	// fields sent as parameters are left out of the body
+	var omit []string
+	for _, p := range params {
+		parameter := &Parameter{Name: p.name, In: p.in, Description: p.description, Schema: p.schema}
+		if parameter.Schema == nil {
+			field := p.field
+			if field == "" {
+				field = p.name
+			}
+			omit = append(omit, field)
+			parameter.Schema, parameter.Required = s.param(req, p.name, field)
+		}
+		parameter.Required = parameter.Required || p.in == "path"
+		op.Parameters = append(op.Parameters, parameter)
+	}
+	if r.body != "" {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{r.body: {Schema: s.ref(req, omit...)}},
+		}
+	}
+
+	for _, resp := range r.resp {
+		response := &Response{Description: resp.description}
+		if response.Description == "" {
+			response.Description = http.StatusText(resp.status)
+		}
+		if resp.content != "" {
+			media := &MediaType{}
+			switch body := resp.body.(type) {
+			case nil:
+			case *Schema:
+				media.Schema = body
+			default:
+				media.Schema = s.of(reflect.TypeOf(body))
+			}
+			response.Content = map[string]*MediaType{resp.content: media}
+		}
+		op.Responses[strconv.Itoa(resp.status)] = response
+	}
+	for _, status := range append(slices.Clone(r.errors), commonErrors...) {
+		op.Responses[strconv.Itoa(status)] = &Response{Description: http.StatusText(status)}
+	}
+	return op
+}
+
+// param returns schema of the parameter from the field of req, parameters without fields are
+// strings and ids are uuids
+func (s schemas) param(req reflect.Type, name string, field string) (*Schema, bool) {
+	if req != nil {
+		if schema, required, ok := s.property(req, field); ok {
+			// an omitted parameter is absent, not null
+			if types, ok := schema.Type.([]string); ok {
+				schema.Type = types[0]
+			}
+			return schema, required
+		}
+	}
+	if strings.HasSuffix(name, "_id") {
+		return &Schema{Type: "string", Format: "uuid"}, false
+	}
+	return &Schema{Type: "string"}, false
+}
+
+// Path converts fiber route path to OpenAPI path: parameters are put in braces and escaped colons are unescaped
+func Path(route string) string {
+	var b strings.Builder
+	for i := 0; i < len(route); i++ {
+		switch {
+		case route[i] == '\\' && i+1 < len(route) && route[i+1] == ':':
+			b.WriteByte(':')
+			i++
+		case route[i] == ':':
+			end := i + 1
+			for end < len(route) && isParamChar(route[end]) {
+				end++
+			}
+			b.WriteString("{" + route[i+1:end] + "}")
+			i = end - 1
+		default:
+			b.WriteByte(route[i])
+		}
+	}
+	return b.String()
+}
+
+func pathParams(route string) []string {
+	var names []string
+	path := Path(route)
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			return names
+		}
+		end := strings.IndexByte(path[start:], '}')
+		names = append(names, path[start+1:start+end])
+		path = path[start+end:]
+	}
+}
+
+func isParamChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,85 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		route string
+		want  string
+	}{
+		{"/api/blog", "/api/blog"},
+		{"/api/blog/:blog_id/posts/:post_id", "/api/blog/{blog_id}/posts/{post_id}"},
+		{"/api/blog/:blog_id/posts\\:import", "/api/blog/{blog_id}/posts:import"},
+		{"/api/blog/:blog_id/feed.rss", "/api/blog/{blog_id}/feed.rss"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			assert.Equal(t, tt.want, Path(tt.route))
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	doc := Build()
+	_, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	t.Run("body without parameters", func(t *testing.T) {
+		op := doc.Paths["/api/blog/{blog_id}/posts"]["post"]
+		require.NotNil(t, op)
+		assert.Equal(t, "#/components/schemas/PostPostReq", op.RequestBody.Content[contentJSON].Schema.Ref)
+
+		post := doc.Components.Schemas["PostPostReq"]
+		require.NotNil(t, post)
+		assert.NotContains(t, post.Properties, "blog_id")
+		assert.ElementsMatch(t, []string{"title", "text"}, post.Required)
+		assert.Equal(t, 1, *post.Properties["title"].MinLength)
+		assert.Equal(t, 64, *post.Properties["title"].MaxLength)
+		assert.Equal(t, []string{"draft", "scheduled", "published"}, post.Properties["status"].Enum)
+		assert.Equal(t, []string{"string", "null"}, post.Properties["publish_at"].Type)
+		assert.Equal(t, "date-time", post.Properties["publish_at"].Format)
+
+		tags := post.Properties["tags"]
+		assert.Equal(t, 16, *tags.MaxItems)
+		assert.Equal(t, 32, *tags.Items.MaxLength)
+	})
+
+	t.Run("parameters", func(t *testing.T) {
+		op := doc.Paths["/api/blog/{blog_id}/posts"]["get"]
+		require.NotNil(t, op)
+		params := make(map[string]*Parameter)
+		for _, p := range op.Parameters {
+			params[p.In+" "+p.Name] = p
+		}
+		require.Contains(t, params, "path blog_id")
+		assert.True(t, params["path blog_id"].Required)
+		assert.Equal(t, "uuid", params["path blog_id"].Schema.Format)
+		require.Contains(t, params, "query limit")
+		assert.False(t, params["query limit"].Required)
+		assert.Equal(t, 1, *params["query limit"].Schema.Minimum)
+		assert.Equal(t, 100, *params["query limit"].Schema.Maximum)
+		assert.Contains(t, params, "query render")
+	})
+
+	t.Run("dive into list", func(t *testing.T) {
+		key := doc.Components.Schemas["APIKeyPostReq"]
+		require.NotNil(t, key)
+		assert.Equal(t, 1, *key.Properties["scopes"].MinItems)
+		assert.Equal(t, []string{"read", "write:posts", "admin"}, key.Properties["scopes"].Items.Enum)
+	})
+
+	t.Run("responses", func(t *testing.T) {
+		op := doc.Paths["/api/blog/{blog_id}"]["put"]
+		require.NotNil(t, op)
+		assert.Equal(t, "#/components/schemas/BlogPutResp", op.Responses["200"].Content[contentJSON].Schema.Ref)
+		assert.Contains(t, op.Responses, "412")
+		assert.Contains(t, op.Responses, "429")
+		assert.NotContains(t, doc.Components.Schemas["BlogPutReq"].Properties, "Version")
+	})
+}
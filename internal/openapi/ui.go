@@ -0,0 +1,41 @@
+package openapi
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+//go:embed ui
+var ui embed.FS
+
+// UI is Swagger UI of the document served at /api/docs. The page and its initializer are ours,
+// they load /api/openapi.json; scripts and styles come from the Swagger UI bundle.
+var UI fs.FS = overlay{top: mustSub(ui, "ui"), bottom: swaggerFiles.FS}
+
+// overlay serves files of top, other files are served from bottom
+type overlay struct {
+	top    fs.FS
+	bottom fs.FS
+}
+
+func (o overlay) Open(name string) (fs.File, error) {
+	f, err := o.top.Open(name)
+	if errors.Is(err, fs.ErrNotExist) || name == "." {
+		if f != nil {
+			f.Close()
+		}
+		return o.bottom.Open(name)
+	}
+	return f, err
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	a := assert.New(t)
+	now := time.Now()
+	l := New(1, 2)
+	l.now = func() time.Time { return now }
+
+	res := l.Allow("a")
+	a.True(res.Allowed)
+	a.Equal(2, res.Limit)
+	a.Equal(1, res.Remaining)
+	a.Equal(time.Second, res.Reset)
+
+	a.True(l.Allow("a").Allowed)
+	res = l.Allow("a")
+	a.False(res.Allowed)
+	a.Equal(0, res.Remaining)
+	a.Equal(time.Second, res.RetryAfter)
+
+	// other clients have their own bucket
+	a.True(l.Allow("b").Allowed)
+
+	now = now.Add(time.Second)
+	a.True(l.Allow("a").Allowed)
+	a.False(l.Allow("a").Allowed)
+
+	now = now.Add(time.Minute)
+	l.DeleteFull()
+	a.Empty(l.buckets)
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+type APIKeyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAPIKeyRepo(conn *pgxpool.Pool) *APIKeyRepo {
+	return &APIKeyRepo{
+		db: conn,
+	}
+}
+
+func (r *APIKeyRepo) AddAPIKey(ctx context.Context, key model.DbAPIKey) (uuid.UUID, error) {
+	_, err := r.db.Exec(ctx, `INSERT INTO api_keys(id, users_id, name, prefix, salt, hash, scopes, created_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8)`,
+		key.ID,
+		key.UserID,
+		key.Name,
+		key.Prefix,
+		key.Salt,
+		key.Hash,
+		key.Scopes,
+		key.CreatedAt,
+	)
+	if err != nil {
+		// owner is not registered
+		if hasPgCode(err, foreignKeyViolation) {
+			return uuid.Nil, apperror.ErrNotRegistered
+		}
+		return uuid.Nil, errors.Wrap(err, "repository.APIKeyRepo.AddAPIKey")
+	}
+	return key.ID, nil
+}
+
+func (r *APIKeyRepo) GetAPIKeys(ctx context.Context, userID uuid.UUID) ([]model.DbAPIKey, error) {
+	query := `SELECT id, users_id, name, prefix, salt, hash, scopes, created_at, revoked_at FROM api_keys
+		WHERE users_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC, id DESC`
+	var keys []model.DbAPIKey
+	if err := pgxscan.Select(ctx, r.db, &keys, query, userID); err != nil {
+		return nil, errors.Wrap(err, "repository.APIKeyRepo.GetAPIKeys")
+	}
+	return keys, nil
+}
+
+// GetAPIKeyByPrefix returns active key, revoked keys are not found
+func (r *APIKeyRepo) GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.DbAPIKey, error) {
+	query := `SELECT id, users_id, name, prefix, salt, hash, scopes, created_at, revoked_at FROM api_keys
+		WHERE prefix = $1 AND revoked_at IS NULL`
+	var key model.DbAPIKey
+	if err := pgxscan.Get(ctx, r.db, &key, query, prefix); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DbAPIKey{}, apperror.ErrNotFound
+		}
+		return model.DbAPIKey{}, errors.Wrap(err, "repository.APIKeyRepo.GetAPIKeyByPrefix")
+	}
+	return key, nil
+}
+
+func (r *APIKeyRepo) RevokeAPIKey(ctx context.Context, keyID uuid.UUID, userID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, "UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND users_id = $2 AND revoked_at IS NULL", keyID, userID)
+	if err != nil {
+		return errors.Wrap(err, "repository.APIKeyRepo.RevokeAPIKey")
+	}
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
@@ -2,6 +2,10 @@ package repository
 
 import (
 	"context"
+	"html"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Rolan335/project/internal/apperror"
 	"github.com/Rolan335/project/internal/model"
@@ -29,7 +33,7 @@ func (r *BlogRepo) GetBlog(ctx context.Context, blogID uuid.UUID) (model.DbBlog,
 	defer span.End()
 
 	var blog model.DbBlog
-	if err := pgxscan.Get(ctx, r.db, &blog, "SELECT id, users_id, name, created_at FROM blogs WHERE id = $1", blogID); err != nil {
+	if err := pgxscan.Get(ctx, r.db, &blog, "SELECT id, users_id, name, slug, created_at, version FROM blogs WHERE id = $1 AND deleted_at IS NULL", blogID); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return model.DbBlog{}, apperror.ErrNotFound
 		}
@@ -38,64 +42,136 @@ func (r *BlogRepo) GetBlog(ctx context.Context, blogID uuid.UUID) (model.DbBlog,
 	return blog, nil
 }
 
-func (r *BlogRepo) AddBlog(ctx context.Context, blog model.DbBlog) (uuid.UUID, error) {
+// GetBlogBySlug finds blog by its current or old slug, caller compares slugs to tell them apart
+func (r *BlogRepo) GetBlogBySlug(ctx context.Context, slug string) (model.DbBlog, error) {
+	query := `SELECT id, users_id, name, slug, created_at, version FROM blogs
+		WHERE deleted_at IS NULL AND id = (
+			SELECT id FROM blogs WHERE slug = $1
+			UNION ALL SELECT blogs_id FROM blog_slug_aliases WHERE slug = $1
+			LIMIT 1)`
+	var blog model.DbBlog
+	if err := pgxscan.Get(ctx, r.db, &blog, query, slug); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DbBlog{}, apperror.ErrNotFound
+		}
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.GetBlogBySlug")
+	}
+	return blog, nil
+}
+
+// GetBlogOwner returns owner of the blog, blogs in trash included so that owner can restore them
+func (r *BlogRepo) GetBlogOwner(ctx context.Context, blogID uuid.UUID) (uuid.UUID, error) {
+	var userID uuid.UUID
+	if err := r.db.QueryRow(ctx, "SELECT users_id FROM blogs WHERE id = $1", blogID).Scan(&userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, apperror.ErrNotFound
+		}
+		return uuid.Nil, errors.Wrap(err, "blogprovider.BlogRepo.GetBlogOwner")
+	}
+	return userID, nil
+}
+
+// AddBlog stores the blog with a slug made from its name and returns the stored blog
+func (r *BlogRepo) AddBlog(ctx context.Context, blog model.DbBlog) (model.DbBlog, error) {
 	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return uuid.Nil, errors.Wrap(err, "blogprovider.BlogRepo.AddBlog")
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.AddBlog")
 	}
-	// В идеале потом перекинуть это в отдельный метод для реги юзера
-	UserID := blog.UserID
-	if _, err := tx.Exec(ctx, "INSERT INTO users(id) values($1) ON CONFLICT (id) DO NOTHING", UserID); err != nil {
+	blog.Slug, err = newBlogSlug(ctx, tx, blog.ID, blog.Name)
+	if err != nil {
 		tx.Rollback(ctx)
-		return uuid.Nil, errors.Wrap(err, "blogprovider.BlogRepo.AddBlog")
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.AddBlog")
 	}
-
-	blogID := blog.ID
-	_, err = tx.Exec(ctx, "INSERT INTO blogs(id, users_id, name, created_at) values($1, $2, $3, $4)",
+	// user must be registered with UserRepo.AddUser
+	_, err = tx.Exec(ctx, "INSERT INTO blogs(id, users_id, name, slug, created_at, version) values($1, $2, $3, $4, $5, $6)",
 		blog.ID,
 		blog.UserID,
 		blog.Name,
+		blog.Slug,
 		blog.CreatedAt,
+		blog.Version,
 	)
 	if err != nil {
 		tx.Rollback(ctx)
-		return uuid.Nil, errors.Wrap(err, "blogprovider.BlogRepo.AddBlog")
+		if hasPgCode(err, foreignKeyViolation) {
+			return model.DbBlog{}, apperror.ErrNotRegistered
+		}
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.AddBlog")
+	}
+	if err := insertEvent(ctx, tx, model.EventBlogCreated, blog.ID, blog); err != nil {
+		tx.Rollback(ctx)
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.AddBlog")
 	}
 
 	if err := tx.Commit(ctx); err != nil {
 		tx.Rollback(ctx)
-		return uuid.Nil, errors.Wrap(err, "blogprovider.BlogRepo.AddBlog")
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.AddBlog")
 	}
-	return blogID, nil
+	return blog, nil
 }
 
+// UpdateBlog updates blog if blog.Version matches the stored one, zero version skips the check.
+// Renamed blog gets a new slug, the old one stays as an alias.
 func (r *BlogRepo) UpdateBlog(ctx context.Context, blog model.DbBlog) (model.DbBlog, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.UpdateBlog")
+	}
+	defer tx.Rollback(ctx)
 	var blogRes model.DbBlog
-	query := "UPDATE blogs SET users_id = $1, name = $2 WHERE id = $3 RETURNING id, users_id, name, created_at"
-	if err := pgxscan.Get(ctx, r.db, &blogRes, query, blog.UserID, blog.Name, blog.ID); err != nil {
+	query := `UPDATE blogs SET name = $1, version = version + 1
+		WHERE id = $2 AND deleted_at IS NULL AND ($3 = 0 OR version = $3)
+		RETURNING id, users_id, name, slug, created_at, version`
+	if err := pgxscan.Get(ctx, tx, &blogRes, query, blog.Name, blog.ID, blog.Version); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return model.DbBlog{}, apperror.ErrNotFound
+			return model.DbBlog{}, r.versionMismatchOrNotFound(ctx, "SELECT 1 FROM blogs WHERE id = $1 AND deleted_at IS NULL", blog.ID)
 		}
 		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.UpdateBlog")
 	}
+	if err := renameBlogSlug(ctx, tx, &blogRes); err != nil {
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.UpdateBlog")
+	}
+	if err := insertEvent(ctx, tx, model.EventBlogUpdated, blogRes.ID, blogRes); err != nil {
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.UpdateBlog")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.UpdateBlog")
+	}
 	return blogRes, nil
 }
 
+// versionMismatchOrNotFound tells why conditional update did not touch any row
+func (r *BlogRepo) versionMismatchOrNotFound(ctx context.Context, existsQuery string, args ...any) error {
+	if err := r.db.QueryRow(ctx, existsQuery, args...).Scan(nil); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.ErrNotFound
+		}
+		return errors.Wrap(err, "blogprovider.BlogRepo.versionMismatchOrNotFound")
+	}
+	return apperror.ErrVersionMismatch
+}
+
+// DeleteBlog moves blog and its posts to trash. Posts get the same deleted_at as the blog,
+// so RestoreBlog can tell them apart from posts deleted earlier one by one.
 func (r *BlogRepo) DeleteBlog(ctx context.Context, blogID uuid.UUID) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return errors.Wrap(err, "blogprovider.BlogRepo.DeleteBlog")
 	}
 	defer tx.Rollback(ctx)
-	cmdTag, err := tx.Exec(ctx, "DELETE FROM blogs WHERE id = $1", blogID)
+	// now() is the same for the whole transaction
+	cmdTag, err := tx.Exec(ctx, "UPDATE blogs SET deleted_at = now(), version = version + 1 WHERE id = $1 AND deleted_at IS NULL", blogID)
 	if err != nil {
 		return errors.Wrap(err, "blogprovider.BlogRepo.DeleteBlog")
 	}
 	if cmdTag.RowsAffected() == 0 {
 		return apperror.ErrNotFound
 	}
-	// deleting all posts in deleted blog
-	if _, err := tx.Exec(ctx, "DELETE FROM posts WHERE blogs_id = $1", blogID); err != nil {
+	if _, err := tx.Exec(ctx, "UPDATE posts SET deleted_at = now(), version = version + 1 WHERE blogs_id = $1 AND deleted_at IS NULL", blogID); err != nil {
+		return errors.Wrap(err, "blogprovider.BlogRepo.DeleteBlog")
+	}
+	// posts deleted together with the blog are covered by the blog event
+	if err := insertEvent(ctx, tx, model.EventBlogDeleted, blogID, model.DeletedPayload{ID: blogID, BlogID: blogID}); err != nil {
 		return errors.Wrap(err, "blogprovider.BlogRepo.DeleteBlog")
 	}
 	if err := tx.Commit(ctx); err != nil {
@@ -104,9 +180,73 @@ func (r *BlogRepo) DeleteBlog(ctx context.Context, blogID uuid.UUID) error {
 	return nil
 }
 
+func (r *BlogRepo) GetDeletedBlogs(ctx context.Context, userID uuid.UUID) ([]model.DbBlog, error) {
+	query := `SELECT id, users_id, name, slug, created_at, deleted_at, version FROM blogs
+		WHERE users_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC`
+	var blogs []model.DbBlog
+	if err := pgxscan.Select(ctx, r.db, &blogs, query, userID); err != nil {
+		return nil, errors.Wrap(err, "blogprovider.BlogRepo.GetDeletedBlogs")
+	}
+	return blogs, nil
+}
+
+func (r *BlogRepo) RestoreBlog(ctx context.Context, blogID uuid.UUID) (model.DbBlog, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.RestoreBlog")
+	}
+	defer tx.Rollback(ctx)
+	var deletedAt time.Time
+	if err := tx.QueryRow(ctx, "SELECT deleted_at FROM blogs WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE", blogID).Scan(&deletedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DbBlog{}, apperror.ErrNotFound
+		}
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.RestoreBlog")
+	}
+	var blog model.DbBlog
+	query := "UPDATE blogs SET deleted_at = NULL, version = version + 1 WHERE id = $1 RETURNING id, users_id, name, slug, created_at, version"
+	if err := pgxscan.Get(ctx, tx, &blog, query, blogID); err != nil {
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.RestoreBlog")
+	}
+	// restoring only posts deleted together with the blog
+	if _, err := tx.Exec(ctx, "UPDATE posts SET deleted_at = NULL, version = version + 1 WHERE blogs_id = $1 AND deleted_at = $2", blogID, deletedAt); err != nil {
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.RestoreBlog")
+	}
+	if err := insertEvent(ctx, tx, model.EventBlogRestored, blog.ID, blog); err != nil {
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.RestoreBlog")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return model.DbBlog{}, errors.Wrap(err, "blogprovider.BlogRepo.RestoreBlog")
+	}
+	return blog, nil
+}
+
+// PurgeDeleted removes blogs and posts that have been in trash longer than olderThan
+func (r *BlogRepo) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "blogprovider.BlogRepo.PurgeDeleted")
+	}
+	defer tx.Rollback(ctx)
+	postsTag, err := tx.Exec(ctx, `DELETE FROM posts WHERE deleted_at < now() - $1::interval
+		OR blogs_id IN (SELECT id FROM blogs WHERE deleted_at < now() - $1::interval)`, olderThan)
+	if err != nil {
+		return 0, errors.Wrap(err, "blogprovider.BlogRepo.PurgeDeleted")
+	}
+	blogsTag, err := tx.Exec(ctx, "DELETE FROM blogs WHERE deleted_at < now() - $1::interval", olderThan)
+	if err != nil {
+		return 0, errors.Wrap(err, "blogprovider.BlogRepo.PurgeDeleted")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, errors.Wrap(err, "blogprovider.BlogRepo.PurgeDeleted")
+	}
+	return postsTag.RowsAffected() + blogsTag.RowsAffected(), nil
+}
+
 func (r *BlogRepo) GetPost(ctx context.Context, postID uuid.UUID) (model.DbPost, error) {
 	var post model.DbPost
-	if err := pgxscan.Get(ctx, r.db, &post, "SELECT id, blogs_id, title, text, created_at FROM posts WHERE id = $1", postID); err != nil {
+	if err := pgxscan.Get(ctx, r.db, &post, "SELECT id, blogs_id, title, text, slug, status, publish_at, created_at, version, "+postTagsColumn+", "+postCommentsCountColumn+" FROM posts WHERE id = $1 AND deleted_at IS NULL", postID); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return model.DbPost{}, apperror.ErrNotFound
 		}
@@ -115,56 +255,349 @@ func (r *BlogRepo) GetPost(ctx context.Context, postID uuid.UUID) (model.DbPost,
 	return post, nil
 }
 
-func (r *BlogRepo) GetPosts(ctx context.Context, blogID uuid.UUID) ([]model.DbPost, error) {
+// GetPostBySlug finds post of the blog by its current or old slug, caller compares slugs to tell them apart
+func (r *BlogRepo) GetPostBySlug(ctx context.Context, blogID uuid.UUID, slug string) (model.DbPost, error) {
+	query := `SELECT id, blogs_id, title, text, slug, status, publish_at, created_at, version, ` + postTagsColumn + `, ` + postCommentsCountColumn + ` FROM posts
+		WHERE blogs_id = $1 AND deleted_at IS NULL AND id = (
+			SELECT id FROM posts WHERE blogs_id = $1 AND slug = $2
+			UNION ALL SELECT posts_id FROM post_slug_aliases WHERE blogs_id = $1 AND slug = $2
+			LIMIT 1)`
+	var post model.DbPost
+	if err := pgxscan.Get(ctx, r.db, &post, query, blogID, slug); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DbPost{}, apperror.ErrNotFound
+		}
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.GetPostBySlug")
+	}
+	return post, nil
+}
+
+func (r *BlogRepo) GetPosts(ctx context.Context, filter model.DbPostsFilter) ([]model.DbPost, error) {
+	var afterCreatedAt *time.Time
+	var afterID uuid.UUID
+	if filter.After != nil {
+		afterCreatedAt = &filter.After.CreatedAt
+		afterID = filter.After.ID
+	}
+	// keyset pagination, (created_at, id) is unique so the order is stable between pages
+	query := `SELECT id, blogs_id, title, text, slug, status, publish_at, created_at, version, ` + postTagsColumn + `, ` + postCommentsCountColumn + ` FROM posts
+		WHERE blogs_id = $1 AND deleted_at IS NULL
+			AND ($2::timestamp IS NULL OR (created_at, id) < ($2, $3))
+			AND (NOT $6 OR status = 'published')
+			AND ($5::text = '' OR EXISTS (SELECT 1 FROM post_tags pt JOIN tags t ON t.id = pt.tags_id
+				WHERE pt.posts_id = posts.id AND t.name = $5))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4`
 	var posts []model.DbPost
-	if err := pgxscan.Select(ctx, r.db, &posts, "SELECT id, blogs_id, title, text, created_at FROM posts WHERE blogs_id = $1", blogID); err != nil {
+	if err := pgxscan.Select(ctx, r.db, &posts, query, filter.BlogID, afterCreatedAt, afterID, filter.Limit, filter.Tag, filter.OnlyPublished); err != nil {
 		return nil, errors.Wrap(err, "blogprovider.BlogRepo.GetPosts")
 	}
 	return posts, nil
 }
 
-func (r *BlogRepo) AddPost(ctx context.Context, post model.DbPost) (uuid.UUID, error) {
-	if err := r.db.QueryRow(ctx, "SELECT id FROM blogs WHERE id = $1", post.BlogID).Scan(nil); err != nil {
+// GetBlogsPosts returns the first filter.Limit posts of each of the blogs, newest first within a blog
+func (r *BlogRepo) GetBlogsPosts(ctx context.Context, filter model.DbBlogsPostsFilter) ([]model.DbPost, error) {
+	query := `SELECT id, blogs_id, title, text, slug, status, publish_at, created_at, version, tags, comments_count FROM (
+			SELECT id, blogs_id, title, text, slug, status, publish_at, created_at, version, ` + postTagsColumn + `, ` + postCommentsCountColumn + `,
+				row_number() OVER (PARTITION BY blogs_id ORDER BY created_at DESC, id DESC) AS n
+			FROM posts
+			WHERE blogs_id = ANY($1) AND deleted_at IS NULL
+				AND (status = 'published' OR blogs_id IN (SELECT id FROM blogs WHERE users_id = $2))
+				AND ($4::text = '' OR EXISTS (SELECT 1 FROM post_tags pt JOIN tags t ON t.id = pt.tags_id
+					WHERE pt.posts_id = posts.id AND t.name = $4))
+		) p
+		WHERE n <= $3
+		ORDER BY blogs_id, created_at DESC, id DESC`
+	var posts []model.DbPost
+	if err := pgxscan.Select(ctx, r.db, &posts, query, filter.BlogIDs, filter.ViewerID, filter.Limit, filter.Tag); err != nil {
+		return nil, errors.Wrap(err, "blogprovider.BlogRepo.GetBlogsPosts")
+	}
+	return posts, nil
+}
+
+// snippetStart and snippetStop delimit matches in ts_headline of raw text. They are private use
+// characters removed from the text beforehand, so the snippet is escaped as a whole and only then
+// they are turned into <mark>: matches inside an escaped entity can't break it.
+const (
+	snippetStart = "\uE000"
+	snippetStop  = "\uE001"
+)
+
+var snippetMarks = strings.NewReplacer(snippetStart, "<mark>", snippetStop, "</mark>")
+
+func markSnippet(snippet string) string {
+	return snippetMarks.Replace(html.EscapeString(snippet))
+}
+
+// SearchPosts returns published posts matching the query, snippet is HTML where only <mark> is markup
+func (r *BlogRepo) SearchPosts(ctx context.Context, search model.DbPostsSearch) ([]model.DbPostSearchResult, error) {
+	query := `SELECT id, blogs_id, title, slug, created_at,
+			ts_rank(search, q) AS rank,
+			ts_headline('simple', translate("text", '` + snippetStart + snippetStop + `', ''), q,
+				'StartSel=` + snippetStart + `, StopSel=` + snippetStop + `, MaxFragments=2, MaxWords=30, MinWords=10') AS snippet
+		FROM posts, websearch_to_tsquery('simple', $1) q
+		WHERE search @@ q AND deleted_at IS NULL AND status = 'published' AND ($2::uuid IS NULL OR blogs_id = $2)
+		ORDER BY rank DESC, created_at DESC, id DESC
+		LIMIT $3`
+	var res []model.DbPostSearchResult
+	if err := pgxscan.Select(ctx, r.db, &res, query, search.Query, search.BlogID, search.Limit); err != nil {
+		return nil, errors.Wrap(err, "blogprovider.BlogRepo.SearchPosts")
+	}
+	for i := 0; i < len(res); i++ {
+		res[i].Snippet = markSnippet(res[i].Snippet)
+	}
+	return res, nil
+}
+
+// insertRevisionQuery saves title and text as the next revision of the post
+const insertRevisionQuery = `INSERT INTO post_revisions(posts_id, revision, title, text, created_at)
+	SELECT $1, coalesce(max(revision), 0) + 1, $2, $3, now() FROM post_revisions WHERE posts_id = $1`
+
+// AddPost stores the post with a slug made from its title and returns the stored post
+func (r *BlogRepo) AddPost(ctx context.Context, post model.DbPost) (model.DbPost, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.AddPost")
+	}
+	defer tx.Rollback(ctx)
+	if err := tx.QueryRow(ctx, "SELECT id FROM blogs WHERE id = $1 AND deleted_at IS NULL", post.BlogID).Scan(nil); err != nil {
 		if err == pgx.ErrNoRows {
-			return uuid.Nil, apperror.ErrNotFound
+			return model.DbPost{}, apperror.ErrNotFound
 		}
-		return uuid.Nil, errors.Wrap(err, "blogprovider.BlogRepo.AddPost")
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.AddPost")
 	}
-	_, err := r.db.Exec(ctx, "INSERT INTO posts(id, blogs_id, title, text, created_at) VALUES($1, $2, $3, $4, $5)",
+	post.Slug, err = newPostSlug(ctx, tx, post.BlogID, post.ID, post.Title)
+	if err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.AddPost")
+	}
+	_, err = tx.Exec(ctx, "INSERT INTO posts(id, blogs_id, title, text, slug, status, publish_at, created_at, version) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)",
 		post.ID,
 		post.BlogID,
 		post.Title,
 		post.Text,
+		post.Slug,
+		post.Status,
+		post.PublishAt,
 		post.CreatedAt,
+		post.Version,
 	)
 	if err != nil {
-		return uuid.Nil, errors.Wrap(err, "blogprovider.BlogRepo.AddPost")
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.AddPost")
+	}
+	if _, err := tx.Exec(ctx, insertRevisionQuery, post.ID, post.Title, post.Text); err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.AddPost")
+	}
+	if err := setPostTags(ctx, tx, post.ID, post.Tags); err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.AddPost")
+	}
+	if err := insertEvent(ctx, tx, model.EventPostCreated, post.ID, post); err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.AddPost")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.AddPost")
 	}
 
-	return post.ID, nil
+	return post, nil
 }
 
+// UpdatePost updates post if post.Version matches the stored one, zero version skips the check.
+// Post with a new title gets a new slug, the old one stays as an alias.
 func (r *BlogRepo) UpdatePost(ctx context.Context, post model.DbPost) (model.DbPost, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.UpdatePost")
+	}
+	defer tx.Rollback(ctx)
 	var postRes model.DbPost
-	// Обновляем только title и text
-	query := "UPDATE posts SET title = $1, text = $2 WHERE id = $3 AND blogs_id = $4 RETURNING id, blogs_id, title, text, created_at"
-	err := pgxscan.Get(ctx, r.db, &postRes, query, post.Title, post.Text, post.ID, post.BlogID)
+	// Обновляем title и text, статус меняется только если он задан.
+	// Повторная публикация не сдвигает время публикации.
+	query := `UPDATE posts SET title = $1, text = $2, version = version + 1,
+			status = CASE WHEN $6 = '' THEN status ELSE $6 END,
+			publish_at = CASE WHEN $6 = '' OR ($6 = 'published' AND status = 'published') THEN publish_at ELSE $7 END
+		WHERE id = $3 AND blogs_id = $4 AND deleted_at IS NULL AND ($5 = 0 OR version = $5)
+		RETURNING id, blogs_id, title, text, slug, status, publish_at, created_at, version, ` + postCommentsCountColumn
+	err = pgxscan.Get(ctx, tx, &postRes, query, post.Title, post.Text, post.ID, post.BlogID, post.Version, post.Status, post.PublishAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return model.DbPost{}, apperror.ErrNotFound
+			return model.DbPost{}, r.versionMismatchOrNotFound(ctx,
+				"SELECT 1 FROM posts WHERE id = $1 AND blogs_id = $2 AND deleted_at IS NULL", post.ID, post.BlogID)
+		}
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.UpdatePost")
+	}
+	// строка поста заблокирована UPDATE, поэтому номер ревизии не задублируется
+	if _, err := tx.Exec(ctx, insertRevisionQuery, postRes.ID, postRes.Title, postRes.Text); err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.UpdatePost")
+	}
+	if err := renamePostSlug(ctx, tx, &postRes); err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.UpdatePost")
+	}
+	// nil tags are left as is
+	if post.Tags != nil {
+		if err := setPostTags(ctx, tx, postRes.ID, post.Tags); err != nil {
+			return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.UpdatePost")
 		}
+	}
+	if postRes.Tags, err = getPostTags(ctx, tx, postRes.ID); err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.UpdatePost")
+	}
+	if err := insertEvent(ctx, tx, model.EventPostUpdated, postRes.ID, postRes); err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.UpdatePost")
+	}
+	if err := tx.Commit(ctx); err != nil {
 		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.UpdatePost")
 	}
 	return postRes, nil
 }
 
+// PublishScheduled publishes at most limit scheduled posts which are due and returns them.
+// Rows locked by another replica are skipped, so publishers running in parallel never take the same post.
+func (r *BlogRepo) PublishScheduled(ctx context.Context, limit int) ([]model.DbPost, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "blogprovider.BlogRepo.PublishScheduled")
+	}
+	defer tx.Rollback(ctx)
+	query := `UPDATE posts SET status = 'published', version = version + 1
+		WHERE id IN (
+			SELECT id FROM posts
+			WHERE status = 'scheduled' AND publish_at <= now() AND deleted_at IS NULL
+			ORDER BY publish_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING id, blogs_id, title, text, slug, status, publish_at, created_at, version, ` + postTagsColumn
+	var posts []model.DbPost
+	if err := pgxscan.Select(ctx, tx, &posts, query, limit); err != nil {
+		return nil, errors.Wrap(err, "blogprovider.BlogRepo.PublishScheduled")
+	}
+	if len(posts) == 0 {
+		return nil, nil
+	}
+	if err := insertPostEvents(ctx, tx, model.EventPostPublished, posts); err != nil {
+		return nil, errors.Wrap(err, "blogprovider.BlogRepo.PublishScheduled")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, errors.Wrap(err, "blogprovider.BlogRepo.PublishScheduled")
+	}
+	return posts, nil
+}
+
 func (r *BlogRepo) DeletePost(ctx context.Context, postID uuid.UUID, blogID uuid.UUID) error {
-	cmdTag, err := r.db.Exec(ctx, "DELETE FROM posts WHERE id = $1 AND blogs_id = $2", postID, blogID)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "blogprovider.BlogRepo.DeletePost")
+	}
+	defer tx.Rollback(ctx)
+	cmdTag, err := tx.Exec(ctx, "UPDATE posts SET deleted_at = now(), version = version + 1 WHERE id = $1 AND blogs_id = $2 AND deleted_at IS NULL", postID, blogID)
 	if err != nil {
 		return errors.Wrap(err, "blogprovider.BlogRepo.DeletePost")
 	}
 	if cmdTag.RowsAffected() == 0 {
 		return apperror.ErrNotFound
 	}
+	if err := insertEvent(ctx, tx, model.EventPostDeleted, postID, model.DeletedPayload{ID: postID, BlogID: blogID}); err != nil {
+		return errors.Wrap(err, "blogprovider.BlogRepo.DeletePost")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "blogprovider.BlogRepo.DeletePost")
+	}
 	return nil
 }
+
+func (r *BlogRepo) GetDeletedPosts(ctx context.Context, blogID uuid.UUID) ([]model.DbPost, error) {
+	query := `SELECT id, blogs_id, title, text, slug, status, publish_at, created_at, deleted_at, version, ` + postTagsColumn + ` FROM posts
+		WHERE blogs_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC`
+	var posts []model.DbPost
+	if err := pgxscan.Select(ctx, r.db, &posts, query, blogID); err != nil {
+		return nil, errors.Wrap(err, "blogprovider.BlogRepo.GetDeletedPosts")
+	}
+	return posts, nil
+}
+
+// RestorePost restores post only if its blog is not in trash
+func (r *BlogRepo) RestorePost(ctx context.Context, postID uuid.UUID, blogID uuid.UUID) (model.DbPost, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.RestorePost")
+	}
+	defer tx.Rollback(ctx)
+	var post model.DbPost
+	query := `UPDATE posts SET deleted_at = NULL, version = version + 1
+		WHERE id = $1 AND blogs_id = $2 AND deleted_at IS NOT NULL
+			AND EXISTS (SELECT 1 FROM blogs WHERE id = $2 AND deleted_at IS NULL)
+		RETURNING id, blogs_id, title, text, slug, status, publish_at, created_at, version, ` + postTagsColumn + `, ` + postCommentsCountColumn
+	if err := pgxscan.Get(ctx, tx, &post, query, postID, blogID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DbPost{}, apperror.ErrNotFound
+		}
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.RestorePost")
+	}
+	if err := insertEvent(ctx, tx, model.EventPostRestored, post.ID, post); err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.RestorePost")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return model.DbPost{}, errors.Wrap(err, "blogprovider.BlogRepo.RestorePost")
+	}
+	return post, nil
+}
+
+func (r *BlogRepo) GetPostRevisions(ctx context.Context, postID uuid.UUID, blogID uuid.UUID) ([]model.DbPostRevision, error) {
+	query := `SELECT pr.posts_id, pr.revision, pr.title, pr.created_at FROM post_revisions pr
+		JOIN posts p ON p.id = pr.posts_id
+		WHERE pr.posts_id = $1 AND p.blogs_id = $2 AND p.deleted_at IS NULL
+		ORDER BY pr.revision DESC`
+	var revisions []model.DbPostRevision
+	if err := pgxscan.Select(ctx, r.db, &revisions, query, postID, blogID); err != nil {
+		return nil, errors.Wrap(err, "blogprovider.BlogRepo.GetPostRevisions")
+	}
+	if len(revisions) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+	return revisions, nil
+}
+
+func (r *BlogRepo) GetPostRevision(ctx context.Context, postID uuid.UUID, blogID uuid.UUID, revision int) (model.DbPostRevision, error) {
+	query := `SELECT pr.posts_id, pr.revision, pr.title, pr.text, pr.created_at FROM post_revisions pr
+		JOIN posts p ON p.id = pr.posts_id
+		WHERE pr.posts_id = $1 AND p.blogs_id = $2 AND p.deleted_at IS NULL AND pr.revision = $3`
+	var rev model.DbPostRevision
+	if err := pgxscan.Get(ctx, r.db, &rev, query, postID, blogID, revision); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DbPostRevision{}, apperror.ErrNotFound
+		}
+		return model.DbPostRevision{}, errors.Wrap(err, "blogprovider.BlogRepo.GetPostRevision")
+	}
+	return rev, nil
+}
+
+// exportFetchSize is a number of rows fetched from export cursor at once
+const exportFetchSize = 100
+
+// StreamPosts calls fn for every post of the blog in creation order. Posts are read through a server side
+// cursor, so memory does not depend on the blog size.
+func (r *BlogRepo) StreamPosts(ctx context.Context, blogID uuid.UUID, fn func(model.DbPost) error) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return errors.Wrap(err, "blogprovider.BlogRepo.StreamPosts")
+	}
+	defer tx.Rollback(ctx)
+	query := "DECLARE export_posts NO SCROLL CURSOR FOR SELECT id, blogs_id, title, text, slug, status, publish_at, created_at, version, " + postTagsColumn + `
+		FROM posts WHERE blogs_id = $1 AND deleted_at IS NULL ORDER BY created_at, id`
+	if _, err := tx.Exec(ctx, query, blogID); err != nil {
+		return errors.Wrap(err, "blogprovider.BlogRepo.StreamPosts")
+	}
+	for {
+		var posts []model.DbPost
+		if err := pgxscan.Select(ctx, tx, &posts, "FETCH "+strconv.Itoa(exportFetchSize)+" FROM export_posts"); err != nil {
+			return errors.Wrap(err, "blogprovider.BlogRepo.StreamPosts")
+		}
+		for i := 0; i < len(posts); i++ {
+			if err := fn(posts[i]); err != nil {
+				return err
+			}
+		}
+		if len(posts) < exportFetchSize {
+			return nil
+		}
+	}
+}
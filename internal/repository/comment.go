@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// postCommentsCountColumn selects number of comments of the post, query must select from posts without alias.
+// Cached posts keep the count they were read with until cache ttl expires.
+const postCommentsCountColumn = `(SELECT count(*) FROM comments c WHERE c.posts_id = posts.id) AS comments_count`
+
+type CommentRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewCommentRepo(conn *pgxpool.Pool) *CommentRepo {
+	return &CommentRepo{
+		db: conn,
+	}
+}
+
+func (r *CommentRepo) AddComment(ctx context.Context, blogID uuid.UUID, comment model.DbComment) (uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, errors.Wrap(err, "repository.CommentRepo.AddComment")
+	}
+	defer tx.Rollback(ctx)
+	// FOR SHARE keeps the post from being deleted until the comment is inserted
+	err = tx.QueryRow(ctx, "SELECT id FROM posts WHERE id = $1 AND blogs_id = $2 AND deleted_at IS NULL FOR SHARE",
+		comment.PostID, blogID).Scan(nil)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, apperror.ErrNotFound
+		}
+		return uuid.Nil, errors.Wrap(err, "repository.CommentRepo.AddComment")
+	}
+	// reply must be in the same post as its parent
+	if comment.ParentID != nil {
+		err := tx.QueryRow(ctx, "SELECT id FROM comments WHERE id = $1 AND posts_id = $2", comment.ParentID, comment.PostID).Scan(nil)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return uuid.Nil, apperror.ErrNotFound
+			}
+			return uuid.Nil, errors.Wrap(err, "repository.CommentRepo.AddComment")
+		}
+	}
+	_, err = tx.Exec(ctx, "INSERT INTO comments(id, posts_id, parent_id, users_id, text, created_at) VALUES($1, $2, $3, $4, $5, $6)",
+		comment.ID,
+		comment.PostID,
+		comment.ParentID,
+		comment.UserID,
+		comment.Text,
+		comment.CreatedAt,
+	)
+	if err != nil {
+		// author is not registered
+		if hasPgCode(err, foreignKeyViolation) {
+			return uuid.Nil, apperror.ErrNotRegistered
+		}
+		return uuid.Nil, errors.Wrap(err, "repository.CommentRepo.AddComment")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, errors.Wrap(err, "repository.CommentRepo.AddComment")
+	}
+	return comment.ID, nil
+}
+
+// GetComments returns one level of the thread ordered from oldest to newest
+func (r *CommentRepo) GetComments(ctx context.Context, filter model.DbCommentsFilter) ([]model.DbComment, error) {
+	var afterCreatedAt *time.Time
+	var afterID uuid.UUID
+	if filter.After != nil {
+		afterCreatedAt = &filter.After.CreatedAt
+		afterID = filter.After.ID
+	}
+	query := `SELECT c.id, c.posts_id, c.parent_id, c.users_id, c.text, c.created_at, c.updated_at,
+			(SELECT count(*) FROM comments r WHERE r.parent_id = c.id) AS replies_count
+		FROM comments c
+		JOIN posts p ON p.id = c.posts_id
+		WHERE c.posts_id = $1 AND p.blogs_id = $2 AND p.deleted_at IS NULL
+			AND (($3::uuid IS NULL AND c.parent_id IS NULL) OR c.parent_id = $3)
+			AND ($4::timestamp IS NULL OR (c.created_at, c.id) > ($4, $5))
+		ORDER BY c.created_at, c.id
+		LIMIT $6`
+	var comments []model.DbComment
+	err := pgxscan.Select(ctx, r.db, &comments, query, filter.PostID, filter.BlogID, filter.ParentID, afterCreatedAt, afterID, filter.Limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.CommentRepo.GetComments")
+	}
+	return comments, nil
+}
+
+func (r *CommentRepo) UpdateComment(ctx context.Context, blogID uuid.UUID, comment model.DbComment) (model.DbComment, error) {
+	query := `UPDATE comments c SET text = $1, updated_at = now()
+		FROM posts p
+		WHERE c.id = $2 AND c.posts_id = $3 AND p.id = c.posts_id AND p.blogs_id = $4 AND p.deleted_at IS NULL
+		RETURNING c.id, c.posts_id, c.parent_id, c.users_id, c.text, c.created_at, c.updated_at`
+	var res model.DbComment
+	if err := pgxscan.Get(ctx, r.db, &res, query, comment.Text, comment.ID, comment.PostID, blogID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DbComment{}, apperror.ErrNotFound
+		}
+		return model.DbComment{}, errors.Wrap(err, "repository.CommentRepo.UpdateComment")
+	}
+	return res, nil
+}
+
+// DeleteComment deletes comment with all replies to it
+func (r *CommentRepo) DeleteComment(ctx context.Context, commentID uuid.UUID, postID uuid.UUID, blogID uuid.UUID) error {
+	query := `DELETE FROM comments c USING posts p
+		WHERE c.id = $1 AND c.posts_id = $2 AND p.id = c.posts_id AND p.blogs_id = $3 AND p.deleted_at IS NULL`
+	cmdTag, err := r.db.Exec(ctx, query, commentID, postID, blogID)
+	if err != nil {
+		return errors.Wrap(err, "repository.CommentRepo.DeleteComment")
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+// GetCommentOwners returns the author of the comment and the owner of its blog
+func (r *CommentRepo) GetCommentOwners(ctx context.Context, commentID uuid.UUID, postID uuid.UUID, blogID uuid.UUID) (model.DbCommentOwners, error) {
+	query := `SELECT c.users_id AS author_id, b.users_id AS blog_owner_id
+		FROM comments c
+		JOIN posts p ON p.id = c.posts_id
+		JOIN blogs b ON b.id = p.blogs_id
+		WHERE c.id = $1 AND c.posts_id = $2 AND p.blogs_id = $3 AND p.deleted_at IS NULL`
+	var owners model.DbCommentOwners
+	if err := pgxscan.Get(ctx, r.db, &owners, query, commentID, postID, blogID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DbCommentOwners{}, apperror.ErrNotFound
+		}
+		return model.DbCommentOwners{}, errors.Wrap(err, "repository.CommentRepo.GetCommentOwners")
+	}
+	return owners, nil
+}
+
+// GetPostAccess returns status of the post and the owner of its blog
+func (r *CommentRepo) GetPostAccess(ctx context.Context, postID uuid.UUID, blogID uuid.UUID) (model.DbPostAccess, error) {
+	query := `SELECT p.status, b.users_id AS blog_owner_id
+		FROM posts p
+		JOIN blogs b ON b.id = p.blogs_id
+		WHERE p.id = $1 AND p.blogs_id = $2 AND p.deleted_at IS NULL`
+	var access model.DbPostAccess
+	if err := pgxscan.Get(ctx, r.db, &access, query, postID, blogID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DbPostAccess{}, apperror.ErrNotFound
+		}
+		return model.DbPostAccess{}, errors.Wrap(err, "repository.CommentRepo.GetPostAccess")
+	}
+	return access, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+type IdempotencyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewIdempotencyRepo(conn *pgxpool.Pool) *IdempotencyRepo {
+	return &IdempotencyRepo{
+		db: conn,
+	}
+}
+
+// ClaimKey saves pending record for key unless live record exists. It returns existing record and false
+// when the key has been claimed by another request, expired records are taken over.
+func (r *IdempotencyRepo) ClaimKey(ctx context.Context, key string, requestHash []byte, ttl time.Duration) (model.DbIdempotencyRecord, bool, error) {
+	query := `INSERT INTO idempotency_keys(key, request_hash, expires_at) VALUES($1, $2, now() + $3::interval)
+		ON CONFLICT (key) DO UPDATE SET request_hash = EXCLUDED.request_hash, status = 0, content_type = '',
+			body = NULL, created_at = now(), expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < now()
+		RETURNING key`
+	if err := r.db.QueryRow(ctx, query, key, requestHash, ttl).Scan(nil); err == nil {
+		return model.DbIdempotencyRecord{}, true, nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return model.DbIdempotencyRecord{}, false, errors.Wrap(err, "repository.IdempotencyRepo.ClaimKey")
+	}
+
+	var record model.DbIdempotencyRecord
+	if err := pgxscan.Get(ctx, r.db, &record, "SELECT key, request_hash, status, content_type, body, expires_at FROM idempotency_keys WHERE key = $1", key); err != nil {
+		return model.DbIdempotencyRecord{}, false, errors.Wrap(err, "repository.IdempotencyRepo.ClaimKey")
+	}
+	return record, false, nil
+}
+
+func (r *IdempotencyRepo) SaveResponse(ctx context.Context, key string, status int, contentType string, body []byte) error {
+	if _, err := r.db.Exec(ctx, "UPDATE idempotency_keys SET status = $1, content_type = $2, body = $3 WHERE key = $4", status, contentType, body, key); err != nil {
+		return errors.Wrap(err, "repository.IdempotencyRepo.SaveResponse")
+	}
+	return nil
+}
+
+// ReleaseKey removes pending record, so that failed request can be retried with the same key
+func (r *IdempotencyRepo) ReleaseKey(ctx context.Context, key string) error {
+	if _, err := r.db.Exec(ctx, "DELETE FROM idempotency_keys WHERE key = $1 AND status = 0", key); err != nil {
+		return errors.Wrap(err, "repository.IdempotencyRepo.ReleaseKey")
+	}
+	return nil
+}
+
+func (r *IdempotencyRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	tag, err := r.db.Exec(ctx, "DELETE FROM idempotency_keys WHERE expires_at < now()")
+	if err != nil {
+		return 0, errors.Wrap(err, "repository.IdempotencyRepo.DeleteExpired")
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"io"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/slug"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+// ImportPosts inserts posts in one transaction, one COPY per batch returned by next until it returns io.EOF.
+// Every post gets its first revision and tags, as in AddPost.
+func (r *BlogRepo) ImportPosts(ctx context.Context, blogID uuid.UUID, next func() ([]model.DbPost, error)) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "blogprovider.BlogRepo.ImportPosts")
+	}
+	defer tx.Rollback(ctx)
+	if err := tx.QueryRow(ctx, "SELECT id FROM blogs WHERE id = $1 AND deleted_at IS NULL", blogID).Scan(nil); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.ErrNotFound
+		}
+		return errors.Wrap(err, "blogprovider.BlogRepo.ImportPosts")
+	}
+	taken, err := importTakenSlugs(ctx, tx, blogID)
+	if err != nil {
+		return errors.Wrap(err, "blogprovider.BlogRepo.ImportPosts")
+	}
+	for {
+		posts, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "blogprovider.BlogRepo.ImportPosts")
+		}
+		// posts of the batch do not collide with each other and with earlier batches too
+		slugs := make([]string, len(posts))
+		for i := 0; i < len(posts); i++ {
+			slugs[i] = slug.Next(slugBase(posts[i].Title, postSlugFallback), taken)
+			taken[slugs[i]] = true
+		}
+		if err := copyPosts(ctx, tx, blogID, posts, slugs); err != nil {
+			return errors.Wrap(err, "blogprovider.BlogRepo.ImportPosts")
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "blogprovider.BlogRepo.ImportPosts")
+	}
+	return nil
+}
+
+func copyPosts(ctx context.Context, tx pgx.Tx, blogID uuid.UUID, posts []model.DbPost, slugs []string) error {
+	_, err := tx.CopyFrom(ctx, pgx.Identifier{"posts"}, []string{"id", "blogs_id", "title", "text", "slug", "status", "publish_at", "created_at", "version"},
+		pgx.CopyFromSlice(len(posts), func(i int) ([]any, error) {
+			return []any{posts[i].ID, blogID, posts[i].Title, posts[i].Text, slugs[i], posts[i].Status, posts[i].PublishAt, posts[i].CreatedAt, posts[i].Version}, nil
+		}))
+	if err != nil {
+		return errors.Wrap(err, "blogprovider.copyPosts")
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"post_revisions"}, []string{"posts_id", "revision", "title", "text", "created_at"},
+		pgx.CopyFromSlice(len(posts), func(i int) ([]any, error) {
+			return []any{posts[i].ID, 1, posts[i].Title, posts[i].Text, posts[i].CreatedAt}, nil
+		}))
+	if err != nil {
+		return errors.Wrap(err, "blogprovider.copyPosts")
+	}
+	created := make([]model.DbPost, len(posts))
+	for i := 0; i < len(posts); i++ {
+		created[i] = posts[i]
+		created[i].BlogID = blogID
+		created[i].Slug = slugs[i]
+	}
+	if err := insertPostEvents(ctx, tx, model.EventPostCreated, created); err != nil {
+		return errors.Wrap(err, "blogprovider.copyPosts")
+	}
+
+	var postIDs []uuid.UUID
+	var tags []string
+	for i := 0; i < len(posts); i++ {
+		for _, tag := range posts[i].Tags {
+			postIDs = append(postIDs, posts[i].ID)
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO tags(name) SELECT DISTINCT unnest($1::text[]) ON CONFLICT (name) DO NOTHING", tags); err != nil {
+		return errors.Wrap(err, "blogprovider.copyPosts")
+	}
+	query := `INSERT INTO post_tags(posts_id, tags_id)
+		SELECT p.id, t.id FROM unnest($1::uuid[], $2::text[]) AS p(id, name) JOIN tags t ON t.name = p.name`
+	if _, err := tx.Exec(ctx, query, postIDs, tags); err != nil {
+		return errors.Wrap(err, "blogprovider.copyPosts")
+	}
+	return nil
+}
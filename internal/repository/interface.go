@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/Rolan335/project/internal/model"
 	"github.com/google/uuid"
@@ -10,12 +11,75 @@ import (
 //go:generate mockgen -source=interface.go -destination=../../mocks/blogrepository.go -package=mocks
 type BlogRepository interface {
 	GetBlog(ctx context.Context, blogID uuid.UUID) (model.DbBlog, error)
-	AddBlog(ctx context.Context, blog model.DbBlog) (uuid.UUID, error)
+	GetBlogOwner(ctx context.Context, blogID uuid.UUID) (uuid.UUID, error)
+	GetBlogBySlug(ctx context.Context, slug string) (model.DbBlog, error)
+	AddBlog(ctx context.Context, blog model.DbBlog) (model.DbBlog, error)
 	UpdateBlog(ctx context.Context, blog model.DbBlog) (model.DbBlog, error)
 	DeleteBlog(ctx context.Context, blogID uuid.UUID) error
+	GetDeletedBlogs(ctx context.Context, userID uuid.UUID) ([]model.DbBlog, error)
+	RestoreBlog(ctx context.Context, blogID uuid.UUID) (model.DbBlog, error)
 	GetPost(ctx context.Context, postID uuid.UUID) (model.DbPost, error)
-	GetPosts(ctx context.Context, BlogID uuid.UUID) ([]model.DbPost, error)
-	AddPost(ctx context.Context, post model.DbPost) (uuid.UUID, error)
+	GetPostBySlug(ctx context.Context, blogID uuid.UUID, slug string) (model.DbPost, error)
+	GetPosts(ctx context.Context, filter model.DbPostsFilter) ([]model.DbPost, error)
+	GetBlogsPosts(ctx context.Context, filter model.DbBlogsPostsFilter) ([]model.DbPost, error)
+	StreamPosts(ctx context.Context, blogID uuid.UUID, fn func(model.DbPost) error) error
+	SearchPosts(ctx context.Context, search model.DbPostsSearch) ([]model.DbPostSearchResult, error)
+	AddPost(ctx context.Context, post model.DbPost) (model.DbPost, error)
+	ImportPosts(ctx context.Context, blogID uuid.UUID, next func() ([]model.DbPost, error)) error
 	UpdatePost(ctx context.Context, post model.DbPost) (model.DbPost, error)
+	PublishScheduled(ctx context.Context, limit int) ([]model.DbPost, error)
 	DeletePost(ctx context.Context, postID uuid.UUID, blogID uuid.UUID) error
+	GetDeletedPosts(ctx context.Context, blogID uuid.UUID) ([]model.DbPost, error)
+	RestorePost(ctx context.Context, postID uuid.UUID, blogID uuid.UUID) (model.DbPost, error)
+	PurgeDeleted(ctx context.Context, olderThan time.Duration) (int64, error)
+	GetPostRevisions(ctx context.Context, postID uuid.UUID, blogID uuid.UUID) ([]model.DbPostRevision, error)
+	GetPostRevision(ctx context.Context, postID uuid.UUID, blogID uuid.UUID, revision int) (model.DbPostRevision, error)
+	GetBlogTags(ctx context.Context, blogID uuid.UUID) ([]model.DbTagCount, error)
+}
+
+type CommentRepository interface {
+	AddComment(ctx context.Context, blogID uuid.UUID, comment model.DbComment) (uuid.UUID, error)
+	GetComments(ctx context.Context, filter model.DbCommentsFilter) ([]model.DbComment, error)
+	UpdateComment(ctx context.Context, blogID uuid.UUID, comment model.DbComment) (model.DbComment, error)
+	DeleteComment(ctx context.Context, commentID uuid.UUID, postID uuid.UUID, blogID uuid.UUID) error
+	GetPostAccess(ctx context.Context, postID uuid.UUID, blogID uuid.UUID) (model.DbPostAccess, error)
+	GetCommentOwners(ctx context.Context, commentID uuid.UUID, postID uuid.UUID, blogID uuid.UUID) (model.DbCommentOwners, error)
+}
+
+type UserRepository interface {
+	AddUser(ctx context.Context, user model.DbUser) (uuid.UUID, error)
+	GetUser(ctx context.Context, userID uuid.UUID) (model.DbUser, error)
+	UpdateUser(ctx context.Context, user model.DbUser) (model.DbUser, error)
+	GetUserBlogs(ctx context.Context, userID uuid.UUID) ([]model.DbBlog, error)
+}
+
+type APIKeyRepository interface {
+	AddAPIKey(ctx context.Context, key model.DbAPIKey) (uuid.UUID, error)
+	GetAPIKeys(ctx context.Context, userID uuid.UUID) ([]model.DbAPIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.DbAPIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID uuid.UUID, userID uuid.UUID) error
+}
+
+type OutboxRepository interface {
+	RelayEvents(ctx context.Context, limit int, fn func(model.DbOutboxEvent) error) (int, error)
+	DeleteSent(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+type WebhookRepository interface {
+	GetBlogOwner(ctx context.Context, blogID uuid.UUID) (uuid.UUID, error)
+	AddWebhook(ctx context.Context, hook model.DbWebhook) (uuid.UUID, error)
+	GetWebhooks(ctx context.Context, blogID uuid.UUID) ([]model.DbWebhook, error)
+	DeleteWebhook(ctx context.Context, webhookID uuid.UUID, blogID uuid.UUID) error
+	AddDeliveries(ctx context.Context, blogID uuid.UUID, event model.DbOutboxEvent, payload []byte) (int64, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.DbWebhookDelivery, error)
+	SaveAttempt(ctx context.Context, attempt model.DbWebhookAttempt) error
+	GetDeliveries(ctx context.Context, webhookID uuid.UUID, blogID uuid.UUID, limit int) ([]model.DbWebhookDelivery, error)
+	Redeliver(ctx context.Context, deliveryID uuid.UUID, webhookID uuid.UUID, blogID uuid.UUID) (model.DbWebhookDelivery, error)
+}
+
+type IdempotencyRepository interface {
+	ClaimKey(ctx context.Context, key string, requestHash []byte, ttl time.Duration) (model.DbIdempotencyRecord, bool, error)
+	SaveResponse(ctx context.Context, key string, status int, contentType string, body []byte) error
+	ReleaseKey(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// traceContext returns trace of ctx in the form of the configured propagator
+func traceContext(ctx context.Context) propagation.MapCarrier {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier
+}
+
+// outboxWriteLock is held shared by transactions writing to outbox until they end, the relay takes it exclusive
+// to wait for them. Ids are taken before commit, so without it the relay could send an event
+// while the one with a lower id is still to be committed.
+const outboxWriteLock = "outbox_write"
+
+// lockOutboxWrite must be called before events are inserted
+func lockOutboxWrite(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock_shared(hashtext($1))", outboxWriteLock)
+	return err
+}
+
+// insertEvent writes event to outbox in the transaction of the change,
+// so the event is stored if and only if the change is committed
+func insertEvent(ctx context.Context, tx pgx.Tx, eventType string, aggregateID uuid.UUID, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "repository.insertEvent")
+	}
+	if err := lockOutboxWrite(ctx, tx); err != nil {
+		return errors.Wrap(err, "repository.insertEvent")
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO outbox(event_type, aggregate_id, payload, trace_context) VALUES($1, $2, $3, $4)",
+		eventType, aggregateID, data, traceContext(ctx)); err != nil {
+		return errors.Wrap(err, "repository.insertEvent")
+	}
+	return nil
+}
+
+// insertPostEvents is insertEvent for a batch of posts
+func insertPostEvents(ctx context.Context, tx pgx.Tx, eventType string, posts []model.DbPost) error {
+	ids := make([]uuid.UUID, 0, len(posts))
+	payloads := make([][]byte, 0, len(posts))
+	for i := 0; i < len(posts); i++ {
+		data, err := json.Marshal(posts[i])
+		if err != nil {
+			return errors.Wrap(err, "repository.insertPostEvents")
+		}
+		ids = append(ids, posts[i].ID)
+		payloads = append(payloads, data)
+	}
+	if err := lockOutboxWrite(ctx, tx); err != nil {
+		return errors.Wrap(err, "repository.insertPostEvents")
+	}
+	// WITH ORDINALITY keeps ids of events in the order of posts
+	query := `INSERT INTO outbox(event_type, aggregate_id, payload, trace_context)
+		SELECT $1, e.id, e.payload, $4 FROM unnest($2::uuid[], $3::jsonb[]) WITH ORDINALITY AS e(id, payload, n) ORDER BY e.n`
+	if _, err := tx.Exec(ctx, query, eventType, ids, payloads, traceContext(ctx)); err != nil {
+		return errors.Wrap(err, "repository.insertPostEvents")
+	}
+	return nil
+}
+
+type OutboxRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxRepo(conn *pgxpool.Pool) *OutboxRepo {
+	return &OutboxRepo{
+		db: conn,
+	}
+}
+
+// committedID returns the last outbox id all events up to which are committed or rolled back.
+// It waits for the writers holding outboxWriteLock, the lock is released right after,
+// writers which come later take greater ids.
+func (r *OutboxRepo) committedID(ctx context.Context) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", outboxWriteLock); err != nil {
+		return 0, err
+	}
+	var id int64
+	if err := tx.QueryRow(ctx, "SELECT last_value FROM outbox_id_seq").Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit(ctx)
+}
+
+// RelayEvents passes at most limit pending events to fn in id order and marks delivered ones as sent.
+// Delivery stops at the first error of fn, the failed event and the rest are passed again on the next call.
+// Only one relay works at a time to keep the order, the call returns 0 right away when another one holds the lock.
+// Events after one whose transaction is still open wait for the next call, so they are not sent before it.
+func (r *OutboxRepo) RelayEvents(ctx context.Context, limit int, fn func(model.DbOutboxEvent) error) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "repository.OutboxRepo.RelayEvents")
+	}
+	defer tx.Rollback(ctx)
+	var locked bool
+	if err := tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock(hashtext('outbox'))").Scan(&locked); err != nil {
+		return 0, errors.Wrap(err, "repository.OutboxRepo.RelayEvents")
+	}
+	if !locked {
+		return 0, nil
+	}
+	committed, err := r.committedID(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "repository.OutboxRepo.RelayEvents")
+	}
+	var events []model.DbOutboxEvent
+	query := `SELECT id, event_type, aggregate_id, payload, trace_context, created_at FROM outbox
+		WHERE sent_at IS NULL AND id <= $2 ORDER BY id LIMIT $1`
+	if err := pgxscan.Select(ctx, tx, &events, query, limit, committed); err != nil {
+		return 0, errors.Wrap(err, "repository.OutboxRepo.RelayEvents")
+	}
+	sent := make([]int64, 0, len(events))
+	var fnErr error
+	for i := 0; i < len(events); i++ {
+		if fnErr = fn(events[i]); fnErr != nil {
+			break
+		}
+		sent = append(sent, events[i].ID)
+	}
+	if len(sent) > 0 {
+		if _, err := tx.Exec(ctx, "UPDATE outbox SET sent_at = now() WHERE id = ANY($1)", sent); err != nil {
+			return 0, errors.Wrap(err, "repository.OutboxRepo.RelayEvents")
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, errors.Wrap(err, "repository.OutboxRepo.RelayEvents")
+	}
+	if fnErr != nil {
+		return len(sent), errors.Wrap(fnErr, "repository.OutboxRepo.RelayEvents")
+	}
+	return len(sent), nil
+}
+
+// DeleteSent removes events sent more than olderThan ago
+func (r *OutboxRepo) DeleteSent(ctx context.Context, olderThan time.Duration) (int64, error) {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM outbox WHERE sent_at < now() - $1::interval", olderThan)
+	if err != nil {
+		return 0, errors.Wrap(err, "repository.OutboxRepo.DeleteSent")
+	}
+	return cmdTag.RowsAffected(), nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/slug"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+// slugs for names without letters and digits
+const (
+	blogSlugFallback = "blog"
+	postSlugFallback = "post"
+)
+
+// blog slugs are unique globally, post slugs within a blog. Current slugs and aliases share the namespace,
+// so an old link never points to another blog. Aliases of the blog itself are free to be taken back.
+const (
+	blogSlugsTakenQuery = `SELECT slug FROM blogs WHERE id <> $1 AND (slug = $2 OR slug LIKE $2 || '-%')
+		UNION ALL SELECT slug FROM blog_slug_aliases WHERE blogs_id <> $1 AND (slug = $2 OR slug LIKE $2 || '-%')`
+	postSlugsTakenQuery = `SELECT slug FROM posts WHERE blogs_id = $1 AND id <> $2 AND (slug = $3 OR slug LIKE $3 || '-%')
+		UNION ALL SELECT slug FROM post_slug_aliases WHERE blogs_id = $1 AND posts_id <> $2 AND (slug = $3 OR slug LIKE $3 || '-%')`
+)
+
+func slugBase(s string, fallback string) string {
+	if base := slug.Make(s); base != "" {
+		return base
+	}
+	return fallback
+}
+
+// lockSlugs serializes slug allocation within scope until the end of transaction,
+// so concurrent inserts of the same title get different suffixes instead of unique violation
+func lockSlugs(ctx context.Context, tx pgx.Tx, scope string) error {
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", scope); err != nil {
+		return errors.Wrap(err, "repository.lockSlugs")
+	}
+	return nil
+}
+
+func takenSlugs(ctx context.Context, tx pgx.Tx, query string, args ...any) (map[string]bool, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.takenSlugs")
+	}
+	slugs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.takenSlugs")
+	}
+	taken := make(map[string]bool, len(slugs))
+	for _, s := range slugs {
+		taken[s] = true
+	}
+	return taken, nil
+}
+
+func newBlogSlug(ctx context.Context, tx pgx.Tx, blogID uuid.UUID, name string) (string, error) {
+	if err := lockSlugs(ctx, tx, "blogs"); err != nil {
+		return "", errors.Wrap(err, "repository.newBlogSlug")
+	}
+	base := slugBase(name, blogSlugFallback)
+	taken, err := takenSlugs(ctx, tx, blogSlugsTakenQuery, blogID, base)
+	if err != nil {
+		return "", errors.Wrap(err, "repository.newBlogSlug")
+	}
+	return slug.Next(base, taken), nil
+}
+
+func newPostSlug(ctx context.Context, tx pgx.Tx, blogID uuid.UUID, postID uuid.UUID, title string) (string, error) {
+	if err := lockSlugs(ctx, tx, "posts:"+blogID.String()); err != nil {
+		return "", errors.Wrap(err, "repository.newPostSlug")
+	}
+	base := slugBase(title, postSlugFallback)
+	taken, err := takenSlugs(ctx, tx, postSlugsTakenQuery, blogID, postID, base)
+	if err != nil {
+		return "", errors.Wrap(err, "repository.newPostSlug")
+	}
+	return slug.Next(base, taken), nil
+}
+
+// renameBlogSlug gives the blog a new slug when the name no longer matches the current one.
+// The old slug is kept as an alias.
+func renameBlogSlug(ctx context.Context, tx pgx.Tx, blog *model.DbBlog) error {
+	if slug.HasBase(blog.Slug, slugBase(blog.Name, blogSlugFallback)) {
+		return nil
+	}
+	newSlug, err := newBlogSlug(ctx, tx, blog.ID, blog.Name)
+	if err != nil {
+		return errors.Wrap(err, "repository.renameBlogSlug")
+	}
+	if _, err := tx.Exec(ctx, "UPDATE blogs SET slug = $1 WHERE id = $2", newSlug, blog.ID); err != nil {
+		return errors.Wrap(err, "repository.renameBlogSlug")
+	}
+	// the blog may take back one of its old slugs
+	if _, err := tx.Exec(ctx, "DELETE FROM blog_slug_aliases WHERE slug = $1 AND blogs_id = $2", newSlug, blog.ID); err != nil {
+		return errors.Wrap(err, "repository.renameBlogSlug")
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO blog_slug_aliases(slug, blogs_id) VALUES($1, $2)", blog.Slug, blog.ID); err != nil {
+		return errors.Wrap(err, "repository.renameBlogSlug")
+	}
+	blog.Slug = newSlug
+	return nil
+}
+
+// renamePostSlug is renameBlogSlug for post title
+func renamePostSlug(ctx context.Context, tx pgx.Tx, post *model.DbPost) error {
+	if slug.HasBase(post.Slug, slugBase(post.Title, postSlugFallback)) {
+		return nil
+	}
+	newSlug, err := newPostSlug(ctx, tx, post.BlogID, post.ID, post.Title)
+	if err != nil {
+		return errors.Wrap(err, "repository.renamePostSlug")
+	}
+	if _, err := tx.Exec(ctx, "UPDATE posts SET slug = $1 WHERE id = $2", newSlug, post.ID); err != nil {
+		return errors.Wrap(err, "repository.renamePostSlug")
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM post_slug_aliases WHERE blogs_id = $1 AND slug = $2 AND posts_id = $3", post.BlogID, newSlug, post.ID); err != nil {
+		return errors.Wrap(err, "repository.renamePostSlug")
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO post_slug_aliases(blogs_id, slug, posts_id) VALUES($1, $2, $3)", post.BlogID, post.Slug, post.ID); err != nil {
+		return errors.Wrap(err, "repository.renamePostSlug")
+	}
+	post.Slug = newSlug
+	return nil
+}
+
+// importTakenSlugs locks post slugs of the blog for the import and returns the taken ones,
+// the import adds slugs of its posts to the set as it goes
+func importTakenSlugs(ctx context.Context, tx pgx.Tx, blogID uuid.UUID) (map[string]bool, error) {
+	if err := lockSlugs(ctx, tx, "posts:"+blogID.String()); err != nil {
+		return nil, errors.Wrap(err, "repository.importTakenSlugs")
+	}
+	taken, err := takenSlugs(ctx, tx, `SELECT slug FROM posts WHERE blogs_id = $1
+		UNION ALL SELECT slug FROM post_slug_aliases WHERE blogs_id = $1`, blogID)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.importTakenSlugs")
+	}
+	return taken, nil
+}
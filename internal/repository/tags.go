@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+// postTagsColumn selects sorted tags of the post, query must select from posts without alias
+const postTagsColumn = `coalesce(array(SELECT t.name FROM post_tags pt JOIN tags t ON t.id = pt.tags_id
+	WHERE pt.posts_id = posts.id ORDER BY t.name), '{}') AS tags`
+
+// setPostTags replaces tags of the post, unknown tags are created
+func setPostTags(ctx context.Context, tx pgx.Tx, postID uuid.UUID, tags []string) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM post_tags WHERE posts_id = $1", postID); err != nil {
+		return errors.Wrap(err, "blogprovider.setPostTags")
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO tags(name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING", tags); err != nil {
+		return errors.Wrap(err, "blogprovider.setPostTags")
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO post_tags(posts_id, tags_id) SELECT $1, id FROM tags WHERE name = ANY($2)", postID, tags); err != nil {
+		return errors.Wrap(err, "blogprovider.setPostTags")
+	}
+	return nil
+}
+
+func getPostTags(ctx context.Context, tx pgx.Tx, postID uuid.UUID) ([]string, error) {
+	var tags []string
+	if err := tx.QueryRow(ctx, "SELECT "+postTagsColumn+" FROM posts WHERE id = $1", postID).Scan(&tags); err != nil {
+		return nil, errors.Wrap(err, "blogprovider.getPostTags")
+	}
+	return tags, nil
+}
+
+func (r *BlogRepo) GetBlogTags(ctx context.Context, blogID uuid.UUID) ([]model.DbTagCount, error) {
+	query := `SELECT t.name, count(*) AS count FROM tags t
+		JOIN post_tags pt ON pt.tags_id = t.id
+		JOIN posts p ON p.id = pt.posts_id
+		WHERE p.blogs_id = $1 AND p.deleted_at IS NULL AND p.status = 'published'
+		GROUP BY t.name
+		ORDER BY count DESC, t.name`
+	var tags []model.DbTagCount
+	if err := pgxscan.Select(ctx, r.db, &tags, query, blogID); err != nil {
+		return nil, errors.Wrap(err, "blogprovider.BlogRepo.GetBlogTags")
+	}
+	return tags, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// users created implicitly before registration existed have no profile, so nullable columns are coalesced
+const userColumns = `id, coalesce(display_name, '') AS display_name, coalesce(email, '') AS email,
+	coalesce(bio, '') AS bio, coalesce(created_at, CURRENT_TIMESTAMP) AS created_at`
+
+type UserRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewUserRepo(conn *pgxpool.Pool) *UserRepo {
+	return &UserRepo{
+		db: conn,
+	}
+}
+
+func (r *UserRepo) AddUser(ctx context.Context, user model.DbUser) (uuid.UUID, error) {
+	_, err := r.db.Exec(ctx, "INSERT INTO users(id, display_name, email, bio, created_at) VALUES($1, $2, $3, $4, $5)",
+		user.ID,
+		user.DisplayName,
+		user.Email,
+		user.Bio,
+		user.CreatedAt,
+	)
+	if err != nil {
+		if hasPgCode(err, uniqueViolation) {
+			return uuid.Nil, apperror.ErrConflict
+		}
+		return uuid.Nil, errors.Wrap(err, "repository.UserRepo.AddUser")
+	}
+	return user.ID, nil
+}
+
+func (r *UserRepo) GetUser(ctx context.Context, userID uuid.UUID) (model.DbUser, error) {
+	var user model.DbUser
+	if err := pgxscan.Get(ctx, r.db, &user, "SELECT "+userColumns+" FROM users WHERE id = $1", userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DbUser{}, apperror.ErrNotFound
+		}
+		return model.DbUser{}, errors.Wrap(err, "repository.UserRepo.GetUser")
+	}
+	return user, nil
+}
+
+func (r *UserRepo) UpdateUser(ctx context.Context, user model.DbUser) (model.DbUser, error) {
+	var userRes model.DbUser
+	query := "UPDATE users SET display_name = $1, email = $2, bio = $3 WHERE id = $4 RETURNING " + userColumns
+	if err := pgxscan.Get(ctx, r.db, &userRes, query, user.DisplayName, user.Email, user.Bio, user.ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DbUser{}, apperror.ErrNotFound
+		}
+		if hasPgCode(err, uniqueViolation) {
+			return model.DbUser{}, apperror.ErrConflict
+		}
+		return model.DbUser{}, errors.Wrap(err, "repository.UserRepo.UpdateUser")
+	}
+	return userRes, nil
+}
+
+func (r *UserRepo) GetUserBlogs(ctx context.Context, userID uuid.UUID) ([]model.DbBlog, error) {
+	query := `SELECT id, users_id, name, slug, created_at, version FROM blogs
+		WHERE users_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC, id DESC`
+	var blogs []model.DbBlog
+	if err := pgxscan.Select(ctx, r.db, &blogs, query, userID); err != nil {
+		return nil, errors.Wrap(err, "repository.UserRepo.GetUserBlogs")
+	}
+	return blogs, nil
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+type WebhookRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookRepo(conn *pgxpool.Pool) *WebhookRepo {
+	return &WebhookRepo{
+		db: conn,
+	}
+}
+
+func (r *WebhookRepo) GetBlogOwner(ctx context.Context, blogID uuid.UUID) (uuid.UUID, error) {
+	var userID uuid.UUID
+	if err := r.db.QueryRow(ctx, "SELECT users_id FROM blogs WHERE id = $1", blogID).Scan(&userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, apperror.ErrNotFound
+		}
+		return uuid.Nil, errors.Wrap(err, "repository.WebhookRepo.GetBlogOwner")
+	}
+	return userID, nil
+}
+
+func (r *WebhookRepo) AddWebhook(ctx context.Context, hook model.DbWebhook) (uuid.UUID, error) {
+	_, err := r.db.Exec(ctx, `INSERT INTO webhooks(id, blogs_id, url, secret, events, created_at)
+		VALUES($1, $2, $3, $4, $5, $6)`,
+		hook.ID,
+		hook.BlogID,
+		hook.URL,
+		hook.Secret,
+		hook.Events,
+		hook.CreatedAt,
+	)
+	if err != nil {
+		if hasPgCode(err, foreignKeyViolation) {
+			return uuid.Nil, apperror.ErrNotFound
+		}
+		return uuid.Nil, errors.Wrap(err, "repository.WebhookRepo.AddWebhook")
+	}
+	return hook.ID, nil
+}
+
+func (r *WebhookRepo) GetWebhooks(ctx context.Context, blogID uuid.UUID) ([]model.DbWebhook, error) {
+	query := `SELECT id, blogs_id, url, secret, events, created_at FROM webhooks
+		WHERE blogs_id = $1
+		ORDER BY created_at DESC, id DESC`
+	var hooks []model.DbWebhook
+	if err := pgxscan.Select(ctx, r.db, &hooks, query, blogID); err != nil {
+		return nil, errors.Wrap(err, "repository.WebhookRepo.GetWebhooks")
+	}
+	return hooks, nil
+}
+
+// DeleteWebhook removes the webhook with its delivery log
+func (r *WebhookRepo) DeleteWebhook(ctx context.Context, webhookID uuid.UUID, blogID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM webhooks WHERE id = $1 AND blogs_id = $2", webhookID, blogID)
+	if err != nil {
+		return errors.Wrap(err, "repository.WebhookRepo.DeleteWebhook")
+	}
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+// AddDeliveries creates a pending delivery of the event for every webhook of the blog subscribed to it.
+// An event passed again does not make new deliveries.
+func (r *WebhookRepo) AddDeliveries(ctx context.Context, blogID uuid.UUID, event model.DbOutboxEvent, payload []byte) (int64, error) {
+	query := `INSERT INTO webhook_deliveries(id, webhooks_id, event_id, event_type, payload, trace_context)
+		SELECT gen_random_uuid(), id, $2, $3, $4, $5 FROM webhooks
+		WHERE blogs_id = $1 AND $3 = ANY(events)
+		ON CONFLICT (webhooks_id, event_id) DO NOTHING`
+	traceContext := event.TraceContext
+	if traceContext == nil {
+		traceContext = map[string]string{}
+	}
+	tag, err := r.db.Exec(ctx, query, blogID, event.ID, event.Type, payload, traceContext)
+	if err != nil {
+		return 0, errors.Wrap(err, "repository.WebhookRepo.AddDeliveries")
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ClaimDeliveries returns at most limit due deliveries with url and secret of their webhooks.
+// Claimed deliveries are not due for lease, so other workers skip them while they are being sent.
+func (r *WebhookRepo) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.DbWebhookDelivery, error) {
+	query := `WITH claimed AS (
+			UPDATE webhook_deliveries SET next_attempt_at = now() + $2::interval
+			WHERE id IN (
+				SELECT id FROM webhook_deliveries
+				WHERE status = 'pending' AND next_attempt_at <= now()
+				ORDER BY next_attempt_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, webhooks_id, event_id, event_type, payload, trace_context, status, attempts,
+				next_attempt_at, response_status, last_error, created_at, delivered_at
+		)
+		SELECT c.*, w.url, w.secret FROM claimed c JOIN webhooks w ON w.id = c.webhooks_id
+		ORDER BY c.event_id`
+	var deliveries []model.DbWebhookDelivery
+	if err := pgxscan.Select(ctx, r.db, &deliveries, query, limit, lease); err != nil {
+		return nil, errors.Wrap(err, "repository.WebhookRepo.ClaimDeliveries")
+	}
+	return deliveries, nil
+}
+
+// SaveAttempt records the outcome of an attempt, next_attempt_at matters for pending deliveries only
+func (r *WebhookRepo) SaveAttempt(ctx context.Context, attempt model.DbWebhookAttempt) error {
+	query := `UPDATE webhook_deliveries SET
+			status = $2,
+			attempts = attempts + 1,
+			next_attempt_at = $3,
+			response_status = $4,
+			last_error = $5,
+			delivered_at = CASE WHEN $2 = 'delivered' THEN now() END
+		WHERE id = $1`
+	tag, err := r.db.Exec(ctx, query, attempt.DeliveryID, attempt.Status, attempt.NextAttemptAt, attempt.ResponseStatus, attempt.Error)
+	if err != nil {
+		return errors.Wrap(err, "repository.WebhookRepo.SaveAttempt")
+	}
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+// GetDeliveries returns the delivery log of the webhook, newest first
+func (r *WebhookRepo) GetDeliveries(ctx context.Context, webhookID uuid.UUID, blogID uuid.UUID, limit int) ([]model.DbWebhookDelivery, error) {
+	query := `SELECT d.id, d.webhooks_id, d.event_id, d.event_type, d.payload, d.trace_context, d.status, d.attempts,
+			d.next_attempt_at, d.response_status, d.last_error, d.created_at, d.delivered_at
+		FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhooks_id
+		WHERE d.webhooks_id = $1 AND w.blogs_id = $2
+		ORDER BY d.created_at DESC, d.event_id DESC
+		LIMIT $3`
+	var deliveries []model.DbWebhookDelivery
+	if err := pgxscan.Select(ctx, r.db, &deliveries, query, webhookID, blogID, limit); err != nil {
+		return nil, errors.Wrap(err, "repository.WebhookRepo.GetDeliveries")
+	}
+	return deliveries, nil
+}
+
+// Redeliver makes the delivery pending and due right away with a fresh set of attempts
+func (r *WebhookRepo) Redeliver(ctx context.Context, deliveryID uuid.UUID, webhookID uuid.UUID, blogID uuid.UUID) (model.DbWebhookDelivery, error) {
+	query := `UPDATE webhook_deliveries d SET status = 'pending', attempts = 0, next_attempt_at = now(), delivered_at = NULL
+		FROM webhooks w
+		WHERE d.id = $1 AND d.webhooks_id = $2 AND w.id = d.webhooks_id AND w.blogs_id = $3
+		RETURNING d.id, d.webhooks_id, d.event_id, d.event_type, d.payload, d.trace_context, d.status, d.attempts,
+			d.next_attempt_at, d.response_status, d.last_error, d.created_at, d.delivered_at`
+	var delivery model.DbWebhookDelivery
+	if err := pgxscan.Get(ctx, r.db, &delivery, query, deliveryID, webhookID, blogID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DbWebhookDelivery{}, apperror.ErrNotFound
+		}
+		return model.DbWebhookDelivery{}, errors.Wrap(err, "repository.WebhookRepo.Redeliver")
+	}
+	return delivery, nil
+}
@@ -0,0 +1,94 @@
+// Package slug makes URL friendly identifiers from names and titles
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLen is the maximum length of a slug, collision suffix included
+const MaxLen = 64
+
+// maxBaseLen leaves room for a collision suffix
+const maxBaseLen = MaxLen - 8
+
+// translit covers letters that do not decompose into latin letter and combining marks
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th",
+}
+
+// Make lowercases s, transliterates it to latin and joins words with '-'.
+// The result is empty if s has no letters or digits.
+func Make(s string) string {
+	var b strings.Builder
+	dash := false
+	write := func(part string) {
+		if dash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		dash = false
+		b.WriteString(part)
+	}
+	for _, r := range strings.ToLower(s) {
+		// transliteration goes first, decomposition would turn й into и
+		if t, ok := translit[r]; ok {
+			if t != "" {
+				write(t)
+			}
+			continue
+		}
+		for _, d := range norm.NFKD.String(string(r)) {
+			switch {
+			case d < unicode.MaxASCII && (unicode.IsLetter(d) || unicode.IsDigit(d)):
+				write(string(d))
+			case unicode.Is(unicode.Mn, d):
+				// combining mark left by decomposition, é -> e
+			default:
+				dash = true
+			}
+		}
+	}
+	res := b.String()
+	if len(res) > maxBaseLen {
+		res = strings.TrimRight(res[:maxBaseLen], "-")
+	}
+	return res
+}
+
+// Next returns base if it is not taken, otherwise base with the smallest free suffix: base-2, base-3...
+func Next(base string, taken map[string]bool) string {
+	if !taken[base] {
+		return base
+	}
+	for i := 2; ; i++ {
+		s := base + "-" + strconv.Itoa(i)
+		if !taken[s] {
+			return s
+		}
+	}
+}
+
+// HasBase reports whether s is base itself or base with a collision suffix
+func HasBase(s, base string) bool {
+	if s == base {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(s, base+"-")
+	if !ok || suffix == "" || suffix[0] == '0' {
+		return false
+	}
+	for _, r := range suffix {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
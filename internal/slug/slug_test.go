@@ -0,0 +1,55 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "latin", in: "Hello, World!", want: "hello-world"},
+		{name: "diacritics", in: "Crème brûlée à la carte", want: "creme-brulee-a-la-carte"},
+		{name: "cyrillic", in: "Привет, мир", want: "privet-mir"},
+		{name: "soft sign", in: "Мысль дня", want: "mysl-dnya"},
+		{name: "short i", in: "Мой район", want: "moy-rayon"},
+		{name: "special letters", in: "Straße Øst", want: "strasse-ost"},
+		{name: "digits", in: "Go 1.24 released", want: "go-1-24-released"},
+		{name: "trim separators", in: "  --Title--  ", want: "title"},
+		{name: "no letters", in: "!!! ???", want: ""},
+		{name: "emoji", in: "🚀 launch", want: "launch"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Make(tt.in))
+		})
+	}
+}
+
+func TestMake_Long(t *testing.T) {
+	s := Make(strings.Repeat("word ", 40))
+	assert.LessOrEqual(t, len(s), maxBaseLen)
+	assert.False(t, strings.HasSuffix(s, "-"))
+}
+
+func TestNext(t *testing.T) {
+	a := assert.New(t)
+	a.Equal("post", Next("post", nil))
+	a.Equal("post-2", Next("post", map[string]bool{"post": true}))
+	a.Equal("post-4", Next("post", map[string]bool{"post": true, "post-2": true, "post-3": true}))
+}
+
+func TestHasBase(t *testing.T) {
+	a := assert.New(t)
+	a.True(HasBase("post", "post"))
+	a.True(HasBase("post-12", "post"))
+	a.False(HasBase("post-about", "post"))
+	a.False(HasBase("post-", "post"))
+	a.False(HasBase("post-02", "post"))
+	a.False(HasBase("posts", "post"))
+}
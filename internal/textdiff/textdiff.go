@@ -0,0 +1,117 @@
+// Package textdiff computes line based diff of two texts
+package textdiff
+
+import (
+	"slices"
+	"strings"
+)
+
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines returns edit script transforming a into b, built on the longest common subsequence of lines.
+// Memory is linear in the number of lines, time is quadratic.
+func Lines(a, b string) []Line {
+	x, y := split(a), split(b)
+	return diff(x, y, make([]Line, 0, max(len(x), len(y))))
+}
+
+// diff appends edit script of x into y to res. It is Hirschberg's algorithm: y is cut where the halves
+// of x together have the longest common subsequence with it, then the halves are diffed on their own.
+func diff(x, y []string, res []Line) []Line {
+	for len(x) > 0 && len(y) > 0 && x[0] == y[0] {
+		res = append(res, Line{Op: OpEqual, Text: x[0]})
+		x, y = x[1:], y[1:]
+	}
+	common := 0
+	for common < len(x) && common < len(y) && x[len(x)-1-common] == y[len(y)-1-common] {
+		common++
+	}
+	suffix := x[len(x)-common:]
+	x, y = x[:len(x)-common], y[:len(y)-common]
+
+	switch {
+	case len(x) == 0:
+		res = appendLines(res, OpInsert, y)
+	case len(y) == 0:
+		res = appendLines(res, OpDelete, x)
+	case len(x) == 1:
+		j := slices.Index(y, x[0])
+		if j < 0 {
+			res = appendLines(res, OpDelete, x)
+			res = appendLines(res, OpInsert, y)
+			break
+		}
+		res = appendLines(res, OpInsert, y[:j])
+		res = append(res, Line{Op: OpEqual, Text: x[0]})
+		res = appendLines(res, OpInsert, y[j+1:])
+	default:
+		mid := len(x) / 2
+		head, tail := lcsHead(x[:mid], y), lcsTail(x[mid:], y)
+		cut := 0
+		for j := 1; j <= len(y); j++ {
+			if head[j]+tail[j] > head[cut]+tail[cut] {
+				cut = j
+			}
+		}
+		res = diff(x[:mid], y[:cut], res)
+		res = diff(x[mid:], y[cut:], res)
+	}
+	return appendLines(res, OpEqual, suffix)
+}
+
+// lcsHead returns lengths of the longest common subsequence of x and every prefix y[:j]
+func lcsHead(x, y []string) []int {
+	prev, cur := make([]int, len(y)+1), make([]int, len(y)+1)
+	for i := 0; i < len(x); i++ {
+		for j := 0; j < len(y); j++ {
+			if x[i] == y[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// lcsTail returns lengths of the longest common subsequence of x and every suffix y[j:]
+func lcsTail(x, y []string) []int {
+	prev, cur := make([]int, len(y)+1), make([]int, len(y)+1)
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				cur[j] = prev[j+1] + 1
+			} else {
+				cur[j] = max(prev[j], cur[j+1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+func appendLines(res []Line, op Op, lines []string) []Line {
+	for _, line := range lines {
+		res = append(res, Line{Op: op, Text: line})
+	}
+	return res
+}
+
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
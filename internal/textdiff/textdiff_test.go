@@ -0,0 +1,109 @@
+package textdiff
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	testCases := []struct {
+		name string
+		a    string
+		b    string
+		want []Line
+	}{
+		{
+			name: "equal",
+			a:    "a\nb",
+			b:    "a\nb",
+			want: []Line{{OpEqual, "a"}, {OpEqual, "b"}},
+		},
+		{
+			name: "changed line",
+			a:    "a\nb\nc",
+			b:    "a\nx\nc",
+			want: []Line{{OpEqual, "a"}, {OpDelete, "b"}, {OpInsert, "x"}, {OpEqual, "c"}},
+		},
+		{
+			name: "appended",
+			a:    "a",
+			b:    "a\nb",
+			want: []Line{{OpEqual, "a"}, {OpInsert, "b"}},
+		},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "a",
+			want: []Line{{OpInsert, "a"}},
+		},
+		{
+			name: "moved line",
+			a:    "a\nb\nc\nd",
+			b:    "b\nc\na\nd",
+			want: []Line{{OpDelete, "a"}, {OpEqual, "b"}, {OpEqual, "c"}, {OpInsert, "a"}, {OpEqual, "d"}},
+		},
+		{
+			name: "to empty",
+			a:    "a\r\nb",
+			b:    "",
+			want: []Line{{OpDelete, "a"}, {OpDelete, "b"}},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Lines(tt.a, tt.b))
+		})
+	}
+}
+
+// lcsLength is the plain quadratic longest common subsequence
+func lcsLength(x, y []string) int {
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	return lcs[0][0]
+}
+
+func TestLines_random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	text := func() string {
+		lines := make([]string, rnd.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + rnd.Intn(4)))
+		}
+		return strings.Join(lines, "\n")
+	}
+	for n := 0; n < 500; n++ {
+		a, b := text(), text()
+		var gotA, gotB []string
+		equal := 0
+		for _, line := range Lines(a, b) {
+			if line.Op != OpInsert {
+				gotA = append(gotA, line.Text)
+			}
+			if line.Op != OpDelete {
+				gotB = append(gotB, line.Text)
+			}
+			if line.Op == OpEqual {
+				equal++
+			}
+		}
+		if !assert.Equal(t, split(a), gotA) || !assert.Equal(t, split(b), gotB) ||
+			!assert.Equal(t, lcsLength(split(a), split(b)), equal, "script is not the shortest: %q %q", a, b) {
+			return
+		}
+	}
+}
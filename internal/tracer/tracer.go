@@ -5,6 +5,7 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
@@ -27,5 +28,7 @@ func SetupTracer() (*trace.TracerProvider, error) {
 		)),
 	)
 	otel.SetTracerProvider(tp)
+	// trace context travels with outbox events to webhook deliveries
+	otel.SetTextMapPropagator(propagation.TraceContext{})
 	return tp, nil
 }
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/repository"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// API key looks like bk_<prefix>_<secret>, prefix is stored as is to find the key,
+// secret is stored as salted hash
+const (
+	apiKeyPrefix      = "bk_"
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 32
+	apiKeySaltBytes   = 16
+)
+
+type APIKeyProvider struct {
+	repository repository.APIKeyRepository
+}
+
+func NewAPIKeyProvider(repository repository.APIKeyRepository) *APIKeyProvider {
+	return &APIKeyProvider{
+		repository: repository,
+	}
+}
+
+// AddAPIKey creates key of the caller. Keys can not be created with an API key which is not admin,
+// otherwise a key could grant itself more scopes.
+func (p *APIKeyProvider) AddAPIKey(ctx context.Context, req model.APIKeyPostReq) (model.APIKeyPostResp, error) {
+	userID, err := caller(ctx, auth.ScopeAdmin)
+	if err != nil {
+		return model.APIKeyPostResp{}, errors.Wrap(err, "usercase.APIKeyProvider.AddAPIKey")
+	}
+	prefix, err := randomHex(apiKeyPrefixBytes)
+	if err != nil {
+		return model.APIKeyPostResp{}, errors.Wrap(err, "usercase.APIKeyProvider.AddAPIKey")
+	}
+	secret, err := randomHex(apiKeySecretBytes)
+	if err != nil {
+		return model.APIKeyPostResp{}, errors.Wrap(err, "usercase.APIKeyProvider.AddAPIKey")
+	}
+	salt := make([]byte, apiKeySaltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return model.APIKeyPostResp{}, errors.Wrap(err, "usercase.APIKeyProvider.AddAPIKey")
+	}
+	id, _ := uuid.NewRandom()
+	key := model.DbAPIKey{
+		ID:        id,
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		Salt:      salt,
+		Hash:      hashAPIKeySecret(salt, secret),
+		Scopes:    req.Scopes,
+		CreatedAt: time.Now(),
+	}
+	keyID, err := p.repository.AddAPIKey(ctx, key)
+	if err != nil {
+		return model.APIKeyPostResp{}, errors.Wrap(err, "usercase.APIKeyProvider.AddAPIKey")
+	}
+	return model.APIKeyPostResp{
+		KeyID:     keyID,
+		Key:       apiKeyPrefix + prefix + "_" + secret,
+		Prefix:    prefix,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt,
+	}, nil
+}
+
+func (p *APIKeyProvider) GetAPIKeys(ctx context.Context) (model.APIKeysGetResp, error) {
+	userID, err := caller(ctx, auth.ScopeAdmin)
+	if err != nil {
+		return model.APIKeysGetResp{}, errors.Wrap(err, "usercase.APIKeyProvider.GetAPIKeys")
+	}
+	keys, err := p.repository.GetAPIKeys(ctx, userID)
+	if err != nil {
+		return model.APIKeysGetResp{}, errors.Wrap(err, "usercase.APIKeyProvider.GetAPIKeys")
+	}
+	resp := make([]model.APIKeyResp, 0, len(keys))
+	for i := 0; i < len(keys); i++ {
+		resp = append(resp, model.APIKeyResp{
+			KeyID:     keys[i].ID,
+			Name:      keys[i].Name,
+			Prefix:    keys[i].Prefix,
+			Scopes:    keys[i].Scopes,
+			CreatedAt: keys[i].CreatedAt,
+		})
+	}
+	return model.APIKeysGetResp{Keys: resp}, nil
+}
+
+func (p *APIKeyProvider) RevokeAPIKey(ctx context.Context, req model.APIKeyDeleteReq) error {
+	userID, err := caller(ctx, auth.ScopeAdmin)
+	if err != nil {
+		return errors.Wrap(err, "usercase.APIKeyProvider.RevokeAPIKey")
+	}
+	if err := p.repository.RevokeAPIKey(ctx, req.KeyID, userID); err != nil {
+		return errors.Wrap(err, "usercase.APIKeyProvider.RevokeAPIKey")
+	}
+	return nil
+}
+
+// AuthenticateAPIKey returns owner and scopes of the key, unknown and revoked keys are ErrUnauthorized
+func (p *APIKeyProvider) AuthenticateAPIKey(ctx context.Context, key string) (model.APIKeyAuthResp, error) {
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(key, apiKeyPrefix), "_")
+	if !ok || !strings.HasPrefix(key, apiKeyPrefix) {
+		return model.APIKeyAuthResp{}, apperror.ErrUnauthorized
+	}
+	dbKey, err := p.repository.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return model.APIKeyAuthResp{}, apperror.ErrUnauthorized
+		}
+		return model.APIKeyAuthResp{}, errors.Wrap(err, "usercase.APIKeyProvider.AuthenticateAPIKey")
+	}
+	if subtle.ConstantTimeCompare(hashAPIKeySecret(dbKey.Salt, secret), dbKey.Hash) != 1 {
+		return model.APIKeyAuthResp{}, apperror.ErrUnauthorized
+	}
+	return model.APIKeyAuthResp{UserID: dbKey.UserID, Scopes: dbKey.Scopes}, nil
+}
+
+func hashAPIKeySecret(salt []byte, secret string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(secret))
+	return h.Sum(nil)
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
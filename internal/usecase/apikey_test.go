@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPIKeyProvider_AuthenticateAPIKey(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockAPIKeyRepository(ctrl)
+	provider := NewAPIKeyProvider(repository)
+
+	userID := uuid.New()
+	ctx := auth.WithUserID(context.Background(), userID)
+
+	var stored model.DbAPIKey
+	repository.EXPECT().AddAPIKey(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key model.DbAPIKey) (uuid.UUID, error) {
+		stored = key
+		return key.ID, nil
+	})
+	created, err := provider.AddAPIKey(ctx, model.APIKeyPostReq{Name: "ci", Scopes: []string{auth.ScopeWritePosts}})
+	a.NoError(err)
+	a.Len(stored.Hash, 32)
+	a.Equal(created.Prefix, stored.Prefix)
+
+	repository.EXPECT().GetAPIKeyByPrefix(gomock.Any(), created.Prefix).Return(stored, nil).Times(2)
+	repository.EXPECT().GetAPIKeyByPrefix(gomock.Any(), "unknown").Return(model.DbAPIKey{}, apperror.ErrNotFound)
+
+	testCases := []struct {
+		name    string
+		key     string
+		wantErr error
+	}{
+		{"valid", created.Key, nil},
+		{"wrong secret", apiKeyPrefix + created.Prefix + "_" + "00", apperror.ErrUnauthorized},
+		{"unknown prefix", apiKeyPrefix + "unknown_00", apperror.ErrUnauthorized},
+		{"malformed", "garbage", apperror.ErrUnauthorized},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := provider.AuthenticateAPIKey(context.Background(), tc.key)
+			if tc.wantErr != nil {
+				a.ErrorIs(err, tc.wantErr)
+				return
+			}
+			a.NoError(err)
+			a.Equal(userID, resp.UserID)
+			a.Equal([]string{auth.ScopeWritePosts}, resp.Scopes)
+		})
+	}
+
+	// key without admin scope can not create keys
+	_, err = provider.AddAPIKey(auth.WithScopes(ctx, []string{auth.ScopeWritePosts}), model.APIKeyPostReq{Name: "ci", Scopes: []string{auth.ScopeAdmin}})
+	a.ErrorIs(err, apperror.ErrForbidden)
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// caller returns id of the authenticated user allowed to act within scope.
+// Anonymous caller gets ErrUnauthorized, API key without the scope gets ErrForbidden.
+func caller(ctx context.Context, scope string) (uuid.UUID, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return uuid.Nil, apperror.ErrUnauthorized
+	}
+	if !auth.HasScope(ctx, scope) {
+		return uuid.Nil, apperror.ErrForbidden
+	}
+	return userID, nil
+}
+
+// authorize checks that the caller owns the blog
+func (b *BlogProvider) authorize(ctx context.Context, blogID uuid.UUID, scope string) error {
+	userID, err := caller(ctx, scope)
+	if err != nil {
+		return err
+	}
+	owner, err := b.repository.GetBlogOwner(ctx, blogID)
+	if err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.authorize")
+	}
+	if owner != userID {
+		return apperror.ErrForbidden
+	}
+	return nil
+}
+
+// isOwner tells whether the caller owns the blog. Anonymous callers and API keys without read scope are not owners.
+func (b *BlogProvider) isOwner(ctx context.Context, blogID uuid.UUID) (bool, error) {
+	userID, err := caller(ctx, auth.ScopeRead)
+	if err != nil {
+		return false, nil
+	}
+	owner, err := b.repository.GetBlogOwner(ctx, blogID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "usercase.BlogProvider.isOwner")
+	}
+	return owner == userID, nil
+}
+
+// checkVisible hides drafts and scheduled posts from everyone but the blog owner, as if they did not exist
+func (b *BlogProvider) checkVisible(ctx context.Context, post model.DbPost) error {
+	if post.Status == model.PostStatusPublished {
+		return nil
+	}
+	owner, err := b.isOwner(ctx, post.BlogID)
+	if err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.checkVisible")
+	}
+	if !owner {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+// checkPostVisible checks that the post belongs to the blog and the caller may see it
+func (b *BlogProvider) checkPostVisible(ctx context.Context, blogID uuid.UUID, postID uuid.UUID) error {
+	post, err := b.repository.GetPost(ctx, postID)
+	if err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.checkPostVisible")
+	}
+	if post.BlogID != blogID {
+		return apperror.ErrNotFound
+	}
+	return b.checkVisible(ctx, post)
+}
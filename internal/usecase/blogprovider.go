@@ -4,20 +4,35 @@ import (
 	"context"
 	"time"
 
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/broadcast"
 	"github.com/Rolan335/project/internal/model"
 	"github.com/Rolan335/project/internal/repository"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
 	"go.opentelemetry.io/otel"
 )
 
+// DefaultPostsLimit is a page size of GetPosts when limit is not set
+const DefaultPostsLimit = 20
+
 type BlogProvider struct {
 	repository repository.BlogRepository
+	// events gets changes of posts for stream subscribers
+	events *broadcast.Broadcaster
+	// presence relays what users are doing with posts, it is not kept for replay
+	presence *broadcast.Broadcaster
+	// html keeps rendered text of posts, posts are rendered on every request when it is nil
+	html HTMLCache
 }
 
-func NewBlogProvider(repository repository.BlogRepository) *BlogProvider {
+func NewBlogProvider(repository repository.BlogRepository, events *broadcast.Broadcaster, html HTMLCache) *BlogProvider {
 	return &BlogProvider{
 		repository: repository,
+		events:     events,
+		presence:   broadcast.New(0),
+		html:       html,
 	}
 }
 
@@ -34,32 +49,42 @@ func (b *BlogProvider) GetBlog(ctx context.Context, req model.BlogGetReq) (model
 		BlogID:    blogDB.ID,
 		UserID:    blogDB.UserID,
 		Name:      blogDB.Name,
+		Slug:      blogDB.Slug,
 		CreatedAt: blogDB.CreatedAt,
+		Version:   blogDB.Version,
 	}, nil
 }
 func (b *BlogProvider) AddBlog(ctx context.Context, req model.BlogPostReq) (model.BlogPostResp, error) {
+	userID, err := caller(ctx, auth.ScopeAdmin)
+	if err != nil {
+		return model.BlogPostResp{}, errors.Wrap(err, "usercase.BlogProvider.AddBlog")
+	}
 	id, _ := uuid.NewRandom()
 	blogDB := model.DbBlog{
 		ID:        id,
-		UserID:    req.UserID,
+		UserID:    userID,
 		Name:      req.Name,
 		CreatedAt: time.Now(),
+		Version:   1,
 	}
 
-	blogid, err := b.repository.AddBlog(ctx, blogDB)
+	blog, err := b.repository.AddBlog(ctx, blogDB)
 	if err != nil {
 		return model.BlogPostResp{}, errors.Wrap(err, "usercase.BlogProvider.AddBlog")
 	}
 
-	return model.BlogPostResp{BlogID: blogid}, nil
+	return model.BlogPostResp{BlogID: blog.ID, Slug: blog.Slug}, nil
 }
 
 func (b *BlogProvider) UpdateBlog(ctx context.Context, req model.BlogPutReq) (model.BlogPutResp, error) {
-	// Обновляет userID, Name
+	if err := b.authorize(ctx, req.BlogID, auth.ScopeAdmin); err != nil {
+		return model.BlogPutResp{}, errors.Wrap(err, "usercase.BlogProvider.UpdateBlog")
+	}
+	// Обновляет Name, владельца блога сменить нельзя
 	blogDB := model.DbBlog{
-		ID:     req.BlogID,
-		UserID: req.UserID,
-		Name:   req.Name,
+		ID:      req.BlogID,
+		Name:    req.Name,
+		Version: req.Version,
 	}
 	blog, err := b.repository.UpdateBlog(ctx, blogDB)
 	if err != nil {
@@ -70,82 +95,359 @@ func (b *BlogProvider) UpdateBlog(ctx context.Context, req model.BlogPutReq) (mo
 		BlogID:    blog.ID,
 		UserID:    blog.UserID,
 		Name:      blog.Name,
+		Slug:      blog.Slug,
 		CreatedAt: blog.CreatedAt,
+		Version:   blog.Version,
 	}, nil
 }
 func (b *BlogProvider) DeleteBlog(ctx context.Context, req model.BlogDeleteReq) error {
+	if err := b.authorize(ctx, req.BlogID, auth.ScopeAdmin); err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.DeleteBlog")
+	}
 	if err := b.repository.DeleteBlog(ctx, req.BlogID); err != nil {
 		return errors.Wrap(err, "usercase.BlogProvider.DeleteBlog")
 	}
 	return nil
 }
+
+// GetBlogsTrash lists deleted blogs of the caller
+func (b *BlogProvider) GetBlogsTrash(ctx context.Context, _ model.BlogsTrashGetReq) (model.BlogsTrashGetResp, error) {
+	userID, err := caller(ctx, auth.ScopeRead)
+	if err != nil {
+		return model.BlogsTrashGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetBlogsTrash")
+	}
+	blogs, err := b.repository.GetDeletedBlogs(ctx, userID)
+	if err != nil {
+		return model.BlogsTrashGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetBlogsTrash")
+	}
+	resp := make([]model.BlogGetResp, 0, len(blogs))
+	for i := 0; i < len(blogs); i++ {
+		resp = append(resp, model.BlogGetResp{
+			BlogID:    blogs[i].ID,
+			UserID:    blogs[i].UserID,
+			Name:      blogs[i].Name,
+			Slug:      blogs[i].Slug,
+			CreatedAt: blogs[i].CreatedAt,
+			DeletedAt: blogs[i].DeletedAt,
+			Version:   blogs[i].Version,
+		})
+	}
+	return model.BlogsTrashGetResp{Blogs: resp}, nil
+}
+func (b *BlogProvider) RestoreBlog(ctx context.Context, req model.BlogRestoreReq) (model.BlogGetResp, error) {
+	if err := b.authorize(ctx, req.BlogID, auth.ScopeAdmin); err != nil {
+		return model.BlogGetResp{}, errors.Wrap(err, "usercase.BlogProvider.RestoreBlog")
+	}
+	blog, err := b.repository.RestoreBlog(ctx, req.BlogID)
+	if err != nil {
+		return model.BlogGetResp{}, errors.Wrap(err, "usercase.BlogProvider.RestoreBlog")
+	}
+	return model.BlogGetResp{
+		BlogID:    blog.ID,
+		UserID:    blog.UserID,
+		Name:      blog.Name,
+		Slug:      blog.Slug,
+		CreatedAt: blog.CreatedAt,
+		Version:   blog.Version,
+	}, nil
+}
 func (b *BlogProvider) GetPost(ctx context.Context, req model.PostGetReq) (model.PostGetResp, error) {
 	post, err := b.repository.GetPost(ctx, req.PostID)
 	if err != nil {
 		return model.PostGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPost")
 	}
+	if err := b.checkVisible(ctx, post); err != nil {
+		return model.PostGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPost")
+	}
+	var html string
+	if req.RenderHTML {
+		html, err = b.renderHTML(ctx, post)
+		if err != nil {
+			return model.PostGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPost")
+		}
+	}
 
 	return model.PostGetResp{
-		PostID:    post.ID,
-		BlogID:    post.BlogID,
-		Title:     post.Title,
-		Text:      post.Text,
-		CreatedAt: post.CreatedAt,
+		PostID:        post.ID,
+		BlogID:        post.BlogID,
+		Title:         post.Title,
+		Slug:          post.Slug,
+		Status:        post.Status,
+		PublishAt:     post.PublishAt,
+		Text:          post.Text,
+		HTML:          html,
+		CreatedAt:     post.CreatedAt,
+		Version:       post.Version,
+		Tags:          post.Tags,
+		CommentsCount: post.CommentsCount,
 	}, nil
 }
-func (b *BlogProvider) GetPosts(ctx context.Context, req model.PostsGetReq) ([]model.PostGetResp, error) {
-	posts, err := b.repository.GetPosts(ctx, req.BlogID)
+
+// GetPosts returns drafts and scheduled posts only to the blog owner
+func (b *BlogProvider) GetPosts(ctx context.Context, req model.PostsGetReq) (model.PostsGetResp, error) {
+	owner, err := b.isOwner(ctx, req.BlogID)
+	if err != nil {
+		return model.PostsGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPosts")
+	}
+	return b.getPosts(ctx, req, !owner)
+}
+
+func (b *BlogProvider) getPosts(ctx context.Context, req model.PostsGetReq, onlyPublished bool) (model.PostsGetResp, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultPostsLimit
+	}
+	// запрашиваем на один пост больше, чтобы понять, есть ли следующая страница
+	filter := model.DbPostsFilter{BlogID: req.BlogID, Limit: limit + 1, Tag: normalizeTag(req.Tag), OnlyPublished: onlyPublished}
+	if req.Cursor != "" {
+		after, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return model.PostsGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPosts")
+		}
+		filter.After = &after
+	}
+	posts, err := b.repository.GetPosts(ctx, filter)
 	if err != nil {
-		return nil, errors.Wrap(err, "usercase.BlogProvider.GetPosts")
+		return model.PostsGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPosts")
 	}
+	posts, nextCursor := postsPage(posts, limit)
 	resp := make([]model.PostGetResp, 0, len(posts))
 	for i := 0; i < len(posts); i++ {
+		var html string
+		if req.RenderHTML {
+			html, err = b.renderHTML(ctx, posts[i])
+			if err != nil {
+				return model.PostsGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPosts")
+			}
+		}
 		resp = append(resp, model.PostGetResp{
-			PostID:    posts[i].ID,
-			BlogID:    posts[i].BlogID,
-			Title:     posts[i].Title,
-			Text:      posts[i].Text,
-			CreatedAt: posts[i].CreatedAt,
+			PostID:        posts[i].ID,
+			BlogID:        posts[i].BlogID,
+			Title:         posts[i].Title,
+			Slug:          posts[i].Slug,
+			Status:        posts[i].Status,
+			PublishAt:     posts[i].PublishAt,
+			Text:          posts[i].Text,
+			HTML:          html,
+			CreatedAt:     posts[i].CreatedAt,
+			Version:       posts[i].Version,
+			Tags:          posts[i].Tags,
+			CommentsCount: posts[i].CommentsCount,
 		})
 	}
+	return model.PostsGetResp{Posts: resp, NextCursor: nextCursor}, nil
+}
+
+// GetBlogsPosts gets the first page of posts of each blog with one query, posts are visible as in GetPosts
+func (b *BlogProvider) GetBlogsPosts(ctx context.Context, req model.BlogsPostsGetReq) (model.BlogsPostsGetResp, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultPostsLimit
+	}
+	filter := model.DbBlogsPostsFilter{BlogIDs: req.BlogIDs, Limit: limit + 1, Tag: normalizeTag(req.Tag)}
+	if userID, err := caller(ctx, auth.ScopeRead); err == nil {
+		filter.ViewerID = &userID
+	}
+	posts, err := b.repository.GetBlogsPosts(ctx, filter)
+	if err != nil {
+		return model.BlogsPostsGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetBlogsPosts")
+	}
+	byBlog := make(map[uuid.UUID][]model.DbPost, len(req.BlogIDs))
+	for _, post := range posts {
+		byBlog[post.BlogID] = append(byBlog[post.BlogID], post)
+	}
+	resp := model.BlogsPostsGetResp{Posts: make(map[uuid.UUID]model.PostsGetResp, len(req.BlogIDs))}
+	for _, blogID := range req.BlogIDs {
+		page, nextCursor := postsPage(byBlog[blogID], limit)
+		postsResp := make([]model.PostGetResp, 0, len(page))
+		for _, post := range page {
+			postsResp = append(postsResp, model.PostGetResp{
+				PostID:        post.ID,
+				BlogID:        post.BlogID,
+				Title:         post.Title,
+				Slug:          post.Slug,
+				Status:        post.Status,
+				PublishAt:     post.PublishAt,
+				Text:          post.Text,
+				CreatedAt:     post.CreatedAt,
+				Version:       post.Version,
+				Tags:          post.Tags,
+				CommentsCount: post.CommentsCount,
+			})
+		}
+		resp.Posts[blogID] = model.PostsGetResp{Posts: postsResp, NextCursor: nextCursor}
+	}
 	return resp, nil
 }
+
+// postsPage cuts posts fetched with limit+1 to limit and returns cursor of the next page, if there is one
+func postsPage(posts []model.DbPost, limit int) ([]model.DbPost, string) {
+	if len(posts) <= limit {
+		return posts, ""
+	}
+	posts = posts[:limit]
+	last := posts[len(posts)-1]
+	return posts, encodeCursor(model.DbCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+}
+
+func (b *BlogProvider) SearchPosts(ctx context.Context, req model.PostsSearchReq) (model.PostsSearchResp, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultPostsLimit
+	}
+	found, err := b.repository.SearchPosts(ctx, model.DbPostsSearch{Query: req.Query, BlogID: req.BlogID, Limit: limit})
+	if err != nil {
+		return model.PostsSearchResp{}, errors.Wrap(err, "usercase.BlogProvider.SearchPosts")
+	}
+	resp := make([]model.PostSearchResp, 0, len(found))
+	for i := 0; i < len(found); i++ {
+		resp = append(resp, model.PostSearchResp{
+			PostID:    found[i].ID,
+			BlogID:    found[i].BlogID,
+			Title:     found[i].Title,
+			Slug:      found[i].Slug,
+			Snippet:   found[i].Snippet,
+			Rank:      found[i].Rank,
+			CreatedAt: found[i].CreatedAt,
+		})
+	}
+	return model.PostsSearchResp{Posts: resp}, nil
+}
 func (b *BlogProvider) AddPost(ctx context.Context, req model.PostPostReq) (model.PostPostResp, error) {
+	if err := b.authorize(ctx, req.BlogID, auth.ScopeWritePosts); err != nil {
+		return model.PostPostResp{}, errors.Wrap(err, "usercase.BlogProvider.AddPost")
+	}
 	dbPost := model.DbPost{
 		BlogID: req.BlogID,
 		Title:  req.Title,
 		Text:   req.Text,
+		Tags:   normalizeTags(req.Tags),
 	}
 	dbPost.ID, _ = uuid.NewRandom()
 	dbPost.CreatedAt = time.Now()
-	postID, err := b.repository.AddPost(ctx, dbPost)
+	dbPost.Version = 1
+	dbPost.Status, dbPost.PublishAt = postState(req.Status, req.PublishAt, dbPost.CreatedAt)
+	post, err := b.repository.AddPost(ctx, dbPost)
 	if err != nil {
 		return model.PostPostResp{}, errors.Wrap(err, "usercase.BlogProvider.AddPost")
 	}
-	return model.PostPostResp{PostID: postID}, nil
+	b.publishPost(model.EventPostCreated, post)
+	return model.PostPostResp{PostID: post.ID, Slug: post.Slug, Status: post.Status}, nil
 }
 func (b *BlogProvider) UpdatePost(ctx context.Context, req model.PostPutReq) (model.PostPutResp, error) {
+	if err := b.authorize(ctx, req.BlogID, auth.ScopeWritePosts); err != nil {
+		return model.PostPutResp{}, errors.Wrap(err, "usercase.BlogProvider.UpdatePost")
+	}
 	dbPost := model.DbPost{
-		ID:     req.PostID,
-		BlogID: req.BlogID,
-		Title:  req.Title,
-		Text:   req.Text,
+		ID:      req.PostID,
+		BlogID:  req.BlogID,
+		Title:   req.Title,
+		Text:    req.Text,
+		Tags:    normalizeTags(req.Tags),
+		Version: req.Version,
+	}
+	if req.Status != "" {
+		dbPost.Status, dbPost.PublishAt = postState(req.Status, req.PublishAt, time.Now())
 	}
 	post, err := b.repository.UpdatePost(ctx, dbPost)
 	if err != nil {
 		return model.PostPutResp{}, errors.Wrap(err, "usercase.BlogProvider.UpdatePost")
 	}
+	b.publishPost(model.EventPostUpdated, post)
 	return model.PostPutResp{
 		PostID:    post.ID,
 		BlogID:    post.BlogID,
 		Title:     post.Title,
+		Slug:      post.Slug,
+		Status:    post.Status,
+		PublishAt: post.PublishAt,
 		Text:      post.Text,
 		CreatedAt: post.CreatedAt,
+		Version:   post.Version,
+		Tags:      post.Tags,
 	}, nil
 }
 func (b *BlogProvider) DeletePost(ctx context.Context, req model.PostDeleteReq) error {
+	if err := b.authorize(ctx, req.BlogID, auth.ScopeWritePosts); err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.DeletePost")
+	}
+	// status of the post tells who may know about the deletion
+	post, err := b.repository.GetPost(ctx, req.PostID)
+	if err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.DeletePost")
+	}
 	if err := b.repository.DeletePost(ctx, req.PostID, req.BlogID); err != nil {
 		return errors.Wrap(err, "usercase.BlogProvider.DeletePost")
 	}
+	b.publishPostDeleted(post)
 	return nil
 }
+
+// GetPostsTrash lists deleted posts of the blog, only the blog owner sees them
+func (b *BlogProvider) GetPostsTrash(ctx context.Context, req model.PostsTrashGetReq) (model.PostsTrashGetResp, error) {
+	if err := b.authorize(ctx, req.BlogID, auth.ScopeRead); err != nil {
+		return model.PostsTrashGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPostsTrash")
+	}
+	posts, err := b.repository.GetDeletedPosts(ctx, req.BlogID)
+	if err != nil {
+		return model.PostsTrashGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPostsTrash")
+	}
+	resp := make([]model.PostGetResp, 0, len(posts))
+	for i := 0; i < len(posts); i++ {
+		resp = append(resp, model.PostGetResp{
+			PostID:    posts[i].ID,
+			BlogID:    posts[i].BlogID,
+			Title:     posts[i].Title,
+			Slug:      posts[i].Slug,
+			Status:    posts[i].Status,
+			PublishAt: posts[i].PublishAt,
+			Text:      posts[i].Text,
+			CreatedAt: posts[i].CreatedAt,
+			DeletedAt: posts[i].DeletedAt,
+			Version:   posts[i].Version,
+			Tags:      posts[i].Tags,
+		})
+	}
+	return model.PostsTrashGetResp{Posts: resp}, nil
+}
+func (b *BlogProvider) RestorePost(ctx context.Context, req model.PostRestoreReq) (model.PostGetResp, error) {
+	if err := b.authorize(ctx, req.BlogID, auth.ScopeWritePosts); err != nil {
+		return model.PostGetResp{}, errors.Wrap(err, "usercase.BlogProvider.RestorePost")
+	}
+	post, err := b.repository.RestorePost(ctx, req.PostID, req.BlogID)
+	if err != nil {
+		return model.PostGetResp{}, errors.Wrap(err, "usercase.BlogProvider.RestorePost")
+	}
+	b.publishPost(model.EventPostCreated, post)
+	return model.PostGetResp{
+		PostID:    post.ID,
+		BlogID:    post.BlogID,
+		Title:     post.Title,
+		Slug:      post.Slug,
+		Status:    post.Status,
+		PublishAt: post.PublishAt,
+		Text:      post.Text,
+		CreatedAt: post.CreatedAt,
+		Version:   post.Version,
+		Tags:      post.Tags,
+	}, nil
+}
+
+// GoPurgeTrash periodically removes blogs and posts which have been in trash longer than purgeAfter
+func (b *BlogProvider) GoPurgeTrash(ctx context.Context, interval time.Duration, purgeAfter time.Duration) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+				purged, err := b.repository.PurgeDeleted(ctx, purgeAfter)
+				if err != nil {
+					log.Err(err).Msg("usercase.BlogProvider.GoPurgeTrash")
+					continue
+				}
+				log.Debug().Int64("purged", purged).Msg("trash purged")
+			}
+		}
+	}()
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/broadcast"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBlogProvider_GetBlogsPosts(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockBlogRepository(ctrl)
+	provider := NewBlogProvider(repository, broadcast.New(16), nil)
+
+	userID := uuid.New()
+	first, second, empty := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+	posts := []model.DbPost{
+		{ID: uuid.New(), BlogID: first, Title: "3", CreatedAt: now},
+		{ID: uuid.New(), BlogID: first, Title: "2", CreatedAt: now.Add(-time.Minute)},
+		{ID: uuid.New(), BlogID: first, Title: "1", CreatedAt: now.Add(-2 * time.Minute)},
+		{ID: uuid.New(), BlogID: second, Title: "1", CreatedAt: now},
+	}
+	// one more post per blog is asked to know whether there is a next page
+	repository.EXPECT().GetBlogsPosts(gomock.Any(), model.DbBlogsPostsFilter{
+		BlogIDs:  []uuid.UUID{first, second, empty},
+		Limit:    3,
+		Tag:      "go",
+		ViewerID: &userID,
+	}).Return(posts, nil)
+
+	resp, err := provider.GetBlogsPosts(auth.WithUserID(context.Background(), userID), model.BlogsPostsGetReq{
+		BlogIDs: []uuid.UUID{first, second, empty},
+		Limit:   2,
+		Tag:     "Go",
+	})
+	a.NoError(err)
+	a.Len(resp.Posts, 3)
+	a.Len(resp.Posts[first].Posts, 2)
+	a.Equal(encodeCursor(model.DbCursor{CreatedAt: posts[1].CreatedAt, ID: posts[1].ID}), resp.Posts[first].NextCursor)
+	a.Len(resp.Posts[second].Posts, 1)
+	a.Empty(resp.Posts[second].NextCursor)
+	a.NotNil(resp.Posts[empty].Posts)
+	a.Empty(resp.Posts[empty].Posts)
+}
+
+func TestBlogProvider_trash(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockBlogRepository(ctrl)
+	provider := NewBlogProvider(repository, broadcast.New(16), nil)
+
+	owner, blogID := uuid.New(), uuid.New()
+	ownerCtx := auth.WithUserID(context.Background(), owner)
+	repository.EXPECT().GetBlogOwner(gomock.Any(), blogID).Return(owner, nil).AnyTimes()
+
+	_, err := provider.GetBlogsTrash(context.Background(), model.BlogsTrashGetReq{})
+	a.ErrorIs(err, apperror.ErrUnauthorized)
+
+	repository.EXPECT().GetDeletedBlogs(gomock.Any(), owner).Return([]model.DbBlog{{ID: blogID, UserID: owner}}, nil)
+	blogs, err := provider.GetBlogsTrash(ownerCtx, model.BlogsTrashGetReq{})
+	a.NoError(err)
+	a.Len(blogs.Blogs, 1)
+
+	_, err = provider.GetPostsTrash(context.Background(), model.PostsTrashGetReq{BlogID: blogID})
+	a.ErrorIs(err, apperror.ErrUnauthorized)
+	_, err = provider.GetPostsTrash(auth.WithUserID(context.Background(), uuid.New()), model.PostsTrashGetReq{BlogID: blogID})
+	a.ErrorIs(err, apperror.ErrForbidden)
+
+	repository.EXPECT().GetDeletedPosts(gomock.Any(), blogID).Return([]model.DbPost{{ID: uuid.New(), BlogID: blogID}}, nil)
+	posts, err := provider.GetPostsTrash(ownerCtx, model.PostsTrashGetReq{BlogID: blogID})
+	a.NoError(err)
+	a.Len(posts.Posts, 1)
+}
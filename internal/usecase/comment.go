@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/repository"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type CommentProvider struct {
+	repository repository.CommentRepository
+}
+
+func NewCommentProvider(repository repository.CommentRepository) *CommentProvider {
+	return &CommentProvider{
+		repository: repository,
+	}
+}
+
+// AddComment comments the post on behalf of the caller
+func (p *CommentProvider) AddComment(ctx context.Context, req model.CommentPostReq) (model.CommentPostResp, error) {
+	userID, err := caller(ctx, auth.ScopeWritePosts)
+	if err != nil {
+		return model.CommentPostResp{}, errors.Wrap(err, "usercase.CommentProvider.AddComment")
+	}
+	if err := p.checkVisible(ctx, req.PostID, req.BlogID); err != nil {
+		return model.CommentPostResp{}, errors.Wrap(err, "usercase.CommentProvider.AddComment")
+	}
+	id, _ := uuid.NewRandom()
+	comment := model.DbComment{
+		ID:        id,
+		PostID:    req.PostID,
+		ParentID:  req.ParentID,
+		UserID:    userID,
+		Text:      req.Text,
+		CreatedAt: time.Now(),
+	}
+	commentID, err := p.repository.AddComment(ctx, req.BlogID, comment)
+	if err != nil {
+		return model.CommentPostResp{}, errors.Wrap(err, "usercase.CommentProvider.AddComment")
+	}
+	return model.CommentPostResp{CommentID: commentID}, nil
+}
+
+func (p *CommentProvider) GetComments(ctx context.Context, req model.CommentsGetReq) (model.CommentsGetResp, error) {
+	if err := p.checkVisible(ctx, req.PostID, req.BlogID); err != nil {
+		return model.CommentsGetResp{}, errors.Wrap(err, "usercase.CommentProvider.GetComments")
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultPostsLimit
+	}
+	filter := model.DbCommentsFilter{BlogID: req.BlogID, PostID: req.PostID, ParentID: req.ParentID, Limit: limit + 1}
+	if req.Cursor != "" {
+		after, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return model.CommentsGetResp{}, errors.Wrap(err, "usercase.CommentProvider.GetComments")
+		}
+		filter.After = &after
+	}
+	comments, err := p.repository.GetComments(ctx, filter)
+	if err != nil {
+		return model.CommentsGetResp{}, errors.Wrap(err, "usercase.CommentProvider.GetComments")
+	}
+	var nextCursor string
+	if len(comments) > limit {
+		comments = comments[:limit]
+		last := comments[len(comments)-1]
+		nextCursor = encodeCursor(model.DbCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	resp := make([]model.CommentGetResp, 0, len(comments))
+	for i := 0; i < len(comments); i++ {
+		resp = append(resp, model.CommentGetResp{
+			CommentID:    comments[i].ID,
+			PostID:       comments[i].PostID,
+			ParentID:     comments[i].ParentID,
+			UserID:       comments[i].UserID,
+			Text:         comments[i].Text,
+			RepliesCount: comments[i].RepliesCount,
+			CreatedAt:    comments[i].CreatedAt,
+			UpdatedAt:    comments[i].UpdatedAt,
+		})
+	}
+	return model.CommentsGetResp{Comments: resp, NextCursor: nextCursor}, nil
+}
+
+// UpdateComment changes text of the comment, only its author can do it
+func (p *CommentProvider) UpdateComment(ctx context.Context, req model.CommentPutReq) (model.CommentPutResp, error) {
+	userID, err := caller(ctx, auth.ScopeWritePosts)
+	if err != nil {
+		return model.CommentPutResp{}, errors.Wrap(err, "usercase.CommentProvider.UpdateComment")
+	}
+	owners, err := p.repository.GetCommentOwners(ctx, req.CommentID, req.PostID, req.BlogID)
+	if err != nil {
+		return model.CommentPutResp{}, errors.Wrap(err, "usercase.CommentProvider.UpdateComment")
+	}
+	if owners.AuthorID != userID {
+		return model.CommentPutResp{}, apperror.ErrForbidden
+	}
+	comment, err := p.repository.UpdateComment(ctx, req.BlogID, model.DbComment{
+		ID:     req.CommentID,
+		PostID: req.PostID,
+		Text:   req.Text,
+	})
+	if err != nil {
+		return model.CommentPutResp{}, errors.Wrap(err, "usercase.CommentProvider.UpdateComment")
+	}
+	return model.CommentPutResp{
+		CommentID: comment.ID,
+		PostID:    comment.PostID,
+		ParentID:  comment.ParentID,
+		UserID:    comment.UserID,
+		Text:      comment.Text,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}, nil
+}
+
+// DeleteComment deletes the comment with its replies, the author and the owner of the blog can do it
+func (p *CommentProvider) DeleteComment(ctx context.Context, req model.CommentDeleteReq) error {
+	userID, err := caller(ctx, auth.ScopeWritePosts)
+	if err != nil {
+		return errors.Wrap(err, "usercase.CommentProvider.DeleteComment")
+	}
+	owners, err := p.repository.GetCommentOwners(ctx, req.CommentID, req.PostID, req.BlogID)
+	if err != nil {
+		return errors.Wrap(err, "usercase.CommentProvider.DeleteComment")
+	}
+	if owners.AuthorID != userID && owners.BlogOwnerID != userID {
+		return apperror.ErrForbidden
+	}
+	if err := p.repository.DeleteComment(ctx, req.CommentID, req.PostID, req.BlogID); err != nil {
+		return errors.Wrap(err, "usercase.CommentProvider.DeleteComment")
+	}
+	return nil
+}
+
+// checkVisible hides drafts and scheduled posts from everyone except the blog owner, as BlogProvider.checkVisible does
+func (p *CommentProvider) checkVisible(ctx context.Context, postID uuid.UUID, blogID uuid.UUID) error {
+	access, err := p.repository.GetPostAccess(ctx, postID, blogID)
+	if err != nil {
+		return errors.Wrap(err, "usercase.CommentProvider.checkVisible")
+	}
+	if access.Status == model.PostStatusPublished {
+		return nil
+	}
+	if userID, err := caller(ctx, auth.ScopeRead); err != nil || userID != access.BlogOwnerID {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCommentProvider_AddComment(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockCommentRepository(ctrl)
+	provider := NewCommentProvider(repository)
+
+	req := model.CommentPostReq{BlogID: uuid.New(), PostID: uuid.New(), Text: "text"}
+	_, err := provider.AddComment(context.Background(), req)
+	a.ErrorIs(err, apperror.ErrUnauthorized)
+	_, err = provider.AddComment(auth.WithScopes(auth.WithUserID(context.Background(), uuid.New()), []string{auth.ScopeRead}), req)
+	a.ErrorIs(err, apperror.ErrForbidden)
+
+	// the author is the caller
+	userID := uuid.New()
+	repository.EXPECT().GetPostAccess(gomock.Any(), req.PostID, req.BlogID).
+		Return(model.DbPostAccess{Status: model.PostStatusPublished, BlogOwnerID: uuid.New()}, nil)
+	repository.EXPECT().AddComment(gomock.Any(), req.BlogID, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ uuid.UUID, comment model.DbComment) (uuid.UUID, error) {
+			a.Equal(userID, comment.UserID)
+			return comment.ID, nil
+		})
+	_, err = provider.AddComment(auth.WithUserID(context.Background(), userID), req)
+	a.NoError(err)
+}
+
+func TestCommentProvider_changes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockCommentRepository(ctrl)
+	provider := NewCommentProvider(repository)
+
+	author, blogOwner := uuid.New(), uuid.New()
+	blogID, postID, commentID := uuid.New(), uuid.New(), uuid.New()
+	repository.EXPECT().GetCommentOwners(gomock.Any(), commentID, postID, blogID).
+		Return(model.DbCommentOwners{AuthorID: author, BlogOwnerID: blogOwner}, nil).AnyTimes()
+	repository.EXPECT().UpdateComment(gomock.Any(), blogID, gomock.Any()).Return(model.DbComment{ID: commentID}, nil).AnyTimes()
+	repository.EXPECT().DeleteComment(gomock.Any(), commentID, postID, blogID).Return(nil).AnyTimes()
+
+	update := func(ctx context.Context) error {
+		_, err := provider.UpdateComment(ctx, model.CommentPutReq{BlogID: blogID, PostID: postID, CommentID: commentID, Text: "text"})
+		return err
+	}
+	remove := func(ctx context.Context) error {
+		return provider.DeleteComment(ctx, model.CommentDeleteReq{BlogID: blogID, PostID: postID, CommentID: commentID})
+	}
+	tests := []struct {
+		name   string
+		change func(context.Context) error
+		ctx    context.Context
+		err    error
+	}{
+		{"update by anonymous", update, context.Background(), apperror.ErrUnauthorized},
+		{"update by author", update, auth.WithUserID(context.Background(), author), nil},
+		{"update by blog owner", update, auth.WithUserID(context.Background(), blogOwner), apperror.ErrForbidden},
+		{"update by stranger", update, auth.WithUserID(context.Background(), uuid.New()), apperror.ErrForbidden},
+		{"delete by anonymous", remove, context.Background(), apperror.ErrUnauthorized},
+		{"delete by author", remove, auth.WithUserID(context.Background(), author), nil},
+		{"delete by blog owner", remove, auth.WithUserID(context.Background(), blogOwner), nil},
+		{"delete by stranger", remove, auth.WithUserID(context.Background(), uuid.New()), apperror.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.change(tt.ctx)
+			if tt.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestCommentProvider_draft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockCommentRepository(ctrl)
+	provider := NewCommentProvider(repository)
+
+	ownerID := uuid.New()
+	blogID, postID := uuid.New(), uuid.New()
+	repository.EXPECT().GetPostAccess(gomock.Any(), postID, blogID).
+		Return(model.DbPostAccess{Status: model.PostStatusDraft, BlogOwnerID: ownerID}, nil).AnyTimes()
+	repository.EXPECT().GetComments(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	repository.EXPECT().AddComment(gomock.Any(), blogID, gomock.Any()).Return(uuid.New(), nil).AnyTimes()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+	}{
+		{"owner", auth.WithUserID(context.Background(), ownerID), nil},
+		{"another user", auth.WithUserID(context.Background(), uuid.New()), apperror.ErrNotFound},
+		{"anonymous", context.Background(), apperror.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.GetComments(tt.ctx, model.CommentsGetReq{BlogID: blogID, PostID: postID})
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+			if tt.ctx == context.Background() {
+				return
+			}
+			_, err = provider.AddComment(tt.ctx, model.CommentPostReq{BlogID: blogID, PostID: postID, Text: "text"})
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
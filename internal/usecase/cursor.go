@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/google/uuid"
+)
+
+// cursor is opaque for clients, it is base64 encoded json of the last returned row keyset
+type cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        uuid.UUID `json:"i"`
+}
+
+func encodeCursor(c model.DbCursor) string {
+	// marshalling of time and uuid can't fail
+	data, _ := json.Marshal(cursor{CreatedAt: c.CreatedAt, ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (model.DbCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return model.DbCursor{}, apperror.ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == uuid.Nil || c.CreatedAt.IsZero() {
+		return model.DbCursor{}, apperror.ErrInvalidCursor
+	}
+	return model.DbCursor{CreatedAt: c.CreatedAt, ID: c.ID}, nil
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	a := assert.New(t)
+	in := model.DbCursor{CreatedAt: time.Now().UTC(), ID: uuid.New()}
+
+	got, err := decodeCursor(encodeCursor(in))
+	a.NoError(err)
+	a.True(in.CreatedAt.Equal(got.CreatedAt))
+	a.Equal(in.ID, got.ID)
+
+	for _, s := range []string{"not base64!", "e30", "bm90IGpzb24"} {
+		_, err := decodeCursor(s)
+		a.ErrorIs(err, apperror.ErrInvalidCursor, s)
+	}
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/broadcast"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// EventPostsReset tells stream subscribers to reload posts, it is sent instead of events they can not get:
+// events missed while disconnected or bulk changes such as import
+const EventPostsReset = "posts.reset"
+
+// EventPresence is the type of presence messages, data is PresenceEvent
+const EventPresence = "presence"
+
+// SubscribePostEvents subscribes to changes of posts of the blog. The owner gets changes of drafts
+// and scheduled posts, everyone else gets changes of published posts only.
+func (b *BlogProvider) SubscribePostEvents(ctx context.Context, req model.PostEventsReq) (*broadcast.Subscription, error) {
+	if _, err := b.repository.GetBlog(ctx, req.BlogID); err != nil {
+		return nil, errors.Wrap(err, "usercase.BlogProvider.SubscribePostEvents")
+	}
+	owner, err := b.isOwner(ctx, req.BlogID)
+	if err != nil {
+		return nil, errors.Wrap(err, "usercase.BlogProvider.SubscribePostEvents")
+	}
+	return b.events.Subscribe(req.BlogID, req.LastEventID, owner), nil
+}
+
+// SubscribePresence subscribes to presence of users in the blog
+func (b *BlogProvider) SubscribePresence(ctx context.Context, req model.PresenceSubscribeReq) (*broadcast.Subscription, error) {
+	if _, err := b.repository.GetBlog(ctx, req.BlogID); err != nil {
+		return nil, errors.Wrap(err, "usercase.BlogProvider.SubscribePresence")
+	}
+	return b.presence.Subscribe(req.BlogID, "", false), nil
+}
+
+// SendPresence relays presence of the caller to the other subscribers of sub, anonymous callers have no presence
+func (b *BlogProvider) SendPresence(ctx context.Context, sub *broadcast.Subscription, req model.PresencePostReq) error {
+	userID, err := caller(ctx, auth.ScopeRead)
+	if err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.SendPresence")
+	}
+	data, err := json.Marshal(model.PresenceEvent{
+		BlogID: req.BlogID,
+		PostID: req.PostID,
+		UserID: userID,
+		State:  req.State,
+	})
+	if err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.SendPresence")
+	}
+	sub.Publish(EventPresence, data, false)
+	return nil
+}
+
+func (b *BlogProvider) publishPost(eventType string, post model.DbPost) {
+	b.publish(post.BlogID, eventType, model.PostGetResp{
+		PostID:    post.ID,
+		BlogID:    post.BlogID,
+		Title:     post.Title,
+		Slug:      post.Slug,
+		Status:    post.Status,
+		PublishAt: post.PublishAt,
+		Text:      post.Text,
+		CreatedAt: post.CreatedAt,
+		Version:   post.Version,
+		Tags:      post.Tags,
+	}, post.Status != model.PostStatusPublished)
+}
+
+func (b *BlogProvider) publishPostDeleted(post model.DbPost) {
+	b.publish(post.BlogID, model.EventPostDeleted, model.PostDeletedEvent{
+		PostID: post.ID,
+		BlogID: post.BlogID,
+	}, post.Status != model.PostStatusPublished)
+}
+
+func (b *BlogProvider) publish(blogID uuid.UUID, eventType string, data any, private bool) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		log.Err(err).Msg("usercase.BlogProvider.publish")
+		return
+	}
+	b.events.Publish(blogID, eventType, raw, private)
+}
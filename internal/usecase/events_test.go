@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/broadcast"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBlogProvider_SubscribePostEvents(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockBlogRepository(ctrl)
+	provider := NewBlogProvider(repository, broadcast.New(16), nil)
+
+	ownerID := uuid.New()
+	blogID := uuid.New()
+	ownerCtx := auth.WithUserID(context.Background(), ownerID)
+	repository.EXPECT().GetBlog(gomock.Any(), blogID).Return(model.DbBlog{ID: blogID}, nil).AnyTimes()
+	repository.EXPECT().GetBlogOwner(gomock.Any(), blogID).Return(ownerID, nil).AnyTimes()
+	repository.EXPECT().AddPost(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, post model.DbPost) (model.DbPost, error) {
+		return post, nil
+	}).Times(2)
+
+	owner, err := provider.SubscribePostEvents(ownerCtx, model.PostEventsReq{BlogID: blogID})
+	a.NoError(err)
+	defer owner.Close()
+	anonymous, err := provider.SubscribePostEvents(context.Background(), model.PostEventsReq{BlogID: blogID})
+	a.NoError(err)
+	defer anonymous.Close()
+
+	_, err = provider.AddPost(ownerCtx, model.PostPostReq{BlogID: blogID, Title: "draft", Text: "text", Status: model.PostStatusDraft})
+	a.NoError(err)
+	_, err = provider.AddPost(ownerCtx, model.PostPostReq{BlogID: blogID, Title: "post", Text: "text"})
+	a.NoError(err)
+
+	// drafts are streamed to the owner only
+	a.Len(owner.Events, 2)
+	a.Len(anonymous.Events, 1)
+	e := <-anonymous.Events
+	a.Equal(model.EventPostCreated, e.Type)
+	a.Contains(string(e.Data), `"title":"post"`)
+}
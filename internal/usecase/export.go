@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	ExportFormatJSON     = "json"
+	ExportFormatMarkdown = "markdown"
+)
+
+// ExportBlog writes tar.gz with blog.json and one file per post into w. Posts are written as they are read
+// from the repository, so the archive is never kept in memory.
+func (b *BlogProvider) ExportBlog(ctx context.Context, req model.BlogExportReq, w io.Writer) error {
+	blog, err := b.GetBlog(ctx, model.BlogGetReq{BlogID: req.BlogID})
+	if err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.ExportBlog")
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(blog, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.ExportBlog")
+	}
+	if err := writeTarFile(tw, "blog.json", blog.CreatedAt, data); err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.ExportBlog")
+	}
+	// owner exports drafts too, everyone else gets what they can read
+	owner, err := b.isOwner(ctx, req.BlogID)
+	if err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.ExportBlog")
+	}
+	err = b.repository.StreamPosts(ctx, req.BlogID, func(post model.DbPost) error {
+		if !owner && post.Status != model.PostStatusPublished {
+			return nil
+		}
+		name, data, err := exportPost(post, req.Format)
+		if err != nil {
+			return err
+		}
+		return writeTarFile(tw, name, post.CreatedAt, data)
+	})
+	if err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.ExportBlog")
+	}
+
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.ExportBlog")
+	}
+	if err := gz.Close(); err != nil {
+		return errors.Wrap(err, "usercase.BlogProvider.ExportBlog")
+	}
+	return nil
+}
+
+func exportPost(post model.DbPost, format string) (string, []byte, error) {
+	if format == ExportFormatMarkdown {
+		return "posts/" + post.ID.String() + ".md", postMarkdown(post), nil
+	}
+	data, err := json.MarshalIndent(model.PostGetResp{
+		PostID:    post.ID,
+		BlogID:    post.BlogID,
+		Title:     post.Title,
+		Slug:      post.Slug,
+		Status:    post.Status,
+		PublishAt: post.PublishAt,
+		Text:      post.Text,
+		CreatedAt: post.CreatedAt,
+		Version:   post.Version,
+		Tags:      post.Tags,
+	}, "", "  ")
+	if err != nil {
+		return "", nil, err
+	}
+	return "posts/" + post.ID.String() + ".json", data, nil
+}
+
+// postMarkdown renders post text with YAML front matter. Strings are written as JSON,
+// which is valid YAML, so titles with quotes or colons need no special escaping.
+func postMarkdown(post model.DbPost) []byte {
+	var buf bytes.Buffer
+	title, _ := json.Marshal(post.Title)
+	buf.WriteString("---\n")
+	buf.WriteString("id: " + post.ID.String() + "\n")
+	buf.WriteString("title: " + string(title) + "\n")
+	buf.WriteString("created_at: " + post.CreatedAt.UTC().Format(time.RFC3339) + "\n")
+	if len(post.Tags) > 0 {
+		tags, _ := json.Marshal(post.Tags)
+		buf.WriteString("tags: " + string(tags) + "\n")
+	}
+	buf.WriteString("---\n\n")
+	buf.WriteString(post.Text)
+	buf.WriteString("\n")
+	return buf.Bytes()
+}
+
+func writeTarFile(tw *tar.Writer, name string, modTime time.Time, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
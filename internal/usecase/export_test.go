@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Rolan335/project/internal/broadcast"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBlogProvider_ExportBlog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockBlogRepository(ctrl)
+	provider := NewBlogProvider(repository, broadcast.New(16), nil)
+
+	blog := model.DbBlog{ID: uuid.New(), UserID: uuid.New(), Name: "blog", CreatedAt: time.Now(), Version: 1}
+	post := model.DbPost{
+		ID:        uuid.New(),
+		BlogID:    blog.ID,
+		Title:     `say "hi": there`,
+		Text:      "# hello",
+		CreatedAt: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC),
+		Tags:      []string{"go"},
+		Status:    model.PostStatusPublished,
+	}
+	// anonymous caller does not get drafts
+	draft := model.DbPost{ID: uuid.New(), BlogID: blog.ID, Title: "draft", Text: "draft", Status: model.PostStatusDraft}
+	repository.EXPECT().GetBlog(gomock.Any(), blog.ID).Return(blog, nil)
+	repository.EXPECT().StreamPosts(gomock.Any(), blog.ID, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ uuid.UUID, fn func(model.DbPost) error) error {
+			if err := fn(post); err != nil {
+				return err
+			}
+			return fn(draft)
+		})
+
+	var buf bytes.Buffer
+	err := provider.ExportBlog(context.Background(), model.BlogExportReq{BlogID: blog.ID, Format: ExportFormatMarkdown}, &buf)
+	require.NoError(t, err)
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = string(data)
+	}
+
+	assert.Contains(t, files["blog.json"], `"name": "blog"`)
+	assert.Equal(t, "---\n"+
+		"id: "+post.ID.String()+"\n"+
+		"title: \"say \\\"hi\\\": there\"\n"+
+		"created_at: 2025-04-01T10:00:00Z\n"+
+		"tags: [\"go\"]\n"+
+		"---\n\n# hello\n", files["posts/"+post.ID.String()+".md"])
+	assert.NotContains(t, files, "posts/"+draft.ID.String()+".md")
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/pkg/errors"
+)
+
+// DefaultFeedLimit is a number of posts in feed when limit is not set
+const DefaultFeedLimit = 20
+
+func (b *BlogProvider) GetFeed(ctx context.Context, req model.FeedGetReq) (model.FeedGetResp, error) {
+	blog, err := b.GetBlog(ctx, model.BlogGetReq{BlogID: req.BlogID})
+	if err != nil {
+		return model.FeedGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetFeed")
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultFeedLimit
+	}
+	// feed is cached for everyone, so the owner does not see drafts in it either
+	posts, err := b.getPosts(ctx, model.PostsGetReq{BlogID: req.BlogID, Limit: limit}, true)
+	if err != nil {
+		return model.FeedGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetFeed")
+	}
+	// scheduled post appears in feed when published, not when created
+	updated := blog.CreatedAt
+	for i := 0; i < len(posts.Posts); i++ {
+		if posts.Posts[i].CreatedAt.After(updated) {
+			updated = posts.Posts[i].CreatedAt
+		}
+		if at := posts.Posts[i].PublishAt; at != nil && at.After(updated) {
+			updated = *at
+		}
+	}
+	return model.FeedGetResp{Blog: blog, Posts: posts.Posts, Updated: updated}, nil
+}
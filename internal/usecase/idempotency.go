@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/repository"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type IdempotencyProvider struct {
+	repository repository.IdempotencyRepository
+	ttl        time.Duration
+}
+
+// NewIdempotencyProvider keeps responses for ttl, a key can be used again after that
+func NewIdempotencyProvider(repository repository.IdempotencyRepository, ttl time.Duration) *IdempotencyProvider {
+	return &IdempotencyProvider{
+		repository: repository,
+		ttl:        ttl,
+	}
+}
+
+// Begin claims key for request. It returns stored response when the request has been done already,
+// nil means the caller must handle the request and then call Finish or Abort.
+func (p *IdempotencyProvider) Begin(ctx context.Context, key string, requestHash []byte) (*model.IdempotentResp, error) {
+	record, claimed, err := p.repository.ClaimKey(ctx, key, requestHash, p.ttl)
+	if err != nil {
+		return nil, errors.Wrap(err, "usercase.IdempotencyProvider.Begin")
+	}
+	if claimed {
+		return nil, nil
+	}
+	if !bytes.Equal(record.RequestHash, requestHash) {
+		return nil, apperror.ErrIdempotencyKeyReused
+	}
+	if record.Status == 0 {
+		return nil, apperror.ErrRequestInProgress
+	}
+	return &model.IdempotentResp{
+		Status:      record.Status,
+		ContentType: record.ContentType,
+		Body:        record.Body,
+	}, nil
+}
+
+func (p *IdempotencyProvider) Finish(ctx context.Context, key string, resp model.IdempotentResp) error {
+	if err := p.repository.SaveResponse(ctx, key, resp.Status, resp.ContentType, resp.Body); err != nil {
+		return errors.Wrap(err, "usercase.IdempotencyProvider.Finish")
+	}
+	return nil
+}
+
+func (p *IdempotencyProvider) Abort(ctx context.Context, key string) error {
+	if err := p.repository.ReleaseKey(ctx, key); err != nil {
+		return errors.Wrap(err, "usercase.IdempotencyProvider.Abort")
+	}
+	return nil
+}
+
+// GoPurgeExpired periodically removes expired responses
+func (p *IdempotencyProvider) GoPurgeExpired(ctx context.Context, interval time.Duration) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+				purged, err := p.repository.DeleteExpired(ctx)
+				if err != nil {
+					log.Err(err).Msg("usercase.IdempotencyProvider.GoPurgeExpired")
+					continue
+				}
+				log.Debug().Int64("purged", purged).Msg("idempotency keys purged")
+			}
+		}
+	}()
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// importBatchSize is a number of rows sent by one COPY
+const importBatchSize = 500
+
+const importRejected = "post rejected by database"
+
+// ImportPosts inserts valid lines read by req.Next batch after batch, so the body is never held whole.
+// Atomic import runs in one transaction which is rolled back when Next fails. In best-effort mode
+// every batch is committed on its own and a failed batch is retried post by post, so that only
+// the broken lines are reported.
+func (b *BlogProvider) ImportPosts(ctx context.Context, req model.PostsImportReq) (model.PostsImportResp, error) {
+	if err := b.authorize(ctx, req.BlogID, auth.ScopeWritePosts); err != nil {
+		return model.PostsImportResp{}, errors.Wrap(err, "usercase.BlogProvider.ImportPosts")
+	}
+
+	if req.Atomic {
+		imported := 0
+		err := b.repository.ImportPosts(ctx, req.BlogID, func() ([]model.DbPost, error) {
+			lines, err := req.Next(importBatchSize)
+			if err != nil {
+				return nil, err
+			}
+			imported += len(lines)
+			return importedPosts(req.BlogID, lines), nil
+		})
+		if err != nil {
+			return model.PostsImportResp{}, errors.Wrap(err, "usercase.BlogProvider.ImportPosts")
+		}
+		b.publish(req.BlogID, EventPostsReset, struct{}{}, false)
+		return model.PostsImportResp{Imported: imported, Errors: []model.PostImportError{}}, nil
+	}
+
+	resp := model.PostsImportResp{Errors: []model.PostImportError{}}
+	defer func() {
+		if resp.Imported > 0 {
+			b.publish(req.BlogID, EventPostsReset, struct{}{}, false)
+		}
+	}()
+	for {
+		lines, err := req.Next(importBatchSize)
+		if errors.Is(err, io.EOF) {
+			return resp, nil
+		}
+		if err != nil {
+			return model.PostsImportResp{}, errors.Wrap(err, "usercase.BlogProvider.ImportPosts")
+		}
+		posts := importedPosts(req.BlogID, lines)
+		err = b.repository.ImportPosts(ctx, req.BlogID, postsOnce(posts))
+		if err == nil {
+			resp.Imported += len(posts)
+			continue
+		}
+		if errors.Is(err, apperror.ErrNotFound) {
+			return model.PostsImportResp{}, errors.Wrap(err, "usercase.BlogProvider.ImportPosts")
+		}
+		log.Err(err).Msg("usercase.BlogProvider.ImportPosts")
+		for i := 0; i < len(posts); i++ {
+			if err := b.repository.ImportPosts(ctx, req.BlogID, postsOnce(posts[i:i+1])); err != nil {
+				log.Err(err).Int("line", lines[i].Line).Msg("usercase.BlogProvider.ImportPosts")
+				resp.Errors = append(resp.Errors, model.PostImportError{Line: lines[i].Line, Error: importRejected})
+				continue
+			}
+			resp.Imported++
+		}
+	}
+}
+
+func importedPosts(blogID uuid.UUID, lines []model.PostImportLine) []model.DbPost {
+	posts := make([]model.DbPost, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		id, _ := uuid.NewRandom()
+		posts = append(posts, model.DbPost{
+			ID:        id,
+			BlogID:    blogID,
+			Title:     lines[i].Post.Title,
+			Text:      lines[i].Post.Text,
+			Tags:      normalizeTags(lines[i].Post.Tags),
+			CreatedAt: time.Now(),
+			Version:   1,
+		})
+		last := &posts[len(posts)-1]
+		last.Status, last.PublishAt = postState(lines[i].Post.Status, lines[i].Post.PublishAt, last.CreatedAt)
+	}
+	return posts
+}
+
+// postsOnce gives posts to repository ImportPosts as a single batch
+func postsOnce(posts []model.DbPost) func() ([]model.DbPost, error) {
+	done := false
+	return func() ([]model.DbPost, error) {
+		if done {
+			return nil, io.EOF
+		}
+		done = true
+		return posts, nil
+	}
+}
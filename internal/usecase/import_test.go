@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/broadcast"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// importSource gives count valid lines to PostsImportReq.Next and counts the lines read
+func importSource(count int, read *int) func(n int) ([]model.PostImportLine, error) {
+	return func(n int) ([]model.PostImportLine, error) {
+		if *read == count {
+			return nil, io.EOF
+		}
+		var lines []model.PostImportLine
+		for ; *read < count && len(lines) < n; *read++ {
+			lines = append(lines, model.PostImportLine{Line: *read + 1, Post: model.PostPostReq{Title: "title", Text: "text"}})
+		}
+		return lines, nil
+	}
+}
+
+func TestBlogProvider_ImportPosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockBlogRepository(ctrl)
+	provider := NewBlogProvider(repository, broadcast.New(16), nil)
+
+	ownerID, blogID := uuid.New(), uuid.New()
+	owner := auth.WithUserID(context.Background(), ownerID)
+	repository.EXPECT().GetBlogOwner(gomock.Any(), blogID).Return(ownerID, nil).AnyTimes()
+
+	t.Run("not allowed", func(t *testing.T) {
+		for _, ctx := range []context.Context{context.Background(), auth.WithUserID(context.Background(), uuid.New())} {
+			read := 0
+			_, err := provider.ImportPosts(ctx, model.PostsImportReq{BlogID: blogID, Next: importSource(10, &read)})
+			assert.Error(t, err)
+			assert.Zero(t, read, "body is read before authorization")
+		}
+	})
+
+	t.Run("atomic", func(t *testing.T) {
+		a := assert.New(t)
+		batches := 0
+		repository.EXPECT().ImportPosts(gomock.Any(), blogID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, next func() ([]model.DbPost, error)) error {
+				for {
+					posts, err := next()
+					if errors.Is(err, io.EOF) {
+						return nil
+					}
+					if err != nil {
+						return err
+					}
+					a.LessOrEqual(len(posts), importBatchSize)
+					batches++
+				}
+			})
+		read := 0
+		resp, err := provider.ImportPosts(owner, model.PostsImportReq{BlogID: blogID, Atomic: true, Next: importSource(importBatchSize+1, &read)})
+		a.NoError(err)
+		a.Equal(importBatchSize+1, resp.Imported)
+		a.Equal(2, batches)
+	})
+
+	t.Run("best effort", func(t *testing.T) {
+		a := assert.New(t)
+		// the batch fails, then its second post fails alone
+		calls := 0
+		repository.EXPECT().ImportPosts(gomock.Any(), blogID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, next func() ([]model.DbPost, error)) error {
+				calls++
+				if calls == 1 || calls == 3 {
+					return errors.New("copy failed")
+				}
+				return nil
+			}).Times(4)
+		read := 0
+		resp, err := provider.ImportPosts(owner, model.PostsImportReq{BlogID: blogID, Next: importSource(3, &read)})
+		a.NoError(err)
+		a.Equal(2, resp.Imported)
+		a.Equal([]model.PostImportError{{Line: 2, Error: importRejected}}, resp.Errors)
+	})
+
+	t.Run("blog not found", func(t *testing.T) {
+		repository.EXPECT().ImportPosts(gomock.Any(), blogID, gomock.Any()).Return(apperror.ErrNotFound)
+		read := 0
+		_, err := provider.ImportPosts(owner, model.PostsImportReq{BlogID: blogID, Next: importSource(3, &read)})
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+	})
+}
@@ -2,18 +2,79 @@ package usecase
 
 import (
 	"context"
+	"io"
 
+	"github.com/Rolan335/project/internal/broadcast"
 	"github.com/Rolan335/project/internal/model"
 )
 
 type BlogUsecase interface {
 	GetBlog(ctx context.Context, req model.BlogGetReq) (model.BlogGetResp, error)
+	GetBlogBySlug(ctx context.Context, req model.BlogSlugGetReq) (model.BlogGetResp, error)
 	AddBlog(ctx context.Context, req model.BlogPostReq) (model.BlogPostResp, error)
 	UpdateBlog(ctx context.Context, req model.BlogPutReq) (model.BlogPutResp, error)
 	DeleteBlog(ctx context.Context, req model.BlogDeleteReq) error
+	GetBlogsTrash(ctx context.Context, req model.BlogsTrashGetReq) (model.BlogsTrashGetResp, error)
+	RestoreBlog(ctx context.Context, req model.BlogRestoreReq) (model.BlogGetResp, error)
 	GetPost(ctx context.Context, req model.PostGetReq) (model.PostGetResp, error)
-	GetPosts(ctx context.Context, req model.PostsGetReq) ([]model.PostGetResp, error)
+	GetPostBySlug(ctx context.Context, req model.PostSlugGetReq) (model.PostSlugGetResp, error)
+	GetPosts(ctx context.Context, req model.PostsGetReq) (model.PostsGetResp, error)
+	GetBlogsPosts(ctx context.Context, req model.BlogsPostsGetReq) (model.BlogsPostsGetResp, error)
+	SearchPosts(ctx context.Context, req model.PostsSearchReq) (model.PostsSearchResp, error)
 	AddPost(ctx context.Context, req model.PostPostReq) (model.PostPostResp, error)
+	ImportPosts(ctx context.Context, req model.PostsImportReq) (model.PostsImportResp, error)
+	ExportBlog(ctx context.Context, req model.BlogExportReq, w io.Writer) error
+	GetFeed(ctx context.Context, req model.FeedGetReq) (model.FeedGetResp, error)
 	UpdatePost(ctx context.Context, req model.PostPutReq) (model.PostPutResp, error)
 	DeletePost(ctx context.Context, req model.PostDeleteReq) error
+	GetPostsTrash(ctx context.Context, req model.PostsTrashGetReq) (model.PostsTrashGetResp, error)
+	RestorePost(ctx context.Context, req model.PostRestoreReq) (model.PostGetResp, error)
+	GetPostRevisions(ctx context.Context, req model.PostRevisionsGetReq) (model.PostRevisionsGetResp, error)
+	GetPostRevision(ctx context.Context, req model.PostRevisionGetReq) (model.PostRevisionResp, error)
+	DiffPostRevisions(ctx context.Context, req model.PostRevisionsDiffReq) (model.PostRevisionsDiffResp, error)
+	RestorePostRevision(ctx context.Context, req model.PostRevisionRestoreReq) (model.PostPutResp, error)
+	GetBlogTags(ctx context.Context, req model.TagsGetReq) (model.TagsGetResp, error)
+	SubscribePostEvents(ctx context.Context, req model.PostEventsReq) (*broadcast.Subscription, error)
+	SubscribePresence(ctx context.Context, req model.PresenceSubscribeReq) (*broadcast.Subscription, error)
+	SendPresence(ctx context.Context, sub *broadcast.Subscription, req model.PresencePostReq) error
+}
+
+type CommentUsecase interface {
+	AddComment(ctx context.Context, req model.CommentPostReq) (model.CommentPostResp, error)
+	GetComments(ctx context.Context, req model.CommentsGetReq) (model.CommentsGetResp, error)
+	UpdateComment(ctx context.Context, req model.CommentPutReq) (model.CommentPutResp, error)
+	DeleteComment(ctx context.Context, req model.CommentDeleteReq) error
+}
+
+type UserUsecase interface {
+	AddUser(ctx context.Context, req model.UserPostReq) (model.UserPostResp, error)
+	GetUser(ctx context.Context, req model.UserGetReq) (model.UserGetResp, error)
+	UpdateUser(ctx context.Context, req model.UserPutReq) (model.UserPutResp, error)
+	GetUserBlogs(ctx context.Context, req model.UserBlogsGetReq) (model.UserBlogsGetResp, error)
+}
+
+type APIKeyUsecase interface {
+	AddAPIKey(ctx context.Context, req model.APIKeyPostReq) (model.APIKeyPostResp, error)
+	GetAPIKeys(ctx context.Context) (model.APIKeysGetResp, error)
+	RevokeAPIKey(ctx context.Context, req model.APIKeyDeleteReq) error
+	AuthenticateAPIKey(ctx context.Context, key string) (model.APIKeyAuthResp, error)
+}
+
+type WebhookUsecase interface {
+	AddWebhook(ctx context.Context, req model.WebhookPostReq) (model.WebhookPostResp, error)
+	GetWebhooks(ctx context.Context, req model.WebhooksGetReq) (model.WebhooksGetResp, error)
+	DeleteWebhook(ctx context.Context, req model.WebhookDeleteReq) error
+	GetWebhookDeliveries(ctx context.Context, req model.WebhookDeliveriesGetReq) (model.WebhookDeliveriesGetResp, error)
+	RedeliverWebhook(ctx context.Context, req model.WebhookRedeliverReq) (model.WebhookDeliveryResp, error)
+}
+
+type IdempotencyUsecase interface {
+	Begin(ctx context.Context, key string, requestHash []byte) (*model.IdempotentResp, error)
+	Finish(ctx context.Context, key string, resp model.IdempotentResp) error
+	Abort(ctx context.Context, key string) error
+}
+
+// EventSink receives domain events from OutboxRelay in outbox order, an event may come more than once
+type EventSink interface {
+	Send(ctx context.Context, event model.DbOutboxEvent) error
 }
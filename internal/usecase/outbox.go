@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/repository"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// OutboxRelay delivers events written by BlogRepo to sinks
+type OutboxRelay struct {
+	repository repository.OutboxRepository
+	sinks      []EventSink
+}
+
+func NewOutboxRelay(repository repository.OutboxRepository, sinks ...EventSink) *OutboxRelay {
+	return &OutboxRelay{
+		repository: repository,
+		sinks:      sinks,
+	}
+}
+
+// Relay passes at most batchSize pending events to every sink and returns number of delivered events.
+// An event failed in one of the sinks stops delivery and is sent again to all sinks on the next call,
+// so the order is never broken.
+func (o *OutboxRelay) Relay(ctx context.Context, batchSize int) (int, error) {
+	sent, err := o.repository.RelayEvents(ctx, batchSize, func(event model.DbOutboxEvent) error {
+		for _, sink := range o.sinks {
+			if err := sink.Send(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return sent, errors.Wrap(err, "usercase.OutboxRelay.Relay")
+	}
+	return sent, nil
+}
+
+// GoRelay delivers pending events every interval and removes events sent more than retention ago
+func (o *OutboxRelay) GoRelay(ctx context.Context, interval time.Duration, batchSize int, retention time.Duration) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+				o.relayPending(ctx, batchSize)
+				purged, err := o.repository.DeleteSent(ctx, retention)
+				if err != nil {
+					log.Err(err).Msg("usercase.OutboxRelay.GoRelay")
+					continue
+				}
+				log.Debug().Int64("purged", purged).Msg("outbox purged")
+			}
+		}
+	}()
+}
+
+// relayPending relays batches until the outbox is drained or delivery fails
+func (o *OutboxRelay) relayPending(ctx context.Context, batchSize int) {
+	for {
+		sent, err := o.Relay(ctx, batchSize)
+		if err != nil {
+			log.Err(err).Msg("usercase.OutboxRelay.GoRelay")
+			return
+		}
+		if sent < batchSize {
+			return
+		}
+	}
+}
+
+// LogSink writes events to the log
+type LogSink struct{}
+
+func (LogSink) Send(_ context.Context, event model.DbOutboxEvent) error {
+	log.Info().
+		Int64("id", event.ID).
+		Str("type", event.Type).
+		Str("aggregate_id", event.AggregateID.String()).
+		RawJSON("payload", event.Payload).
+		Msg("outbox event")
+	return nil
+}
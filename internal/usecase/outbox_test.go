@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// recordSink remembers event ids and fails on the event with id failOn
+type recordSink struct {
+	ids    []int64
+	failOn int64
+}
+
+func (s *recordSink) Send(_ context.Context, event model.DbOutboxEvent) error {
+	if event.ID == s.failOn {
+		return errors.New("sink is down")
+	}
+	s.ids = append(s.ids, event.ID)
+	return nil
+}
+
+// relayEvents behaves like OutboxRepo.RelayEvents over pending events
+func relayEvents(pending []model.DbOutboxEvent) func(context.Context, int, func(model.DbOutboxEvent) error) (int, error) {
+	return func(_ context.Context, limit int, fn func(model.DbOutboxEvent) error) (int, error) {
+		sent := 0
+		for i := 0; i < len(pending) && i < limit; i++ {
+			if err := fn(pending[i]); err != nil {
+				return sent, err
+			}
+			sent++
+		}
+		return sent, nil
+	}
+}
+
+func TestOutboxRelay_Relay(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockOutboxRepository(ctrl)
+
+	pending := []model.DbOutboxEvent{{ID: 1, Type: model.EventBlogCreated}, {ID: 2, Type: model.EventPostCreated}, {ID: 3, Type: model.EventPostDeleted}}
+	repository.EXPECT().RelayEvents(gomock.Any(), 10, gomock.Any()).DoAndReturn(relayEvents(pending)).Times(2)
+
+	first, second := &recordSink{}, &recordSink{}
+	relay := NewOutboxRelay(repository, first, second)
+	sent, err := relay.Relay(context.Background(), 10)
+	a.NoError(err)
+	a.Equal(3, sent)
+	a.Equal([]int64{1, 2, 3}, first.ids)
+	a.Equal([]int64{1, 2, 3}, second.ids)
+
+	// failed event is not passed to the next sinks and stops delivery
+	first, second = &recordSink{}, &recordSink{failOn: 2}
+	relay = NewOutboxRelay(repository, second, first)
+	sent, err = relay.Relay(context.Background(), 10)
+	a.Error(err)
+	a.Equal(1, sent)
+	a.Equal([]int64{1}, first.ids)
+}
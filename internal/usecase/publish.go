@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// postState returns status and publish_at of a post. Posts are published right away unless told otherwise,
+// publish_at of a published post is the time of publishing.
+func postState(status string, publishAt *time.Time, now time.Time) (string, *time.Time) {
+	switch status {
+	case model.PostStatusDraft:
+		return status, nil
+	case model.PostStatusScheduled:
+		return status, publishAt
+	default:
+		return model.PostStatusPublished, &now
+	}
+}
+
+// GoPublishScheduled periodically publishes scheduled posts which are due, batchSize posts per query.
+// Several replicas may run it at once, repository never gives the same post to two of them.
+func (b *BlogProvider) GoPublishScheduled(ctx context.Context, interval time.Duration, batchSize int) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+				b.publishScheduled(ctx, batchSize)
+			}
+		}
+	}()
+}
+
+func (b *BlogProvider) publishScheduled(ctx context.Context, batchSize int) {
+	for {
+		posts, err := b.repository.PublishScheduled(ctx, batchSize)
+		if err != nil {
+			log.Err(err).Msg("usercase.BlogProvider.GoPublishScheduled")
+			return
+		}
+		for i := 0; i < len(posts); i++ {
+			b.publishPost(model.EventPostUpdated, posts[i])
+		}
+		if len(posts) > 0 {
+			log.Debug().Int("published", len(posts)).Msg("scheduled posts published")
+		}
+		if len(posts) < batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/broadcast"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBlogProvider_GetPostDraft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockBlogRepository(ctrl)
+	provider := NewBlogProvider(repository, broadcast.New(16), nil)
+
+	ownerID := uuid.New()
+	draft := model.DbPost{ID: uuid.New(), BlogID: uuid.New(), Title: "draft", Status: model.PostStatusDraft}
+	repository.EXPECT().GetPost(gomock.Any(), draft.ID).Return(draft, nil).AnyTimes()
+	repository.EXPECT().GetBlogOwner(gomock.Any(), draft.BlogID).Return(ownerID, nil).AnyTimes()
+
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{name: "owner", ctx: auth.WithUserID(context.Background(), ownerID)},
+		{name: "another user", ctx: auth.WithUserID(context.Background(), uuid.New()), wantErr: apperror.ErrNotFound},
+		{name: "anonymous", ctx: context.Background(), wantErr: apperror.ErrNotFound},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := provider.GetPost(tt.ctx, model.PostGetReq{BlogID: draft.BlogID, PostID: draft.ID})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, model.PostStatusDraft, resp.Status)
+		})
+	}
+}
+
+func TestBlogProvider_publishScheduled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockBlogRepository(ctrl)
+	provider := NewBlogProvider(repository, broadcast.New(16), nil)
+
+	// full batch means there may be more due posts
+	gomock.InOrder(
+		repository.EXPECT().PublishScheduled(gomock.Any(), 2).Return(make([]model.DbPost, 2), nil),
+		repository.EXPECT().PublishScheduled(gomock.Any(), 2).Return(make([]model.DbPost, 1), nil),
+	)
+	provider.publishScheduled(context.Background(), 2)
+}
+
+func TestPostState(t *testing.T) {
+	a := assert.New(t)
+	now := time.Now()
+	at := now.Add(time.Hour)
+
+	status, publishAt := postState("", nil, now)
+	a.Equal(model.PostStatusPublished, status)
+	a.Equal(&now, publishAt)
+
+	status, publishAt = postState(model.PostStatusScheduled, &at, now)
+	a.Equal(model.PostStatusScheduled, status)
+	a.Equal(&at, publishAt)
+
+	status, publishAt = postState(model.PostStatusDraft, &at, now)
+	a.Equal(model.PostStatusDraft, status)
+	a.Nil(publishAt)
+}
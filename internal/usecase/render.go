@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Rolan335/project/internal/markdown"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/pkg/errors"
+)
+
+// HTMLCache keeps rendered text of posts by version, cache.CacheDecorator keeps it next to the cached post
+type HTMLCache interface {
+	GetHTML(ctx context.Context, post model.DbPost) (string, bool)
+	SetHTML(ctx context.Context, post model.DbPost, html string)
+}
+
+// renderHTML renders post text from markdown, once per version of the post when html cache is set
+func (b *BlogProvider) renderHTML(ctx context.Context, post model.DbPost) (string, error) {
+	if b.html != nil {
+		if html, ok := b.html.GetHTML(ctx, post); ok {
+			return html, nil
+		}
+	}
+	html, err := markdown.Render(post.Text)
+	if err != nil {
+		return "", errors.Wrap(err, "usercase.BlogProvider.renderHTML")
+	}
+	if b.html != nil {
+		b.html.SetHTML(ctx, post, html)
+	}
+	return html, nil
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/Rolan335/project/internal/broadcast"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// htmlCache keeps html by post id and version
+type htmlCache map[string]string
+
+func (c htmlCache) key(post model.DbPost) string {
+	return post.ID.String() + "/" + strconv.Itoa(post.Version)
+}
+
+func (c htmlCache) GetHTML(_ context.Context, post model.DbPost) (string, bool) {
+	html, ok := c[c.key(post)]
+	return html, ok
+}
+
+func (c htmlCache) SetHTML(_ context.Context, post model.DbPost, html string) {
+	c[c.key(post)] = html
+}
+
+func TestBlogProvider_renderHTML(t *testing.T) {
+	a := assert.New(t)
+	cache := htmlCache{}
+	provider := NewBlogProvider(nil, broadcast.New(16), cache)
+
+	post := model.DbPost{ID: uuid.New(), Text: "*hi*", Version: 1}
+	html, err := provider.renderHTML(context.Background(), post)
+	a.NoError(err)
+	a.Equal("<p><em>hi</em></p>\n", html)
+	a.Equal(html, cache[cache.key(post)])
+
+	// cached html of the version is not rendered again
+	cache[cache.key(post)] = "cached"
+	html, err = provider.renderHTML(context.Background(), post)
+	a.NoError(err)
+	a.Equal("cached", html)
+
+	html, err = NewBlogProvider(nil, broadcast.New(16), nil).renderHTML(context.Background(), post)
+	a.NoError(err)
+	a.Equal("<p><em>hi</em></p>\n", html)
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/textdiff"
+	"github.com/pkg/errors"
+)
+
+// revisions are shown to those who can see the post, drafts only to the blog owner
+func (b *BlogProvider) GetPostRevisions(ctx context.Context, req model.PostRevisionsGetReq) (model.PostRevisionsGetResp, error) {
+	if err := b.checkPostVisible(ctx, req.BlogID, req.PostID); err != nil {
+		return model.PostRevisionsGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPostRevisions")
+	}
+	revisions, err := b.repository.GetPostRevisions(ctx, req.PostID, req.BlogID)
+	if err != nil {
+		return model.PostRevisionsGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPostRevisions")
+	}
+	resp := make([]model.PostRevisionResp, 0, len(revisions))
+	for i := 0; i < len(revisions); i++ {
+		resp = append(resp, model.PostRevisionResp{
+			Revision:  revisions[i].Revision,
+			Title:     revisions[i].Title,
+			CreatedAt: revisions[i].CreatedAt,
+		})
+	}
+	return model.PostRevisionsGetResp{Revisions: resp}, nil
+}
+
+func (b *BlogProvider) GetPostRevision(ctx context.Context, req model.PostRevisionGetReq) (model.PostRevisionResp, error) {
+	if err := b.checkPostVisible(ctx, req.BlogID, req.PostID); err != nil {
+		return model.PostRevisionResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPostRevision")
+	}
+	rev, err := b.repository.GetPostRevision(ctx, req.PostID, req.BlogID, req.Revision)
+	if err != nil {
+		return model.PostRevisionResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPostRevision")
+	}
+	return model.PostRevisionResp{
+		Revision:  rev.Revision,
+		Title:     rev.Title,
+		Text:      rev.Text,
+		CreatedAt: rev.CreatedAt,
+	}, nil
+}
+
+func (b *BlogProvider) DiffPostRevisions(ctx context.Context, req model.PostRevisionsDiffReq) (model.PostRevisionsDiffResp, error) {
+	if err := b.checkPostVisible(ctx, req.BlogID, req.PostID); err != nil {
+		return model.PostRevisionsDiffResp{}, errors.Wrap(err, "usercase.BlogProvider.DiffPostRevisions")
+	}
+	from, err := b.repository.GetPostRevision(ctx, req.PostID, req.BlogID, req.From)
+	if err != nil {
+		return model.PostRevisionsDiffResp{}, errors.Wrap(err, "usercase.BlogProvider.DiffPostRevisions")
+	}
+	to, err := b.repository.GetPostRevision(ctx, req.PostID, req.BlogID, req.To)
+	if err != nil {
+		return model.PostRevisionsDiffResp{}, errors.Wrap(err, "usercase.BlogProvider.DiffPostRevisions")
+	}
+	return model.PostRevisionsDiffResp{
+		From:  from.Revision,
+		To:    to.Revision,
+		Title: diffLines(from.Title, to.Title),
+		Text:  diffLines(from.Text, to.Text),
+	}, nil
+}
+
+// RestorePostRevision writes content of an old revision as a new one, history is never rewritten
+func (b *BlogProvider) RestorePostRevision(ctx context.Context, req model.PostRevisionRestoreReq) (model.PostPutResp, error) {
+	rev, err := b.repository.GetPostRevision(ctx, req.PostID, req.BlogID, req.Revision)
+	if err != nil {
+		return model.PostPutResp{}, errors.Wrap(err, "usercase.BlogProvider.RestorePostRevision")
+	}
+	resp, err := b.UpdatePost(ctx, model.PostPutReq{
+		PostID: req.PostID,
+		BlogID: req.BlogID,
+		Title:  rev.Title,
+		Text:   rev.Text,
+	})
+	if err != nil {
+		return model.PostPutResp{}, errors.Wrap(err, "usercase.BlogProvider.RestorePostRevision")
+	}
+	return resp, nil
+}
+
+func diffLines(a, b string) []model.DiffLine {
+	lines := textdiff.Lines(a, b)
+	res := make([]model.DiffLine, 0, len(lines))
+	for _, l := range lines {
+		res = append(res, model.DiffLine{Op: string(l.Op), Text: l.Text})
+	}
+	return res
+}
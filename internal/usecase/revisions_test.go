@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/broadcast"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBlogProvider_revisionsOfDraft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockBlogRepository(ctrl)
+	provider := NewBlogProvider(repository, broadcast.New(16), nil)
+
+	ownerID := uuid.New()
+	draft := model.DbPost{ID: uuid.New(), BlogID: uuid.New(), Title: "draft", Status: model.PostStatusDraft}
+	repository.EXPECT().GetPost(gomock.Any(), draft.ID).Return(draft, nil).AnyTimes()
+	repository.EXPECT().GetBlogOwner(gomock.Any(), draft.BlogID).Return(ownerID, nil).AnyTimes()
+	repository.EXPECT().GetPostRevisions(gomock.Any(), draft.ID, draft.BlogID).
+		Return([]model.DbPostRevision{{Revision: 1}}, nil).AnyTimes()
+	repository.EXPECT().GetPostRevision(gomock.Any(), draft.ID, draft.BlogID, gomock.Any()).
+		Return(model.DbPostRevision{Revision: 1}, nil).AnyTimes()
+
+	requests := map[string]func(context.Context, uuid.UUID) error{
+		"list": func(ctx context.Context, blogID uuid.UUID) error {
+			_, err := provider.GetPostRevisions(ctx, model.PostRevisionsGetReq{BlogID: blogID, PostID: draft.ID})
+			return err
+		},
+		"get": func(ctx context.Context, blogID uuid.UUID) error {
+			_, err := provider.GetPostRevision(ctx, model.PostRevisionGetReq{BlogID: blogID, PostID: draft.ID, Revision: 1})
+			return err
+		},
+		"diff": func(ctx context.Context, blogID uuid.UUID) error {
+			_, err := provider.DiffPostRevisions(ctx, model.PostRevisionsDiffReq{BlogID: blogID, PostID: draft.ID, From: 1, To: 1})
+			return err
+		},
+	}
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		blogID  uuid.UUID
+		wantErr error
+	}{
+		{name: "owner", ctx: auth.WithUserID(context.Background(), ownerID), blogID: draft.BlogID},
+		{name: "owner in another blog", ctx: auth.WithUserID(context.Background(), ownerID), blogID: uuid.New(), wantErr: apperror.ErrNotFound},
+		{name: "another user", ctx: auth.WithUserID(context.Background(), uuid.New()), blogID: draft.BlogID, wantErr: apperror.ErrNotFound},
+		{name: "anonymous", ctx: context.Background(), blogID: draft.BlogID, wantErr: apperror.ErrNotFound},
+	}
+	for _, tt := range testCases {
+		for name, request := range requests {
+			t.Run(tt.name+" "+name, func(t *testing.T) {
+				err := request(tt.ctx, tt.blogID)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+					return
+				}
+				assert.NoError(t, err)
+			})
+		}
+	}
+}
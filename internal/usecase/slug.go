@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/pkg/errors"
+)
+
+// GetBlogBySlug finds blog by current or old slug, resp.Slug is always the current one
+func (b *BlogProvider) GetBlogBySlug(ctx context.Context, req model.BlogSlugGetReq) (model.BlogGetResp, error) {
+	blog, err := b.repository.GetBlogBySlug(ctx, req.Slug)
+	if err != nil {
+		return model.BlogGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetBlogBySlug")
+	}
+	return model.BlogGetResp{
+		BlogID:    blog.ID,
+		UserID:    blog.UserID,
+		Name:      blog.Name,
+		Slug:      blog.Slug,
+		CreatedAt: blog.CreatedAt,
+		Version:   blog.Version,
+	}, nil
+}
+
+// GetPostBySlug finds post by current or old slugs of the blog and the post
+func (b *BlogProvider) GetPostBySlug(ctx context.Context, req model.PostSlugGetReq) (model.PostSlugGetResp, error) {
+	blog, err := b.repository.GetBlogBySlug(ctx, req.BlogSlug)
+	if err != nil {
+		return model.PostSlugGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPostBySlug")
+	}
+	post, err := b.repository.GetPostBySlug(ctx, blog.ID, req.PostSlug)
+	if err != nil {
+		return model.PostSlugGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPostBySlug")
+	}
+	if err := b.checkVisible(ctx, post); err != nil {
+		return model.PostSlugGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPostBySlug")
+	}
+	var html string
+	if req.RenderHTML {
+		html, err = b.renderHTML(ctx, post)
+		if err != nil {
+			return model.PostSlugGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetPostBySlug")
+		}
+	}
+	return model.PostSlugGetResp{
+		BlogSlug: blog.Slug,
+		Post: model.PostGetResp{
+			PostID:        post.ID,
+			BlogID:        post.BlogID,
+			Title:         post.Title,
+			Slug:          post.Slug,
+			Status:        post.Status,
+			PublishAt:     post.PublishAt,
+			Text:          post.Text,
+			HTML:          html,
+			CreatedAt:     post.CreatedAt,
+			Version:       post.Version,
+			Tags:          post.Tags,
+			CommentsCount: post.CommentsCount,
+		},
+	}, nil
+}
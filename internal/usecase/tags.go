@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/pkg/errors"
+)
+
+func (b *BlogProvider) GetBlogTags(ctx context.Context, req model.TagsGetReq) (model.TagsGetResp, error) {
+	tags, err := b.repository.GetBlogTags(ctx, req.BlogID)
+	if err != nil {
+		return model.TagsGetResp{}, errors.Wrap(err, "usercase.BlogProvider.GetBlogTags")
+	}
+	resp := make([]model.TagResp, 0, len(tags))
+	for i := 0; i < len(tags); i++ {
+		resp = append(resp, model.TagResp{Name: tags[i].Name, Count: tags[i].Count})
+	}
+	return model.TagsGetResp{Tags: resp}, nil
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// normalizeTags lowercases, sorts and deduplicates tags. Nil stays nil, it means "don't touch tags" on update.
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	res := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = normalizeTag(tag); tag != "" {
+			res = append(res, tag)
+		}
+	}
+	slices.Sort(res)
+	return slices.Compact(res)
+}
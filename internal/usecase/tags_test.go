@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	a := assert.New(t)
+	a.Nil(normalizeTags(nil))
+	a.Equal([]string{}, normalizeTags([]string{}))
+	a.Equal([]string{"db", "go"}, normalizeTags([]string{" Go", "go", "DB", "  "}))
+}
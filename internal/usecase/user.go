@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/repository"
+	"github.com/pkg/errors"
+)
+
+type UserProvider struct {
+	repository repository.UserRepository
+}
+
+func NewUserProvider(repository repository.UserRepository) *UserProvider {
+	return &UserProvider{
+		repository: repository,
+	}
+}
+
+// AddUser registers the caller, the user gets id of the authenticated subject. Registering again is a conflict.
+func (u *UserProvider) AddUser(ctx context.Context, req model.UserPostReq) (model.UserPostResp, error) {
+	id, err := caller(ctx, auth.ScopeAdmin)
+	if err != nil {
+		return model.UserPostResp{}, errors.Wrap(err, "usercase.UserProvider.AddUser")
+	}
+	user := model.DbUser{
+		ID:          id,
+		DisplayName: req.DisplayName,
+		Email:       normalizeEmail(req.Email),
+		Bio:         req.Bio,
+		CreatedAt:   time.Now(),
+	}
+	userID, err := u.repository.AddUser(ctx, user)
+	if err != nil {
+		return model.UserPostResp{}, errors.Wrap(err, "usercase.UserProvider.AddUser")
+	}
+	return model.UserPostResp{UserID: userID}, nil
+}
+
+func (u *UserProvider) GetUser(ctx context.Context, req model.UserGetReq) (model.UserGetResp, error) {
+	user, err := u.repository.GetUser(ctx, req.UserID)
+	if err != nil {
+		return model.UserGetResp{}, errors.Wrap(err, "usercase.UserProvider.GetUser")
+	}
+	resp := model.UserGetResp{
+		UserID:      user.ID,
+		DisplayName: user.DisplayName,
+		Bio:         user.Bio,
+		CreatedAt:   user.CreatedAt,
+	}
+	// email is private, others see the profile without it
+	if userID, err := caller(ctx, auth.ScopeRead); err == nil && userID == user.ID {
+		resp.Email = user.Email
+	}
+	return resp, nil
+}
+
+func (u *UserProvider) UpdateUser(ctx context.Context, req model.UserPutReq) (model.UserPutResp, error) {
+	userID, err := caller(ctx, auth.ScopeAdmin)
+	if err != nil {
+		return model.UserPutResp{}, errors.Wrap(err, "usercase.UserProvider.UpdateUser")
+	}
+	if userID != req.UserID {
+		return model.UserPutResp{}, apperror.ErrForbidden
+	}
+	user, err := u.repository.UpdateUser(ctx, model.DbUser{
+		ID:          req.UserID,
+		DisplayName: req.DisplayName,
+		Email:       normalizeEmail(req.Email),
+		Bio:         req.Bio,
+	})
+	if err != nil {
+		return model.UserPutResp{}, errors.Wrap(err, "usercase.UserProvider.UpdateUser")
+	}
+	return model.UserPutResp{
+		UserID:      user.ID,
+		DisplayName: user.DisplayName,
+		Email:       user.Email,
+		Bio:         user.Bio,
+		CreatedAt:   user.CreatedAt,
+	}, nil
+}
+
+func (u *UserProvider) GetUserBlogs(ctx context.Context, req model.UserBlogsGetReq) (model.UserBlogsGetResp, error) {
+	// unknown user is not found, user without blogs has empty list
+	if _, err := u.repository.GetUser(ctx, req.UserID); err != nil {
+		return model.UserBlogsGetResp{}, errors.Wrap(err, "usercase.UserProvider.GetUserBlogs")
+	}
+	blogs, err := u.repository.GetUserBlogs(ctx, req.UserID)
+	if err != nil {
+		return model.UserBlogsGetResp{}, errors.Wrap(err, "usercase.UserProvider.GetUserBlogs")
+	}
+	resp := make([]model.BlogGetResp, 0, len(blogs))
+	for i := 0; i < len(blogs); i++ {
+		resp = append(resp, model.BlogGetResp{
+			BlogID:    blogs[i].ID,
+			UserID:    blogs[i].UserID,
+			Name:      blogs[i].Name,
+			Slug:      blogs[i].Slug,
+			CreatedAt: blogs[i].CreatedAt,
+			Version:   blogs[i].Version,
+		})
+	}
+	return model.UserBlogsGetResp{Blogs: resp}, nil
+}
+
+// emails are unique regardless of case
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUserProvider_GetUserEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockUserRepository(ctrl)
+	provider := NewUserProvider(repository)
+
+	user := model.DbUser{ID: uuid.New(), DisplayName: "user", Email: "user@example.com"}
+	repository.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).AnyTimes()
+
+	testCases := []struct {
+		name      string
+		ctx       context.Context
+		wantEmail string
+	}{
+		{name: "the user", ctx: auth.WithUserID(context.Background(), user.ID), wantEmail: user.Email},
+		{name: "another user", ctx: auth.WithUserID(context.Background(), uuid.New())},
+		{name: "anonymous", ctx: context.Background()},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := provider.GetUser(tt.ctx, model.UserGetReq{UserID: user.ID})
+			assert.NoError(t, err)
+			assert.Equal(t, user.DisplayName, resp.DisplayName)
+			assert.Equal(t, tt.wantEmail, resp.Email)
+		})
+	}
+}
+
+func TestUserProvider_AddUser(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockUserRepository(ctrl)
+	provider := NewUserProvider(repository)
+
+	req := model.UserPostReq{DisplayName: "user", Email: " User@Example.com"}
+	_, err := provider.AddUser(context.Background(), req)
+	a.ErrorIs(err, apperror.ErrUnauthorized)
+
+	// the user is the authenticated subject
+	userID := uuid.New()
+	repository.EXPECT().AddUser(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user model.DbUser) (uuid.UUID, error) {
+		a.Equal(userID, user.ID)
+		a.Equal("user@example.com", user.Email)
+		return user.ID, nil
+	})
+	resp, err := provider.AddUser(auth.WithUserID(context.Background(), userID), req)
+	a.NoError(err)
+	a.Equal(userID, resp.UserID)
+}
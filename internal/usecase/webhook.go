@@ -0,0 +1,386 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/internal/repository"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// headers of a webhook delivery. Signature is hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the webhook secret,
+// receivers should check it and reject old timestamps.
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+
+	webhookSignaturePrefix = "sha256="
+	webhookSecretPrefix    = "whsec_"
+	webhookSecretBytes     = 32
+	// claimed deliveries are hidden from other workers for client timeout plus webhookLeaseMargin
+	webhookLeaseMargin = time.Minute
+)
+
+// WebhookRetry is the retry policy of deliveries, attempt n waits Backoff*2^(n-1) but not longer than MaxBackoff.
+// A delivery failed MaxAttempts times is dead and is sent again only by redeliver.
+type WebhookRetry struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+func (r WebhookRetry) delay(attempts int) time.Duration {
+	delay := r.Backoff
+	for i := 1; i < attempts && delay < r.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.MaxBackoff)
+}
+
+// WebhookProvider manages webhooks of blogs and delivers outbox events to them. It is an EventSink:
+// events are stored as pending deliveries and sent by GoDeliver, so a slow receiver does not hold the outbox.
+type WebhookProvider struct {
+	repository repository.WebhookRepository
+	client     *http.Client
+	retry      WebhookRetry
+}
+
+func NewWebhookProvider(repository repository.WebhookRepository, timeout time.Duration, retry WebhookRetry) *WebhookProvider {
+	return &WebhookProvider{
+		repository: repository,
+		client:     newWebhookClient(timeout, publicAddr),
+		retry:      retry,
+	}
+}
+
+// newWebhookClient returns client which connects only to addresses passing allowed and does not follow redirects.
+// The address is checked right before connect, after the host is resolved, so a name pointing
+// to an internal address at the time of delivery is refused even if it was public when the webhook was added.
+func newWebhookClient(timeout time.Duration, allowed func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !allowed(addrPort.Addr()) {
+				return errors.Errorf("address %s is not allowed", addrPort.Addr())
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		// proxy would be dialed instead of the receiver and pass the check
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// publicAddr reports whether addr may be a webhook receiver: loopback, private, link-local and other
+// non-routable addresses belong to the service's own network.
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// authorize checks that the caller owns the blog
+func (p *WebhookProvider) authorize(ctx context.Context, blogID uuid.UUID, scope string) error {
+	userID, err := caller(ctx, scope)
+	if err != nil {
+		return err
+	}
+	owner, err := p.repository.GetBlogOwner(ctx, blogID)
+	if err != nil {
+		return errors.Wrap(err, "usercase.WebhookProvider.authorize")
+	}
+	if owner != userID {
+		return apperror.ErrForbidden
+	}
+	return nil
+}
+
+// AddWebhook registers url for events of the blog. Webhooks hold secrets, so they are managed with admin scope.
+func (p *WebhookProvider) AddWebhook(ctx context.Context, req model.WebhookPostReq) (model.WebhookPostResp, error) {
+	if err := p.authorize(ctx, req.BlogID, auth.ScopeAdmin); err != nil {
+		return model.WebhookPostResp{}, errors.Wrap(err, "usercase.WebhookProvider.AddWebhook")
+	}
+	secret, err := randomHex(webhookSecretBytes)
+	if err != nil {
+		return model.WebhookPostResp{}, errors.Wrap(err, "usercase.WebhookProvider.AddWebhook")
+	}
+	id, _ := uuid.NewRandom()
+	hook := model.DbWebhook{
+		ID:        id,
+		BlogID:    req.BlogID,
+		URL:       req.URL,
+		Secret:    webhookSecretPrefix + secret,
+		Events:    req.Events,
+		CreatedAt: time.Now(),
+	}
+	hookID, err := p.repository.AddWebhook(ctx, hook)
+	if err != nil {
+		return model.WebhookPostResp{}, errors.Wrap(err, "usercase.WebhookProvider.AddWebhook")
+	}
+	return model.WebhookPostResp{
+		WebhookID: hookID,
+		URL:       hook.URL,
+		Secret:    hook.Secret,
+		Events:    hook.Events,
+		CreatedAt: hook.CreatedAt,
+	}, nil
+}
+
+func (p *WebhookProvider) GetWebhooks(ctx context.Context, req model.WebhooksGetReq) (model.WebhooksGetResp, error) {
+	if err := p.authorize(ctx, req.BlogID, auth.ScopeRead); err != nil {
+		return model.WebhooksGetResp{}, errors.Wrap(err, "usercase.WebhookProvider.GetWebhooks")
+	}
+	hooks, err := p.repository.GetWebhooks(ctx, req.BlogID)
+	if err != nil {
+		return model.WebhooksGetResp{}, errors.Wrap(err, "usercase.WebhookProvider.GetWebhooks")
+	}
+	resp := make([]model.WebhookResp, 0, len(hooks))
+	for i := 0; i < len(hooks); i++ {
+		resp = append(resp, model.WebhookResp{
+			WebhookID: hooks[i].ID,
+			URL:       hooks[i].URL,
+			Events:    hooks[i].Events,
+			CreatedAt: hooks[i].CreatedAt,
+		})
+	}
+	return model.WebhooksGetResp{Webhooks: resp}, nil
+}
+
+func (p *WebhookProvider) DeleteWebhook(ctx context.Context, req model.WebhookDeleteReq) error {
+	if err := p.authorize(ctx, req.BlogID, auth.ScopeAdmin); err != nil {
+		return errors.Wrap(err, "usercase.WebhookProvider.DeleteWebhook")
+	}
+	if err := p.repository.DeleteWebhook(ctx, req.WebhookID, req.BlogID); err != nil {
+		return errors.Wrap(err, "usercase.WebhookProvider.DeleteWebhook")
+	}
+	return nil
+}
+
+func (p *WebhookProvider) GetWebhookDeliveries(ctx context.Context, req model.WebhookDeliveriesGetReq) (model.WebhookDeliveriesGetResp, error) {
+	if err := p.authorize(ctx, req.BlogID, auth.ScopeRead); err != nil {
+		return model.WebhookDeliveriesGetResp{}, errors.Wrap(err, "usercase.WebhookProvider.GetWebhookDeliveries")
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultPostsLimit
+	}
+	deliveries, err := p.repository.GetDeliveries(ctx, req.WebhookID, req.BlogID, limit)
+	if err != nil {
+		return model.WebhookDeliveriesGetResp{}, errors.Wrap(err, "usercase.WebhookProvider.GetWebhookDeliveries")
+	}
+	resp := make([]model.WebhookDeliveryResp, 0, len(deliveries))
+	for i := 0; i < len(deliveries); i++ {
+		resp = append(resp, deliveryResp(deliveries[i]))
+	}
+	return model.WebhookDeliveriesGetResp{Deliveries: resp}, nil
+}
+
+// RedeliverWebhook sends the delivery again, dead deliveries get a fresh set of attempts
+func (p *WebhookProvider) RedeliverWebhook(ctx context.Context, req model.WebhookRedeliverReq) (model.WebhookDeliveryResp, error) {
+	if err := p.authorize(ctx, req.BlogID, auth.ScopeAdmin); err != nil {
+		return model.WebhookDeliveryResp{}, errors.Wrap(err, "usercase.WebhookProvider.RedeliverWebhook")
+	}
+	delivery, err := p.repository.Redeliver(ctx, req.DeliveryID, req.WebhookID, req.BlogID)
+	if err != nil {
+		return model.WebhookDeliveryResp{}, errors.Wrap(err, "usercase.WebhookProvider.RedeliverWebhook")
+	}
+	return deliveryResp(delivery), nil
+}
+
+func deliveryResp(d model.DbWebhookDelivery) model.WebhookDeliveryResp {
+	resp := model.WebhookDeliveryResp{
+		DeliveryID:     d.ID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	if d.Status == model.WebhookDeliveryPending {
+		resp.NextAttemptAt = &d.NextAttemptAt
+	}
+	return resp
+}
+
+// Send stores deliveries of the event for webhooks subscribed to it, the event itself is the request body
+func (p *WebhookProvider) Send(ctx context.Context, event model.DbOutboxEvent) error {
+	blogID, err := eventBlogID(event)
+	if err != nil {
+		return errors.Wrap(err, "usercase.WebhookProvider.Send")
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "usercase.WebhookProvider.Send")
+	}
+	if _, err := p.repository.AddDeliveries(ctx, blogID, event, payload); err != nil {
+		return errors.Wrap(err, "usercase.WebhookProvider.Send")
+	}
+	return nil
+}
+
+// eventBlogID returns blog the event belongs to, post events keep it in the payload
+func eventBlogID(event model.DbOutboxEvent) (uuid.UUID, error) {
+	if strings.HasPrefix(event.Type, "blog.") {
+		return event.AggregateID, nil
+	}
+	var payload struct {
+		BlogID uuid.UUID `json:"blog_id"`
+	}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return uuid.Nil, err
+	}
+	return payload.BlogID, nil
+}
+
+// GoDeliver sends due deliveries every interval, batchSize deliveries are sent at once
+func (p *WebhookProvider) GoDeliver(ctx context.Context, interval time.Duration, batchSize int) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+				p.deliverDue(ctx, batchSize)
+			}
+		}
+	}()
+}
+
+// deliverDue sends batches until no delivery is due
+func (p *WebhookProvider) deliverDue(ctx context.Context, batchSize int) {
+	for {
+		deliveries, err := p.repository.ClaimDeliveries(ctx, batchSize, p.client.Timeout+webhookLeaseMargin)
+		if err != nil {
+			log.Err(err).Msg("usercase.WebhookProvider.GoDeliver")
+			return
+		}
+		var wg sync.WaitGroup
+		for i := 0; i < len(deliveries); i++ {
+			wg.Add(1)
+			go func(d model.DbWebhookDelivery) {
+				defer wg.Done()
+				if err := p.repository.SaveAttempt(ctx, p.deliver(ctx, d)); err != nil {
+					log.Err(err).Msg("usercase.WebhookProvider.GoDeliver")
+				}
+			}(deliveries[i])
+		}
+		wg.Wait()
+		if len(deliveries) < batchSize {
+			return
+		}
+	}
+}
+
+// deliver makes one attempt. The attempt is a child of the trace which made the event
+// and passes the trace on to the receiver.
+func (p *WebhookProvider) deliver(ctx context.Context, d model.DbWebhookDelivery) model.DbWebhookAttempt {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(d.TraceContext))
+	ctx, span := otel.Tracer("project").Start(ctx, "WebhookDelivery",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("webhook.id", d.WebhookID.String()),
+			attribute.String("webhook.delivery_id", d.ID.String()),
+			attribute.String("webhook.event", d.EventType),
+			attribute.Int("webhook.attempt", d.Attempts+1),
+		),
+	)
+	defer span.End()
+
+	attempt := model.DbWebhookAttempt{DeliveryID: d.ID, Status: model.WebhookDeliveryDelivered, NextAttemptAt: time.Now()}
+	status, err := p.post(ctx, d)
+	if status != 0 {
+		attempt.ResponseStatus = &status
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+	}
+	if err == nil {
+		return attempt
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, "webhook delivery failed")
+	msg := err.Error()
+	attempt.Error = &msg
+	if d.Attempts+1 >= p.retry.MaxAttempts {
+		attempt.Status = model.WebhookDeliveryDead
+		return attempt
+	}
+	attempt.Status = model.WebhookDeliveryPending
+	attempt.NextAttemptAt = time.Now().Add(p.retry.delay(d.Attempts + 1))
+	return attempt
+}
+
+// post sends signed payload, any response but 2xx is a failure, redirects included
+func (p *WebhookProvider) post(ctx context.Context, d model.DbWebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, d.EventType)
+	req.Header.Set(WebhookDeliveryHeader, d.ID.String())
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(d.Secret, timestamp, d.Payload))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// the body is not read, the delivery log keeps only the status
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.Errorf("receiver responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhook returns value of WebhookSignatureHeader for body sent at timestamp
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
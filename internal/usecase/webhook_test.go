@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Rolan335/project/internal/model"
+	"github.com/Rolan335/project/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWebhookRetry_delay(t *testing.T) {
+	retry := WebhookRetry{MaxAttempts: 10, Backoff: time.Second, MaxBackoff: 10 * time.Second}
+	testCases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 5, want: 10 * time.Second},
+		{attempts: 100, want: 10 * time.Second},
+	}
+	for _, tt := range testCases {
+		t.Run(strconv.Itoa(tt.attempts), func(t *testing.T) {
+			assert.Equal(t, tt.want, retry.delay(tt.attempts))
+		})
+	}
+}
+
+func TestWebhookProvider_Send(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repository := mocks.NewMockWebhookRepository(ctrl)
+	provider := NewWebhookProvider(repository, time.Second, WebhookRetry{})
+
+	blogID := uuid.New()
+	post, _ := json.Marshal(model.DbPost{ID: uuid.New(), BlogID: blogID})
+	events := []model.DbOutboxEvent{
+		{ID: 1, Type: model.EventBlogUpdated, AggregateID: blogID, Payload: json.RawMessage(`{}`)},
+		{ID: 2, Type: model.EventPostCreated, AggregateID: uuid.New(), Payload: post},
+	}
+	for _, event := range events {
+		body, _ := json.Marshal(event)
+		repository.EXPECT().AddDeliveries(gomock.Any(), blogID, event, body).Return(int64(1), nil)
+		assert.NoError(t, provider.Send(context.Background(), event))
+	}
+}
+
+// receiver is a webhook endpoint which checks signatures and answers with status
+type receiver struct {
+	t         *testing.T
+	secret    string
+	status    int
+	requests  int
+	traceID   trace.TraceID
+	eventType string
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.requests++
+	body, _ := io.ReadAll(r.Body)
+	timestamp, err := strconv.ParseInt(r.Header.Get(WebhookTimestampHeader), 10, 64)
+	assert.NoError(rc.t, err)
+	assert.Equal(rc.t, SignWebhook(rc.secret, timestamp, body), r.Header.Get(WebhookSignatureHeader))
+	assert.Equal(rc.t, "application/json", r.Header.Get("Content-Type"))
+	rc.eventType = r.Header.Get(WebhookEventHeader)
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+	rc.traceID = trace.SpanContextFromContext(ctx).TraceID()
+	w.WriteHeader(rc.status)
+}
+
+func TestWebhookProvider_deliverDue(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	retry := WebhookRetry{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: time.Hour}
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	wantTraceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+
+	testCases := []struct {
+		name         string
+		status       int
+		attempts     int
+		wantStatus   string
+		wantRetryErr bool
+	}{
+		{name: "delivered", status: http.StatusNoContent, wantStatus: model.WebhookDeliveryDelivered},
+		{name: "retried", status: http.StatusInternalServerError, attempts: 1, wantStatus: model.WebhookDeliveryPending, wantRetryErr: true},
+		{name: "dead", status: http.StatusBadGateway, attempts: 2, wantStatus: model.WebhookDeliveryDead, wantRetryErr: true},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repository := mocks.NewMockWebhookRepository(ctrl)
+			provider := NewWebhookProvider(repository, time.Second, retry)
+			// the test receiver listens on loopback
+			provider.client = newWebhookClient(time.Second, func(netip.Addr) bool { return true })
+
+			rc := &receiver{t: t, secret: "whsec_test", status: tt.status}
+			server := httptest.NewServer(rc)
+			defer server.Close()
+
+			delivery := model.DbWebhookDelivery{
+				ID:           uuid.New(),
+				WebhookID:    uuid.New(),
+				EventID:      1,
+				EventType:    model.EventPostCreated,
+				Payload:      json.RawMessage(`{"id":1}`),
+				TraceContext: map[string]string{"traceparent": traceParent},
+				Status:       model.WebhookDeliveryPending,
+				Attempts:     tt.attempts,
+				URL:          server.URL,
+				Secret:       rc.secret,
+			}
+			var saved model.DbWebhookAttempt
+			repository.EXPECT().ClaimDeliveries(gomock.Any(), 10, gomock.Any()).Return([]model.DbWebhookDelivery{delivery}, nil)
+			repository.EXPECT().SaveAttempt(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, attempt model.DbWebhookAttempt) error {
+				saved = attempt
+				return nil
+			})
+
+			start := time.Now()
+			provider.deliverDue(context.Background(), 10)
+
+			assert.Equal(t, 1, rc.requests)
+			assert.Equal(t, model.EventPostCreated, rc.eventType)
+			assert.Equal(t, wantTraceID, rc.traceID)
+			assert.Equal(t, delivery.ID, saved.DeliveryID)
+			assert.Equal(t, tt.wantStatus, saved.Status)
+			if assert.NotNil(t, saved.ResponseStatus) {
+				assert.Equal(t, tt.status, *saved.ResponseStatus)
+			}
+			assert.Equal(t, tt.wantRetryErr, saved.Error != nil)
+			if tt.wantStatus == model.WebhookDeliveryPending {
+				assert.WithinDuration(t, start.Add(retry.delay(tt.attempts+1)), saved.NextAttemptAt, 5*time.Second)
+			}
+		})
+	}
+}
+
+func TestPublicAddr(t *testing.T) {
+	testCases := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "93.184.216.34", want: true},
+		{addr: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{addr: "127.0.0.1"},
+		{addr: "::1"},
+		{addr: "10.0.0.1"},
+		{addr: "172.16.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "fd00::1"},
+		{addr: "169.254.169.254"},
+		{addr: "fe80::1"},
+		{addr: "0.0.0.0"},
+		{addr: "::ffff:127.0.0.1"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.want, publicAddr(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestWebhookProvider_post(t *testing.T) {
+	rc := &receiver{t: t, secret: "whsec_test", status: http.StatusNoContent}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(server.URL, http.StatusFound))
+	defer redirect.Close()
+	delivery := model.DbWebhookDelivery{ID: uuid.New(), Payload: json.RawMessage(`{}`), Secret: rc.secret}
+
+	t.Run("internal address", func(t *testing.T) {
+		provider := NewWebhookProvider(nil, time.Second, WebhookRetry{})
+		delivery.URL = server.URL
+		status, err := provider.post(context.Background(), delivery)
+		assert.ErrorContains(t, err, "is not allowed")
+		assert.Zero(t, status)
+		assert.Zero(t, rc.requests)
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		provider := NewWebhookProvider(nil, time.Second, WebhookRetry{})
+		provider.client = newWebhookClient(time.Second, func(netip.Addr) bool { return true })
+		delivery.URL = redirect.URL
+		status, err := provider.post(context.Background(), delivery)
+		assert.EqualError(t, err, "receiver responded 302")
+		assert.Equal(t, http.StatusFound, status)
+		assert.Zero(t, rc.requests)
+	})
+}
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS posts_blogs_id_created_at_id_idx ON posts(blogs_id, created_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS posts_blogs_id_created_at_id_idx;
+-- +goose StatementEnd
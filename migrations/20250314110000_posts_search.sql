@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce("text", '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS posts_search_idx ON posts USING GIN(search);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS posts_search_idx;
+ALTER TABLE posts DROP COLUMN IF EXISTS search;
+-- +goose StatementEnd
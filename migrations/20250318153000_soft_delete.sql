@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS blogs_deleted_at_idx ON blogs(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS posts_deleted_at_idx ON posts(deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS posts_deleted_at_idx;
+DROP INDEX IF EXISTS blogs_deleted_at_idx;
+DELETE FROM posts WHERE deleted_at IS NOT NULL OR blogs_id IN (SELECT id FROM blogs WHERE deleted_at IS NOT NULL);
+DELETE FROM blogs WHERE deleted_at IS NOT NULL;
+ALTER TABLE posts DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE blogs DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS post_revisions(
+    posts_id UUID NOT NULL,
+    revision INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    "text" TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (posts_id, revision),
+    FOREIGN KEY (posts_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+-- current state of existing posts becomes their first revision
+INSERT INTO post_revisions(posts_id, revision, title, "text", created_at)
+SELECT id, 1, title, "text", coalesce(created_at, CURRENT_TIMESTAMP) FROM posts
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS post_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tags(
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS post_tags(
+    posts_id UUID NOT NULL,
+    tags_id INTEGER NOT NULL,
+    PRIMARY KEY (posts_id, tags_id),
+    FOREIGN KEY (posts_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (tags_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS post_tags_tags_id_idx ON post_tags(tags_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS post_tags;
+DROP TABLE IF EXISTS tags;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS comments(
+    id UUID PRIMARY KEY NOT NULL,
+    posts_id UUID NOT NULL,
+    parent_id UUID,
+    users_id UUID NOT NULL,
+    "text" TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    FOREIGN KEY (posts_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (users_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS comments_posts_id_parent_id_created_at_id_idx ON comments(posts_id, parent_id, created_at, id);
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments(parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS comments;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS slug TEXT;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS slug TEXT;
+-- existing rows have no name based slug yet, id is unique and keeps old links working
+UPDATE blogs SET slug = id::text WHERE slug IS NULL;
+UPDATE posts SET slug = id::text WHERE slug IS NULL;
+ALTER TABLE blogs ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE posts ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS blogs_slug_idx ON blogs(slug);
+CREATE UNIQUE INDEX IF NOT EXISTS posts_blogs_id_slug_idx ON posts(blogs_id, slug);
+
+-- old slugs of renamed blogs and posts, they redirect to the current one
+CREATE TABLE IF NOT EXISTS blog_slug_aliases(
+    slug TEXT PRIMARY KEY NOT NULL,
+    blogs_id UUID NOT NULL,
+    FOREIGN KEY (blogs_id) REFERENCES blogs(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS post_slug_aliases(
+    blogs_id UUID NOT NULL,
+    slug TEXT NOT NULL,
+    posts_id UUID NOT NULL,
+    PRIMARY KEY (blogs_id, slug),
+    FOREIGN KEY (blogs_id) REFERENCES blogs(id) ON DELETE CASCADE,
+    FOREIGN KEY (posts_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS post_slug_aliases;
+DROP TABLE IF EXISTS blog_slug_aliases;
+DROP INDEX IF EXISTS posts_blogs_id_slug_idx;
+DROP INDEX IF EXISTS blogs_slug_idx;
+ALTER TABLE posts DROP COLUMN IF EXISTS slug;
+ALTER TABLE blogs DROP COLUMN IF EXISTS slug;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'scheduled', 'published'));
+-- publish_at is the planned time for scheduled posts and the time of publishing for published ones
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+UPDATE posts SET publish_at = created_at WHERE publish_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS posts_scheduled_publish_at_idx ON posts(publish_at) WHERE status = 'scheduled';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS posts_scheduled_publish_at_idx;
+ALTER TABLE posts DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
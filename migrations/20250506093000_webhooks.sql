@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+-- trace context of the request that made the event, so a webhook delivery continues its trace
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS trace_context JSONB NOT NULL DEFAULT '{}';
+
+CREATE TABLE IF NOT EXISTS webhooks(
+    id UUID PRIMARY KEY NOT NULL,
+    blogs_id UUID NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    events TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (blogs_id) REFERENCES blogs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_blogs_id_idx ON webhooks(blogs_id);
+
+-- one delivery per webhook and outbox event, the relay may pass an event more than once
+CREATE TABLE IF NOT EXISTS webhook_deliveries(
+    id UUID PRIMARY KEY NOT NULL,
+    webhooks_id UUID NOT NULL,
+    event_id BIGINT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    trace_context JSONB NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+    response_status INT,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMP,
+    UNIQUE (webhooks_id, event_id),
+    FOREIGN KEY (webhooks_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_log_idx ON webhook_deliveries(webhooks_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+ALTER TABLE outbox DROP COLUMN IF EXISTS trace_context;
+-- +goose StatementEnd
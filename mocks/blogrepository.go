@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/Rolan335/project/internal/model"
 	uuid "github.com/google/uuid"
@@ -43,10 +44,10 @@ func (m *MockBlogRepository) EXPECT() *MockBlogRepositoryMockRecorder {
 }
 
 // AddBlog mocks base method.
-func (m *MockBlogRepository) AddBlog(ctx context.Context, blog model.DbBlog) (uuid.UUID, error) {
+func (m *MockBlogRepository) AddBlog(ctx context.Context, blog model.DbBlog) (model.DbBlog, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddBlog", ctx, blog)
-	ret0, _ := ret[0].(uuid.UUID)
+	ret0, _ := ret[0].(model.DbBlog)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -58,10 +59,10 @@ func (mr *MockBlogRepositoryMockRecorder) AddBlog(ctx, blog any) *gomock.Call {
 }
 
 // AddPost mocks base method.
-func (m *MockBlogRepository) AddPost(ctx context.Context, post model.DbPost) (uuid.UUID, error) {
+func (m *MockBlogRepository) AddPost(ctx context.Context, post model.DbPost) (model.DbPost, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddPost", ctx, post)
-	ret0, _ := ret[0].(uuid.UUID)
+	ret0, _ := ret[0].(model.DbPost)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -115,6 +116,96 @@ func (mr *MockBlogRepositoryMockRecorder) GetBlog(ctx, blogID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlog", reflect.TypeOf((*MockBlogRepository)(nil).GetBlog), ctx, blogID)
 }
 
+// GetBlogBySlug mocks base method.
+func (m *MockBlogRepository) GetBlogBySlug(ctx context.Context, slug string) (model.DbBlog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlogBySlug", ctx, slug)
+	ret0, _ := ret[0].(model.DbBlog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlogBySlug indicates an expected call of GetBlogBySlug.
+func (mr *MockBlogRepositoryMockRecorder) GetBlogBySlug(ctx, slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlogBySlug", reflect.TypeOf((*MockBlogRepository)(nil).GetBlogBySlug), ctx, slug)
+}
+
+// GetBlogOwner mocks base method.
+func (m *MockBlogRepository) GetBlogOwner(ctx context.Context, blogID uuid.UUID) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlogOwner", ctx, blogID)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlogOwner indicates an expected call of GetBlogOwner.
+func (mr *MockBlogRepositoryMockRecorder) GetBlogOwner(ctx, blogID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlogOwner", reflect.TypeOf((*MockBlogRepository)(nil).GetBlogOwner), ctx, blogID)
+}
+
+// GetBlogTags mocks base method.
+func (m *MockBlogRepository) GetBlogTags(ctx context.Context, blogID uuid.UUID) ([]model.DbTagCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlogTags", ctx, blogID)
+	ret0, _ := ret[0].([]model.DbTagCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlogTags indicates an expected call of GetBlogTags.
+func (mr *MockBlogRepositoryMockRecorder) GetBlogTags(ctx, blogID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlogTags", reflect.TypeOf((*MockBlogRepository)(nil).GetBlogTags), ctx, blogID)
+}
+
+// GetBlogsPosts mocks base method.
+func (m *MockBlogRepository) GetBlogsPosts(ctx context.Context, filter model.DbBlogsPostsFilter) ([]model.DbPost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlogsPosts", ctx, filter)
+	ret0, _ := ret[0].([]model.DbPost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlogsPosts indicates an expected call of GetBlogsPosts.
+func (mr *MockBlogRepositoryMockRecorder) GetBlogsPosts(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlogsPosts", reflect.TypeOf((*MockBlogRepository)(nil).GetBlogsPosts), ctx, filter)
+}
+
+// GetDeletedBlogs mocks base method.
+func (m *MockBlogRepository) GetDeletedBlogs(ctx context.Context, userID uuid.UUID) ([]model.DbBlog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBlogs", ctx, userID)
+	ret0, _ := ret[0].([]model.DbBlog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedBlogs indicates an expected call of GetDeletedBlogs.
+func (mr *MockBlogRepositoryMockRecorder) GetDeletedBlogs(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBlogs", reflect.TypeOf((*MockBlogRepository)(nil).GetDeletedBlogs), ctx, userID)
+}
+
+// GetDeletedPosts mocks base method.
+func (m *MockBlogRepository) GetDeletedPosts(ctx context.Context, blogID uuid.UUID) ([]model.DbPost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedPosts", ctx, blogID)
+	ret0, _ := ret[0].([]model.DbPost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedPosts indicates an expected call of GetDeletedPosts.
+func (mr *MockBlogRepositoryMockRecorder) GetDeletedPosts(ctx, blogID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedPosts", reflect.TypeOf((*MockBlogRepository)(nil).GetDeletedPosts), ctx, blogID)
+}
+
 // GetPost mocks base method.
 func (m *MockBlogRepository) GetPost(ctx context.Context, postID uuid.UUID) (model.DbPost, error) {
 	m.ctrl.T.Helper()
@@ -130,19 +221,167 @@ func (mr *MockBlogRepositoryMockRecorder) GetPost(ctx, postID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPost", reflect.TypeOf((*MockBlogRepository)(nil).GetPost), ctx, postID)
 }
 
+// GetPostBySlug mocks base method.
+func (m *MockBlogRepository) GetPostBySlug(ctx context.Context, blogID uuid.UUID, slug string) (model.DbPost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostBySlug", ctx, blogID, slug)
+	ret0, _ := ret[0].(model.DbPost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostBySlug indicates an expected call of GetPostBySlug.
+func (mr *MockBlogRepositoryMockRecorder) GetPostBySlug(ctx, blogID, slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostBySlug", reflect.TypeOf((*MockBlogRepository)(nil).GetPostBySlug), ctx, blogID, slug)
+}
+
+// GetPostRevision mocks base method.
+func (m *MockBlogRepository) GetPostRevision(ctx context.Context, postID, blogID uuid.UUID, revision int) (model.DbPostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostRevision", ctx, postID, blogID, revision)
+	ret0, _ := ret[0].(model.DbPostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostRevision indicates an expected call of GetPostRevision.
+func (mr *MockBlogRepositoryMockRecorder) GetPostRevision(ctx, postID, blogID, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostRevision", reflect.TypeOf((*MockBlogRepository)(nil).GetPostRevision), ctx, postID, blogID, revision)
+}
+
+// GetPostRevisions mocks base method.
+func (m *MockBlogRepository) GetPostRevisions(ctx context.Context, postID, blogID uuid.UUID) ([]model.DbPostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostRevisions", ctx, postID, blogID)
+	ret0, _ := ret[0].([]model.DbPostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostRevisions indicates an expected call of GetPostRevisions.
+func (mr *MockBlogRepositoryMockRecorder) GetPostRevisions(ctx, postID, blogID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostRevisions", reflect.TypeOf((*MockBlogRepository)(nil).GetPostRevisions), ctx, postID, blogID)
+}
+
 // GetPosts mocks base method.
-func (m *MockBlogRepository) GetPosts(ctx context.Context, BlogID uuid.UUID) ([]model.DbPost, error) {
+func (m *MockBlogRepository) GetPosts(ctx context.Context, filter model.DbPostsFilter) ([]model.DbPost, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPosts", ctx, BlogID)
+	ret := m.ctrl.Call(m, "GetPosts", ctx, filter)
 	ret0, _ := ret[0].([]model.DbPost)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPosts indicates an expected call of GetPosts.
-func (mr *MockBlogRepositoryMockRecorder) GetPosts(ctx, BlogID any) *gomock.Call {
+func (mr *MockBlogRepositoryMockRecorder) GetPosts(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockBlogRepository)(nil).GetPosts), ctx, BlogID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockBlogRepository)(nil).GetPosts), ctx, filter)
+}
+
+// ImportPosts mocks base method.
+func (m *MockBlogRepository) ImportPosts(ctx context.Context, blogID uuid.UUID, next func() ([]model.DbPost, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportPosts", ctx, blogID, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportPosts indicates an expected call of ImportPosts.
+func (mr *MockBlogRepositoryMockRecorder) ImportPosts(ctx, blogID, next any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportPosts", reflect.TypeOf((*MockBlogRepository)(nil).ImportPosts), ctx, blogID, next)
+}
+
+// PublishScheduled mocks base method.
+func (m *MockBlogRepository) PublishScheduled(ctx context.Context, limit int) ([]model.DbPost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishScheduled", ctx, limit)
+	ret0, _ := ret[0].([]model.DbPost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishScheduled indicates an expected call of PublishScheduled.
+func (mr *MockBlogRepositoryMockRecorder) PublishScheduled(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishScheduled", reflect.TypeOf((*MockBlogRepository)(nil).PublishScheduled), ctx, limit)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockBlogRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, olderThan)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockBlogRepositoryMockRecorder) PurgeDeleted(ctx, olderThan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockBlogRepository)(nil).PurgeDeleted), ctx, olderThan)
+}
+
+// RestoreBlog mocks base method.
+func (m *MockBlogRepository) RestoreBlog(ctx context.Context, blogID uuid.UUID) (model.DbBlog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBlog", ctx, blogID)
+	ret0, _ := ret[0].(model.DbBlog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBlog indicates an expected call of RestoreBlog.
+func (mr *MockBlogRepositoryMockRecorder) RestoreBlog(ctx, blogID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBlog", reflect.TypeOf((*MockBlogRepository)(nil).RestoreBlog), ctx, blogID)
+}
+
+// RestorePost mocks base method.
+func (m *MockBlogRepository) RestorePost(ctx context.Context, postID, blogID uuid.UUID) (model.DbPost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestorePost", ctx, postID, blogID)
+	ret0, _ := ret[0].(model.DbPost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestorePost indicates an expected call of RestorePost.
+func (mr *MockBlogRepositoryMockRecorder) RestorePost(ctx, postID, blogID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePost", reflect.TypeOf((*MockBlogRepository)(nil).RestorePost), ctx, postID, blogID)
+}
+
+// SearchPosts mocks base method.
+func (m *MockBlogRepository) SearchPosts(ctx context.Context, search model.DbPostsSearch) ([]model.DbPostSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPosts", ctx, search)
+	ret0, _ := ret[0].([]model.DbPostSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchPosts indicates an expected call of SearchPosts.
+func (mr *MockBlogRepositoryMockRecorder) SearchPosts(ctx, search any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPosts", reflect.TypeOf((*MockBlogRepository)(nil).SearchPosts), ctx, search)
+}
+
+// StreamPosts mocks base method.
+func (m *MockBlogRepository) StreamPosts(ctx context.Context, blogID uuid.UUID, fn func(model.DbPost) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamPosts", ctx, blogID, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamPosts indicates an expected call of StreamPosts.
+func (mr *MockBlogRepositoryMockRecorder) StreamPosts(ctx, blogID, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamPosts", reflect.TypeOf((*MockBlogRepository)(nil).StreamPosts), ctx, blogID, fn)
 }
 
 // UpdateBlog mocks base method.
@@ -174,3 +413,577 @@ func (mr *MockBlogRepositoryMockRecorder) UpdatePost(ctx, post any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockBlogRepository)(nil).UpdatePost), ctx, post)
 }
+
+// MockCommentRepository is a mock of CommentRepository interface.
+type MockCommentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCommentRepositoryMockRecorder is the mock recorder for MockCommentRepository.
+type MockCommentRepositoryMockRecorder struct {
+	mock *MockCommentRepository
+}
+
+// NewMockCommentRepository creates a new mock instance.
+func NewMockCommentRepository(ctrl *gomock.Controller) *MockCommentRepository {
+	mock := &MockCommentRepository{ctrl: ctrl}
+	mock.recorder = &MockCommentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommentRepository) EXPECT() *MockCommentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddComment mocks base method.
+func (m *MockCommentRepository) AddComment(ctx context.Context, blogID uuid.UUID, comment model.DbComment) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddComment", ctx, blogID, comment)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddComment indicates an expected call of AddComment.
+func (mr *MockCommentRepositoryMockRecorder) AddComment(ctx, blogID, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockCommentRepository)(nil).AddComment), ctx, blogID, comment)
+}
+
+// DeleteComment mocks base method.
+func (m *MockCommentRepository) DeleteComment(ctx context.Context, commentID, postID, blogID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, commentID, postID, blogID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockCommentRepositoryMockRecorder) DeleteComment(ctx, commentID, postID, blogID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockCommentRepository)(nil).DeleteComment), ctx, commentID, postID, blogID)
+}
+
+// GetCommentOwners mocks base method.
+func (m *MockCommentRepository) GetCommentOwners(ctx context.Context, commentID, postID, blogID uuid.UUID) (model.DbCommentOwners, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentOwners", ctx, commentID, postID, blogID)
+	ret0, _ := ret[0].(model.DbCommentOwners)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentOwners indicates an expected call of GetCommentOwners.
+func (mr *MockCommentRepositoryMockRecorder) GetCommentOwners(ctx, commentID, postID, blogID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentOwners", reflect.TypeOf((*MockCommentRepository)(nil).GetCommentOwners), ctx, commentID, postID, blogID)
+}
+
+// GetComments mocks base method.
+func (m *MockCommentRepository) GetComments(ctx context.Context, filter model.DbCommentsFilter) ([]model.DbComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComments", ctx, filter)
+	ret0, _ := ret[0].([]model.DbComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComments indicates an expected call of GetComments.
+func (mr *MockCommentRepositoryMockRecorder) GetComments(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComments", reflect.TypeOf((*MockCommentRepository)(nil).GetComments), ctx, filter)
+}
+
+// GetPostAccess mocks base method.
+func (m *MockCommentRepository) GetPostAccess(ctx context.Context, postID, blogID uuid.UUID) (model.DbPostAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostAccess", ctx, postID, blogID)
+	ret0, _ := ret[0].(model.DbPostAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostAccess indicates an expected call of GetPostAccess.
+func (mr *MockCommentRepositoryMockRecorder) GetPostAccess(ctx, postID, blogID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostAccess", reflect.TypeOf((*MockCommentRepository)(nil).GetPostAccess), ctx, postID, blogID)
+}
+
+// UpdateComment mocks base method.
+func (m *MockCommentRepository) UpdateComment(ctx context.Context, blogID uuid.UUID, comment model.DbComment) (model.DbComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateComment", ctx, blogID, comment)
+	ret0, _ := ret[0].(model.DbComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateComment indicates an expected call of UpdateComment.
+func (mr *MockCommentRepositoryMockRecorder) UpdateComment(ctx, blogID, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComment", reflect.TypeOf((*MockCommentRepository)(nil).UpdateComment), ctx, blogID, comment)
+}
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddUser mocks base method.
+func (m *MockUserRepository) AddUser(ctx context.Context, user model.DbUser) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUser", ctx, user)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddUser indicates an expected call of AddUser.
+func (mr *MockUserRepositoryMockRecorder) AddUser(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockUserRepository)(nil).AddUser), ctx, user)
+}
+
+// GetUser mocks base method.
+func (m *MockUserRepository) GetUser(ctx context.Context, userID uuid.UUID) (model.DbUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, userID)
+	ret0, _ := ret[0].(model.DbUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockUserRepositoryMockRecorder) GetUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserRepository)(nil).GetUser), ctx, userID)
+}
+
+// GetUserBlogs mocks base method.
+func (m *MockUserRepository) GetUserBlogs(ctx context.Context, userID uuid.UUID) ([]model.DbBlog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserBlogs", ctx, userID)
+	ret0, _ := ret[0].([]model.DbBlog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserBlogs indicates an expected call of GetUserBlogs.
+func (mr *MockUserRepositoryMockRecorder) GetUserBlogs(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBlogs", reflect.TypeOf((*MockUserRepository)(nil).GetUserBlogs), ctx, userID)
+}
+
+// UpdateUser mocks base method.
+func (m *MockUserRepository) UpdateUser(ctx context.Context, user model.DbUser) (model.DbUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, user)
+	ret0, _ := ret[0].(model.DbUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUserRepositoryMockRecorder) UpdateUser(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserRepository)(nil).UpdateUser), ctx, user)
+}
+
+// MockAPIKeyRepository is a mock of APIKeyRepository interface.
+type MockAPIKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIKeyRepositoryMockRecorder is the mock recorder for MockAPIKeyRepository.
+type MockAPIKeyRepositoryMockRecorder struct {
+	mock *MockAPIKeyRepository
+}
+
+// NewMockAPIKeyRepository creates a new mock instance.
+func NewMockAPIKeyRepository(ctrl *gomock.Controller) *MockAPIKeyRepository {
+	mock := &MockAPIKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyRepository) EXPECT() *MockAPIKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddAPIKey mocks base method.
+func (m *MockAPIKeyRepository) AddAPIKey(ctx context.Context, key model.DbAPIKey) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAPIKey", ctx, key)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAPIKey indicates an expected call of AddAPIKey.
+func (mr *MockAPIKeyRepositoryMockRecorder) AddAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAPIKey", reflect.TypeOf((*MockAPIKeyRepository)(nil).AddAPIKey), ctx, key)
+}
+
+// GetAPIKeyByPrefix mocks base method.
+func (m *MockAPIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.DbAPIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByPrefix", ctx, prefix)
+	ret0, _ := ret[0].(model.DbAPIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByPrefix indicates an expected call of GetAPIKeyByPrefix.
+func (mr *MockAPIKeyRepositoryMockRecorder) GetAPIKeyByPrefix(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByPrefix", reflect.TypeOf((*MockAPIKeyRepository)(nil).GetAPIKeyByPrefix), ctx, prefix)
+}
+
+// GetAPIKeys mocks base method.
+func (m *MockAPIKeyRepository) GetAPIKeys(ctx context.Context, userID uuid.UUID) ([]model.DbAPIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeys", ctx, userID)
+	ret0, _ := ret[0].([]model.DbAPIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeys indicates an expected call of GetAPIKeys.
+func (mr *MockAPIKeyRepositoryMockRecorder) GetAPIKeys(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeys", reflect.TypeOf((*MockAPIKeyRepository)(nil).GetAPIKeys), ctx, userID)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockAPIKeyRepository) RevokeAPIKey(ctx context.Context, keyID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, keyID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockAPIKeyRepositoryMockRecorder) RevokeAPIKey(ctx, keyID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockAPIKeyRepository)(nil).RevokeAPIKey), ctx, keyID, userID)
+}
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteSent mocks base method.
+func (m *MockOutboxRepository) DeleteSent(ctx context.Context, olderThan time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSent", ctx, olderThan)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSent indicates an expected call of DeleteSent.
+func (mr *MockOutboxRepositoryMockRecorder) DeleteSent(ctx, olderThan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSent", reflect.TypeOf((*MockOutboxRepository)(nil).DeleteSent), ctx, olderThan)
+}
+
+// RelayEvents mocks base method.
+func (m *MockOutboxRepository) RelayEvents(ctx context.Context, limit int, fn func(model.DbOutboxEvent) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelayEvents", ctx, limit, fn)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RelayEvents indicates an expected call of RelayEvents.
+func (mr *MockOutboxRepositoryMockRecorder) RelayEvents(ctx, limit, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayEvents", reflect.TypeOf((*MockOutboxRepository)(nil).RelayEvents), ctx, limit, fn)
+}
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddDeliveries mocks base method.
+func (m *MockWebhookRepository) AddDeliveries(ctx context.Context, blogID uuid.UUID, event model.DbOutboxEvent, payload []byte) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeliveries", ctx, blogID, event, payload)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddDeliveries indicates an expected call of AddDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) AddDeliveries(ctx, blogID, event, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).AddDeliveries), ctx, blogID, event, payload)
+}
+
+// AddWebhook mocks base method.
+func (m *MockWebhookRepository) AddWebhook(ctx context.Context, hook model.DbWebhook) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWebhook", ctx, hook)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddWebhook indicates an expected call of AddWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) AddWebhook(ctx, hook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).AddWebhook), ctx, hook)
+}
+
+// ClaimDeliveries mocks base method.
+func (m *MockWebhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.DbWebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]model.DbWebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeliveries indicates an expected call of ClaimDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) ClaimDeliveries(ctx, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).ClaimDeliveries), ctx, limit, lease)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookRepository) DeleteWebhook(ctx context.Context, webhookID, blogID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, webhookID, blogID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) DeleteWebhook(ctx, webhookID, blogID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).DeleteWebhook), ctx, webhookID, blogID)
+}
+
+// GetBlogOwner mocks base method.
+func (m *MockWebhookRepository) GetBlogOwner(ctx context.Context, blogID uuid.UUID) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlogOwner", ctx, blogID)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlogOwner indicates an expected call of GetBlogOwner.
+func (mr *MockWebhookRepositoryMockRecorder) GetBlogOwner(ctx, blogID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlogOwner", reflect.TypeOf((*MockWebhookRepository)(nil).GetBlogOwner), ctx, blogID)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockWebhookRepository) GetDeliveries(ctx context.Context, webhookID, blogID uuid.UUID, limit int) ([]model.DbWebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, webhookID, blogID, limit)
+	ret0, _ := ret[0].([]model.DbWebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) GetDeliveries(ctx, webhookID, blogID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).GetDeliveries), ctx, webhookID, blogID, limit)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockWebhookRepository) GetWebhooks(ctx context.Context, blogID uuid.UUID) ([]model.DbWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx, blogID)
+	ret0, _ := ret[0].([]model.DbWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockWebhookRepositoryMockRecorder) GetWebhooks(ctx, blogID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockWebhookRepository)(nil).GetWebhooks), ctx, blogID)
+}
+
+// Redeliver mocks base method.
+func (m *MockWebhookRepository) Redeliver(ctx context.Context, deliveryID, webhookID, blogID uuid.UUID) (model.DbWebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, deliveryID, webhookID, blogID)
+	ret0, _ := ret[0].(model.DbWebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockWebhookRepositoryMockRecorder) Redeliver(ctx, deliveryID, webhookID, blogID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockWebhookRepository)(nil).Redeliver), ctx, deliveryID, webhookID, blogID)
+}
+
+// SaveAttempt mocks base method.
+func (m *MockWebhookRepository) SaveAttempt(ctx context.Context, attempt model.DbWebhookAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAttempt", ctx, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAttempt indicates an expected call of SaveAttempt.
+func (mr *MockWebhookRepositoryMockRecorder) SaveAttempt(ctx, attempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAttempt", reflect.TypeOf((*MockWebhookRepository)(nil).SaveAttempt), ctx, attempt)
+}
+
+// MockIdempotencyRepository is a mock of IdempotencyRepository interface.
+type MockIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyRepositoryMockRecorder is the mock recorder for MockIdempotencyRepository.
+type MockIdempotencyRepositoryMockRecorder struct {
+	mock *MockIdempotencyRepository
+}
+
+// NewMockIdempotencyRepository creates a new mock instance.
+func NewMockIdempotencyRepository(ctrl *gomock.Controller) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimKey mocks base method.
+func (m *MockIdempotencyRepository) ClaimKey(ctx context.Context, key string, requestHash []byte, ttl time.Duration) (model.DbIdempotencyRecord, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimKey", ctx, key, requestHash, ttl)
+	ret0, _ := ret[0].(model.DbIdempotencyRecord)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ClaimKey indicates an expected call of ClaimKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) ClaimKey(ctx, key, requestHash, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).ClaimKey), ctx, key, requestHash, ttl)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIdempotencyRepositoryMockRecorder) DeleteExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIdempotencyRepository)(nil).DeleteExpired), ctx)
+}
+
+// ReleaseKey mocks base method.
+func (m *MockIdempotencyRepository) ReleaseKey(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseKey indicates an expected call of ReleaseKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) ReleaseKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).ReleaseKey), ctx, key)
+}
+
+// SaveResponse mocks base method.
+func (m *MockIdempotencyRepository) SaveResponse(ctx context.Context, key string, status int, contentType string, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveResponse", ctx, key, status, contentType, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveResponse indicates an expected call of SaveResponse.
+func (mr *MockIdempotencyRepositoryMockRecorder) SaveResponse(ctx, key, status, contentType, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveResponse", reflect.TypeOf((*MockIdempotencyRepository)(nil).SaveResponse), ctx, key, status, contentType, body)
+}
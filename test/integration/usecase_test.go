@@ -3,10 +3,16 @@ package integration
 
 import (
 	"context"
+	"io"
 	"os"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Rolan335/project/internal/apperror"
+	"github.com/Rolan335/project/internal/auth"
+	"github.com/Rolan335/project/internal/broadcast"
 	"github.com/Rolan335/project/internal/model"
 	"github.com/Rolan335/project/internal/repository"
 	"github.com/Rolan335/project/internal/storage/pgconn"
@@ -32,13 +38,33 @@ func TestBlogProvider(t *testing.T) {
 	pg, err := pgconn.GetConn(pgConnStr)
 	a.NoError(err)
 
+	commentprovider := usecase.NewCommentProvider(repository.NewCommentRepo(pg))
+	userprovider := usecase.NewUserProvider(repository.NewUserRepo(pg))
+	outboxrepo := repository.NewOutboxRepo(pg)
 	repository := repository.NewBlogRepo(pg)
 
-	blogprovider := usecase.NewBlogProvider(repository)
+	blogprovider := usecase.NewBlogProvider(repository, broadcast.New(16), nil)
 
 	ctx := context.Background()
 
-	addBlogReq := model.BlogPostReq{UserID: uuid.New(), Name: gofakeit.Name()}
+	// changes are made on behalf of the registered user
+	ctx = auth.WithUserID(ctx, uuid.New())
+
+	var addUserResp model.UserPostResp
+	t.Run("AddUser", func(t *testing.T) {
+		var err error
+		addUserResp, err = userprovider.AddUser(ctx, model.UserPostReq{DisplayName: gofakeit.Name(), Email: gofakeit.Email()})
+		a.NoError(err)
+		userID, _ := auth.UserID(ctx)
+		a.Equal(userID, addUserResp.UserID)
+
+		_, err = userprovider.AddUser(ctx, model.UserPostReq{DisplayName: gofakeit.Name(), Email: gofakeit.Email()})
+		a.ErrorIs(err, apperror.ErrConflict)
+		_, err = userprovider.AddUser(context.Background(), model.UserPostReq{DisplayName: gofakeit.Name(), Email: gofakeit.Email()})
+		a.ErrorIs(err, apperror.ErrUnauthorized)
+	})
+
+	addBlogReq := model.BlogPostReq{Name: gofakeit.Name()}
 	var addBlogResp model.BlogPostResp
 	t.Run("AddBlog", func(t *testing.T) {
 		var err error
@@ -47,6 +73,39 @@ func TestBlogProvider(t *testing.T) {
 		a.IsType(model.BlogPostResp{}, addBlogResp)
 		//returns valid uuid
 		a.NotZero(addBlogResp.BlogID.String())
+
+		//unregistered user
+		_, err = blogprovider.AddBlog(auth.WithUserID(ctx, uuid.New()), model.BlogPostReq{Name: gofakeit.Name()})
+		a.ErrorIs(err, apperror.ErrNotRegistered)
+
+		_, err = blogprovider.AddBlog(context.Background(), model.BlogPostReq{Name: gofakeit.Name()})
+		a.ErrorIs(err, apperror.ErrUnauthorized)
+	})
+
+	t.Run("Users", func(t *testing.T) {
+		user, err := userprovider.GetUser(ctx, model.UserGetReq{UserID: addUserResp.UserID})
+		a.NoError(err)
+		a.NotEmpty(user.Email)
+
+		// email is private
+		stranger, err := userprovider.GetUser(context.Background(), model.UserGetReq{UserID: addUserResp.UserID})
+		a.NoError(err)
+		a.Empty(stranger.Email)
+		a.Equal(user.DisplayName, stranger.DisplayName)
+
+		_, err = userprovider.AddUser(auth.WithUserID(ctx, uuid.New()), model.UserPostReq{DisplayName: gofakeit.Name(), Email: user.Email})
+		a.ErrorIs(err, apperror.ErrConflict)
+
+		updated, err := userprovider.UpdateUser(ctx, model.UserPutReq{UserID: user.UserID, DisplayName: user.DisplayName, Email: user.Email, Bio: "bio"})
+		a.NoError(err)
+		a.Equal("bio", updated.Bio)
+
+		blogs, err := userprovider.GetUserBlogs(ctx, model.UserBlogsGetReq{UserID: user.UserID})
+		a.NoError(err)
+		a.Len(blogs.Blogs, 1)
+
+		_, err = userprovider.GetUserBlogs(ctx, model.UserBlogsGetReq{UserID: uuid.New()})
+		a.ErrorIs(err, apperror.ErrNotFound)
 	})
 
 	getBlogReq := model.BlogGetReq{BlogID: addBlogResp.BlogID}
@@ -64,12 +123,12 @@ func TestBlogProvider(t *testing.T) {
 		a.ErrorIs(err, apperror.ErrNotFound)
 	})
 
-	updateBlogReq := model.BlogPutReq{BlogID: addBlogResp.BlogID, UserID: addBlogReq.UserID, Name: gofakeit.Name()}
+	updateBlogReq := model.BlogPutReq{BlogID: addBlogResp.BlogID, Name: gofakeit.Name()}
 	t.Run("UpdateBlog", func(t *testing.T) {
 		UpdateBlogResp, err := blogprovider.UpdateBlog(ctx, updateBlogReq)
 		a.NoError(err)
 		a.Equal(updateBlogReq.BlogID, UpdateBlogResp.BlogID)
-		a.Equal(updateBlogReq.UserID, UpdateBlogResp.UserID)
+		a.Equal(addUserResp.UserID, UpdateBlogResp.UserID)
 		a.Equal(updateBlogReq.Name, UpdateBlogResp.Name)
 
 		resp, err := blogprovider.GetBlog(ctx, model.BlogGetReq{BlogID: updateBlogReq.BlogID})
@@ -78,6 +137,303 @@ func TestBlogProvider(t *testing.T) {
 		a.Equal(UpdateBlogResp.UserID, resp.UserID)
 		a.Equal(UpdateBlogResp.Name, resp.Name)
 		a.Equal(UpdateBlogResp.CreatedAt, resp.CreatedAt)
+
+		//not an owner
+		_, err = blogprovider.UpdateBlog(auth.WithUserID(ctx, uuid.New()), updateBlogReq)
+		a.ErrorIs(err, apperror.ErrForbidden)
+		err = blogprovider.DeleteBlog(context.Background(), model.BlogDeleteReq{BlogID: updateBlogReq.BlogID})
+		a.ErrorIs(err, apperror.ErrUnauthorized)
+	})
+
+	t.Run("UpdateBlogVersion", func(t *testing.T) {
+		blog, err := blogprovider.GetBlog(ctx, model.BlogGetReq{BlogID: addBlogResp.BlogID})
+		a.NoError(err)
+
+		req := model.BlogPutReq{BlogID: blog.BlogID, Name: gofakeit.Name(), Version: blog.Version}
+		updated, err := blogprovider.UpdateBlog(ctx, req)
+		a.NoError(err)
+		a.Equal(blog.Version+1, updated.Version)
+
+		// second editor still holds the old version
+		_, err = blogprovider.UpdateBlog(ctx, req)
+		a.ErrorIs(err, apperror.ErrVersionMismatch)
+
+		_, err = blogprovider.UpdateBlog(ctx, model.BlogPutReq{BlogID: uuid.New(), Name: gofakeit.Name(), Version: 1})
+		a.ErrorIs(err, apperror.ErrNotFound)
+	})
+
+	t.Run("GetPosts", func(t *testing.T) {
+		postsCount := 3
+		for range postsCount {
+			_, err := blogprovider.AddPost(ctx, model.PostPostReq{BlogID: addBlogResp.BlogID, Title: gofakeit.Name(), Text: gofakeit.Name()})
+			a.NoError(err)
+		}
+		first, err := blogprovider.GetPosts(ctx, model.PostsGetReq{BlogID: addBlogResp.BlogID, Limit: 2})
+		a.NoError(err)
+		a.Len(first.Posts, 2)
+		a.NotEmpty(first.NextCursor)
+
+		second, err := blogprovider.GetPosts(ctx, model.PostsGetReq{BlogID: addBlogResp.BlogID, Limit: 2, Cursor: first.NextCursor})
+		a.NoError(err)
+		a.Len(second.Posts, postsCount-2)
+		a.Empty(second.NextCursor)
+		a.False(second.Posts[0].CreatedAt.After(first.Posts[1].CreatedAt))
+
+		_, err = blogprovider.GetPosts(ctx, model.PostsGetReq{BlogID: addBlogResp.BlogID, Cursor: "invalid"})
+		a.ErrorIs(err, apperror.ErrInvalidCursor)
+	})
+
+	t.Run("SearchPosts", func(t *testing.T) {
+		word := gofakeit.LetterN(16)
+		postResp, err := blogprovider.AddPost(ctx, model.PostPostReq{BlogID: addBlogResp.BlogID, Title: gofakeit.Name(), Text: "text with " + word})
+		a.NoError(err)
+
+		found, err := blogprovider.SearchPosts(ctx, model.PostsSearchReq{Query: word, BlogID: &addBlogResp.BlogID})
+		a.NoError(err)
+		a.Len(found.Posts, 1)
+		a.Equal(postResp.PostID, found.Posts[0].PostID)
+		a.Contains(found.Posts[0].Snippet, "<mark>")
+
+		// words of escaped entities are not matched inside them
+		_, err = blogprovider.AddPost(ctx, model.PostPostReq{BlogID: addBlogResp.BlogID, Title: gofakeit.Name(), Text: "a & b amp"})
+		a.NoError(err)
+		found, err = blogprovider.SearchPosts(ctx, model.PostsSearchReq{Query: "amp", BlogID: &addBlogResp.BlogID})
+		a.NoError(err)
+		a.Len(found.Posts, 1)
+		a.Equal("a &amp; b <mark>amp</mark>", found.Posts[0].Snippet)
+
+		otherBlog := uuid.New()
+		found, err = blogprovider.SearchPosts(ctx, model.PostsSearchReq{Query: word, BlogID: &otherBlog})
+		a.NoError(err)
+		a.Empty(found.Posts)
+	})
+
+	t.Run("ImportPosts", func(t *testing.T) {
+		blog, err := blogprovider.AddBlog(ctx, model.BlogPostReq{Name: gofakeit.Name()})
+		a.NoError(err)
+		lines := []model.PostImportLine{
+			{Line: 1, Post: model.PostPostReq{BlogID: blog.BlogID, Title: gofakeit.Name(), Text: gofakeit.Name(), Tags: []string{"Import"}}},
+			{Line: 2, Post: model.PostPostReq{BlogID: blog.BlogID, Title: gofakeit.Name(), Text: gofakeit.Name()}},
+		}
+		next := func(int) ([]model.PostImportLine, error) {
+			if lines == nil {
+				return nil, io.EOF
+			}
+			batch := lines
+			lines = nil
+			return batch, nil
+		}
+		resp, err := blogprovider.ImportPosts(ctx, model.PostsImportReq{BlogID: blog.BlogID, Atomic: true, Next: next})
+		a.NoError(err)
+		a.Equal(2, resp.Imported)
+
+		posts, err := blogprovider.GetPosts(ctx, model.PostsGetReq{BlogID: blog.BlogID, Tag: "import"})
+		a.NoError(err)
+		a.Len(posts.Posts, 1)
+		revisions, err := blogprovider.GetPostRevisions(ctx, model.PostRevisionsGetReq{BlogID: blog.BlogID, PostID: posts.Posts[0].PostID})
+		a.NoError(err)
+		a.Len(revisions.Revisions, 1)
+	})
+
+	t.Run("Slugs", func(t *testing.T) {
+		name := "Блог " + gofakeit.LetterN(12)
+		first, err := blogprovider.AddBlog(ctx, model.BlogPostReq{Name: name})
+		a.NoError(err)
+		second, err := blogprovider.AddBlog(ctx, model.BlogPostReq{Name: name})
+		a.NoError(err)
+		a.True(strings.HasPrefix(first.Slug, "blog-"))
+		a.Equal(first.Slug+"-2", second.Slug)
+
+		post, err := blogprovider.AddPost(ctx, model.PostPostReq{BlogID: first.BlogID, Title: "Hello, World", Text: gofakeit.Name()})
+		a.NoError(err)
+		a.Equal("hello-world", post.Slug)
+		dup, err := blogprovider.AddPost(ctx, model.PostPostReq{BlogID: first.BlogID, Title: "Hello world!", Text: gofakeit.Name()})
+		a.NoError(err)
+		a.Equal("hello-world-2", dup.Slug)
+
+		// renamed post keeps the old slug as an alias
+		updated, err := blogprovider.UpdatePost(ctx, model.PostPutReq{PostID: post.PostID, BlogID: first.BlogID, Title: "Goodbye", Text: gofakeit.Name()})
+		a.NoError(err)
+		a.Equal("goodbye", updated.Slug)
+		found, err := blogprovider.GetPostBySlug(ctx, model.PostSlugGetReq{BlogSlug: first.Slug, PostSlug: "hello-world"})
+		a.NoError(err)
+		a.Equal(post.PostID, found.Post.PostID)
+		a.Equal("goodbye", found.Post.Slug)
+		// the alias is not given to another post
+		other, err := blogprovider.AddPost(ctx, model.PostPostReq{BlogID: first.BlogID, Title: "Hello World", Text: gofakeit.Name()})
+		a.NoError(err)
+		a.Equal("hello-world-3", other.Slug)
+
+		renamed, err := blogprovider.UpdateBlog(ctx, model.BlogPutReq{BlogID: first.BlogID, Name: gofakeit.LetterN(12)})
+		a.NoError(err)
+		blog, err := blogprovider.GetBlogBySlug(ctx, model.BlogSlugGetReq{Slug: first.Slug})
+		a.NoError(err)
+		a.Equal(renamed.Slug, blog.Slug)
+	})
+
+	t.Run("PostStatus", func(t *testing.T) {
+		blog, err := blogprovider.AddBlog(ctx, model.BlogPostReq{Name: gofakeit.Name()})
+		a.NoError(err)
+		draft, err := blogprovider.AddPost(ctx, model.PostPostReq{BlogID: blog.BlogID, Title: gofakeit.Name(), Text: gofakeit.Name(), Status: model.PostStatusDraft})
+		a.NoError(err)
+		a.Equal(model.PostStatusDraft, draft.Status)
+
+		_, err = blogprovider.GetPost(context.Background(), model.PostGetReq{BlogID: blog.BlogID, PostID: draft.PostID})
+		a.ErrorIs(err, apperror.ErrNotFound)
+		_, err = blogprovider.GetPost(ctx, model.PostGetReq{BlogID: blog.BlogID, PostID: draft.PostID})
+		a.NoError(err)
+		_, err = blogprovider.GetPosts(context.Background(), model.PostsGetReq{BlogID: blog.BlogID})
+		a.NoError(err)
+
+		due := time.Now().Add(-time.Minute)
+		scheduled, err := blogprovider.AddPost(ctx, model.PostPostReq{BlogID: blog.BlogID, Title: gofakeit.Name(), Text: gofakeit.Name(), Status: model.PostStatusScheduled, PublishAt: &due})
+		a.NoError(err)
+		posts, err := blogprovider.GetPosts(context.Background(), model.PostsGetReq{BlogID: blog.BlogID})
+		a.NoError(err)
+		a.Empty(posts.Posts)
+		posts, err = blogprovider.GetPosts(ctx, model.PostsGetReq{BlogID: blog.BlogID})
+		a.NoError(err)
+		a.Len(posts.Posts, 2)
+
+		published, err := repository.PublishScheduled(ctx, 100)
+		a.NoError(err)
+		ids := make([]uuid.UUID, 0, len(published))
+		for _, p := range published {
+			ids = append(ids, p.ID)
+		}
+		a.Contains(ids, scheduled.PostID)
+		posts, err = blogprovider.GetPosts(context.Background(), model.PostsGetReq{BlogID: blog.BlogID})
+		a.NoError(err)
+		a.Len(posts.Posts, 1)
+		a.Equal(scheduled.PostID, posts.Posts[0].PostID)
+	})
+
+	t.Run("GetBlogsPosts", func(t *testing.T) {
+		withDraft, err := blogprovider.AddBlog(ctx, model.BlogPostReq{Name: gofakeit.Name()})
+		a.NoError(err)
+		published, err := blogprovider.AddPost(ctx, model.PostPostReq{BlogID: withDraft.BlogID, Title: gofakeit.Name(), Text: gofakeit.Name()})
+		a.NoError(err)
+		draft, err := blogprovider.AddPost(ctx, model.PostPostReq{BlogID: withDraft.BlogID, Title: gofakeit.Name(), Text: gofakeit.Name(), Status: model.PostStatusDraft})
+		a.NoError(err)
+		other, err := blogprovider.AddBlog(ctx, model.BlogPostReq{Name: gofakeit.Name()})
+		a.NoError(err)
+		otherPost, err := blogprovider.AddPost(ctx, model.PostPostReq{BlogID: other.BlogID, Title: gofakeit.Name(), Text: gofakeit.Name()})
+		a.NoError(err)
+
+		postIDs := func(posts []model.DbPost) map[uuid.UUID][]uuid.UUID {
+			ids := make(map[uuid.UUID][]uuid.UUID)
+			for _, post := range posts {
+				ids[post.BlogID] = append(ids[post.BlogID], post.ID)
+			}
+			return ids
+		}
+		filter := model.DbBlogsPostsFilter{BlogIDs: []uuid.UUID{withDraft.BlogID, other.BlogID}, Limit: 10}
+
+		posts, err := repository.GetBlogsPosts(ctx, filter)
+		a.NoError(err)
+		a.Equal(map[uuid.UUID][]uuid.UUID{withDraft.BlogID: {published.PostID}, other.BlogID: {otherPost.PostID}}, postIDs(posts))
+
+		filter.ViewerID = &addUserResp.UserID
+		posts, err = repository.GetBlogsPosts(ctx, filter)
+		a.NoError(err)
+		a.Equal(map[uuid.UUID][]uuid.UUID{withDraft.BlogID: {draft.PostID, published.PostID}, other.BlogID: {otherPost.PostID}}, postIDs(posts))
+
+		// drafts stay hidden from viewers who do not own the blog
+		stranger := uuid.New()
+		filter.ViewerID = &stranger
+		filter.Limit = 1
+		posts, err = repository.GetBlogsPosts(ctx, filter)
+		a.NoError(err)
+		a.Equal(map[uuid.UUID][]uuid.UUID{withDraft.BlogID: {published.PostID}, other.BlogID: {otherPost.PostID}}, postIDs(posts))
+
+		pages, err := blogprovider.GetBlogsPosts(ctx, model.BlogsPostsGetReq{BlogIDs: []uuid.UUID{withDraft.BlogID}, Limit: 1})
+		a.NoError(err)
+		a.Len(pages.Posts[withDraft.BlogID].Posts, 1)
+		a.NotEmpty(pages.Posts[withDraft.BlogID].NextCursor)
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		tag := gofakeit.LetterN(12)
+		postResp, err := blogprovider.AddPost(ctx, model.PostPostReq{BlogID: addBlogResp.BlogID, Title: gofakeit.Name(), Text: gofakeit.Name(), Tags: []string{tag, "Go"}})
+		a.NoError(err)
+
+		posts, err := blogprovider.GetPosts(ctx, model.PostsGetReq{BlogID: addBlogResp.BlogID, Tag: tag})
+		a.NoError(err)
+		a.Len(posts.Posts, 1)
+		a.Equal(postResp.PostID, posts.Posts[0].PostID)
+		a.ElementsMatch([]string{strings.ToLower(tag), "go"}, posts.Posts[0].Tags)
+
+		tags, err := blogprovider.GetBlogTags(ctx, model.TagsGetReq{BlogID: addBlogResp.BlogID})
+		a.NoError(err)
+		a.Contains(tags.Tags, model.TagResp{Name: strings.ToLower(tag), Count: 1})
+
+		// omitted tags are kept on update, empty list clears them
+		updated, err := blogprovider.UpdatePost(ctx, model.PostPutReq{PostID: postResp.PostID, BlogID: addBlogResp.BlogID, Title: gofakeit.Name(), Text: gofakeit.Name()})
+		a.NoError(err)
+		a.Len(updated.Tags, 2)
+		updated, err = blogprovider.UpdatePost(ctx, model.PostPutReq{PostID: postResp.PostID, BlogID: addBlogResp.BlogID, Title: gofakeit.Name(), Text: gofakeit.Name(), Tags: []string{}})
+		a.NoError(err)
+		a.Empty(updated.Tags)
+	})
+
+	t.Run("Comments", func(t *testing.T) {
+		postResp, err := blogprovider.AddPost(ctx, model.PostPostReq{BlogID: addBlogResp.BlogID, Title: gofakeit.Name(), Text: gofakeit.Name()})
+		a.NoError(err)
+
+		root, err := commentprovider.AddComment(ctx, model.CommentPostReq{BlogID: addBlogResp.BlogID, PostID: postResp.PostID, Text: gofakeit.Name()})
+		a.NoError(err)
+		_, err = commentprovider.AddComment(ctx, model.CommentPostReq{BlogID: addBlogResp.BlogID, PostID: postResp.PostID, ParentID: &root.CommentID, Text: gofakeit.Name()})
+		a.NoError(err)
+		_, err = commentprovider.AddComment(ctx, model.CommentPostReq{BlogID: uuid.New(), PostID: postResp.PostID, Text: gofakeit.Name()})
+		a.ErrorIs(err, apperror.ErrNotFound)
+
+		top, err := commentprovider.GetComments(ctx, model.CommentsGetReq{BlogID: addBlogResp.BlogID, PostID: postResp.PostID})
+		a.NoError(err)
+		a.Len(top.Comments, 1)
+		a.Equal(1, top.Comments[0].RepliesCount)
+
+		replies, err := commentprovider.GetComments(ctx, model.CommentsGetReq{BlogID: addBlogResp.BlogID, PostID: postResp.PostID, ParentID: &root.CommentID})
+		a.NoError(err)
+		a.Len(replies.Comments, 1)
+
+		post, err := blogprovider.GetPost(ctx, model.PostGetReq{BlogID: addBlogResp.BlogID, PostID: postResp.PostID})
+		a.NoError(err)
+		a.Equal(2, post.CommentsCount)
+
+		// replies are deleted together with the parent
+		a.NoError(commentprovider.DeleteComment(ctx, model.CommentDeleteReq{BlogID: addBlogResp.BlogID, PostID: postResp.PostID, CommentID: root.CommentID}))
+		replies, err = commentprovider.GetComments(ctx, model.CommentsGetReq{BlogID: addBlogResp.BlogID, PostID: postResp.PostID, ParentID: &root.CommentID})
+		a.NoError(err)
+		a.Empty(replies.Comments)
+	})
+
+	t.Run("PostRevisions", func(t *testing.T) {
+		addPostReq := model.PostPostReq{BlogID: addBlogResp.BlogID, Title: gofakeit.Name(), Text: "first line\nsecond line"}
+		postResp, err := blogprovider.AddPost(ctx, addPostReq)
+		a.NoError(err)
+		_, err = blogprovider.UpdatePost(ctx, model.PostPutReq{PostID: postResp.PostID, BlogID: addBlogResp.BlogID, Title: gofakeit.Name(), Text: "first line\nchanged line"})
+		a.NoError(err)
+
+		revisions, err := blogprovider.GetPostRevisions(ctx, model.PostRevisionsGetReq{PostID: postResp.PostID, BlogID: addBlogResp.BlogID})
+		a.NoError(err)
+		a.Len(revisions.Revisions, 2)
+		a.Equal(2, revisions.Revisions[0].Revision)
+
+		diff, err := blogprovider.DiffPostRevisions(ctx, model.PostRevisionsDiffReq{PostID: postResp.PostID, BlogID: addBlogResp.BlogID, From: 1, To: 2})
+		a.NoError(err)
+		a.Equal([]model.DiffLine{{Op: "equal", Text: "first line"}, {Op: "delete", Text: "second line"}, {Op: "insert", Text: "changed line"}}, diff.Text)
+
+		restored, err := blogprovider.RestorePostRevision(ctx, model.PostRevisionRestoreReq{PostID: postResp.PostID, BlogID: addBlogResp.BlogID, Revision: 1})
+		a.NoError(err)
+		a.Equal(addPostReq.Title, restored.Title)
+		a.Equal(addPostReq.Text, restored.Text)
+
+		rev, err := blogprovider.GetPostRevision(ctx, model.PostRevisionGetReq{PostID: postResp.PostID, BlogID: addBlogResp.BlogID, Revision: 3})
+		a.NoError(err)
+		a.Equal(addPostReq.Text, rev.Text)
+
+		_, err = blogprovider.GetPostRevision(ctx, model.PostRevisionGetReq{PostID: postResp.PostID, BlogID: uuid.New(), Revision: 1})
+		a.ErrorIs(err, apperror.ErrNotFound)
 	})
 
 	DeleteBlogReq := model.BlogDeleteReq{BlogID: addBlogResp.BlogID}
@@ -91,4 +447,56 @@ func TestBlogProvider(t *testing.T) {
 		err = blogprovider.DeleteBlog(ctx, model.BlogDeleteReq{BlogID: uuid.New()})
 		a.ErrorIs(err, apperror.ErrNotFound)
 	})
+
+	t.Run("RestoreBlog", func(t *testing.T) {
+		trash, err := blogprovider.GetBlogsTrash(ctx, model.BlogsTrashGetReq{})
+		a.NoError(err)
+		a.Len(trash.Blogs, 1)
+		a.NotNil(trash.Blogs[0].DeletedAt)
+
+		restored, err := blogprovider.RestoreBlog(ctx, model.BlogRestoreReq{BlogID: DeleteBlogReq.BlogID})
+		a.NoError(err)
+		a.Equal(DeleteBlogReq.BlogID, restored.BlogID)
+
+		posts, err := blogprovider.GetPosts(ctx, model.PostsGetReq{BlogID: DeleteBlogReq.BlogID})
+		a.NoError(err)
+		a.NotEmpty(posts.Posts)
+
+		_, err = blogprovider.RestoreBlog(ctx, model.BlogRestoreReq{BlogID: DeleteBlogReq.BlogID})
+		a.ErrorIs(err, apperror.ErrNotFound)
+	})
+
+	t.Run("OutboxConcurrentWriters", func(t *testing.T) {
+		// the first writer takes an id and commits after the second one
+		tx, err := pg.Begin(ctx)
+		a.NoError(err)
+		defer tx.Rollback(ctx)
+		_, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock_shared(hashtext('outbox_write'))")
+		a.NoError(err)
+		slowID := uuid.New()
+		_, err = tx.Exec(ctx, `INSERT INTO outbox(event_type, aggregate_id, payload, trace_context)
+			VALUES($1, $2, '{}', '{}')`, model.EventBlogUpdated, slowID)
+		a.NoError(err)
+		blog, err := blogprovider.AddBlog(ctx, model.BlogPostReq{Name: gofakeit.Name()})
+		a.NoError(err)
+
+		relayed := make(chan []uuid.UUID)
+		go func() {
+			var aggregates []uuid.UUID
+			_, err := outboxrepo.RelayEvents(ctx, 10000, func(event model.DbOutboxEvent) error {
+				aggregates = append(aggregates, event.AggregateID)
+				return nil
+			})
+			a.NoError(err)
+			relayed <- aggregates
+		}()
+		time.Sleep(100 * time.Millisecond)
+		a.NoError(tx.Commit(ctx))
+
+		aggregates := <-relayed
+		slow, fast := slices.Index(aggregates, slowID), slices.Index(aggregates, blog.BlogID)
+		a.NotEqual(-1, slow)
+		a.NotEqual(-1, fast)
+		a.Less(slow, fast)
+	})
 }